@@ -20,6 +20,20 @@ CREATE TABLE `nft_collections` (
   UNIQUE KEY `contract_address` (`contract_address`)
 ) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Create syntax for TABLE 'nft_rarities'
+CREATE TABLE `nft_rarities` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `nft_id` bigint unsigned NOT NULL,
+  `collection_id` bigint unsigned NOT NULL,
+  `rarity_score` double NOT NULL,
+  `statistical_rarity` double NOT NULL,
+  `information_content` double NOT NULL,
+  `rank` int unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_nft_rarities_nft_id` (`nft_id`),
+  KEY `idx_collection_rank` (`collection_id`,`rank`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- Create syntax for TABLE 'nft_transfer_events'
 CREATE TABLE `nft_transfer_events` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
@@ -63,8 +77,407 @@ CREATE TABLE `orders` (
   `price` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
   `seller` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
   `status` tinyint unsigned NOT NULL,
+  `invalid` tinyint(1) NOT NULL DEFAULT '0',
+  `invalid_reason` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
   PRIMARY KEY (`id`),
   KEY `idx_orders_nft_contract_address` (`nft_contract_address`),
   KEY `idx_orders_token_id` (`token_id`),
   KEY `idx_orders_seller` (`seller`)
-) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
\ No newline at end of file
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'order_events'
+CREATE TABLE `order_events` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` bigint unsigned NOT NULL,
+  `event_type` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL,
+  `transaction_hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `block_number` bigint unsigned NOT NULL,
+  `block_timestamp` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_order_event` (`order_id`,`event_type`),
+  KEY `idx_order_event_nft` (`nft_contract_address`,`token_id`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'payment_tokens'
+CREATE TABLE `payment_tokens` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `symbol` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `decimals` tinyint unsigned NOT NULL,
+  `supports_permit` tinyint(1) NOT NULL DEFAULT '0',
+  `list_status` tinyint unsigned NOT NULL DEFAULT '0',
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_payment_tokens_address` (`address`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'retry_jobs'
+CREATE TABLE `retry_jobs` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `job_type` enum('nft','collection') COLLATE utf8mb4_unicode_ci NOT NULL,
+  `contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL DEFAULT '0',
+  `status` tinyint unsigned NOT NULL DEFAULT '0',
+  `attempts` int unsigned NOT NULL DEFAULT '0',
+  `next_retry_at` datetime NOT NULL,
+  `last_error` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_job_target` (`job_type`,`contract_address`,`token_id`),
+  KEY `idx_status_next_retry` (`status`,`next_retry_at`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'sales'
+CREATE TABLE `sales` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` bigint unsigned NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL,
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `seller` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `buyer` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `transaction_hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `block_number` bigint unsigned NOT NULL,
+  `block_timestamp` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_sales_order_id` (`order_id`),
+  KEY `idx_sale_nft` (`nft_contract_address`,`token_id`),
+  KEY `idx_sales_seller` (`seller`),
+  KEY `idx_sales_buyer` (`buyer`),
+  KEY `idx_sales_block_timestamp` (`block_timestamp`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'price_candles'
+CREATE TABLE `price_candles` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `interval` enum('1h','1d','1w') COLLATE utf8mb4_unicode_ci NOT NULL,
+  `bucket_start` datetime NOT NULL,
+  `open` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `high` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `low` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `close` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `volume` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `count` int unsigned NOT NULL DEFAULT '0',
+  `average` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `open_at` datetime NOT NULL,
+  `close_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_candle_bucket` (`nft_contract_address`,`token_address`,`interval`,`bucket_start`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'offers'
+CREATE TABLE `offers` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `bidder` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL DEFAULT '0',
+  `collection_wide` tinyint(1) NOT NULL DEFAULT '0',
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nonce` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expiry` datetime NOT NULL,
+  `signature` varchar(132) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` tinyint unsigned NOT NULL DEFAULT '0',
+  `invalid` tinyint(1) NOT NULL DEFAULT '0',
+  `invalid_reason` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_offers_hash` (`hash`),
+  KEY `idx_offers_bidder` (`bidder`),
+  KEY `idx_offer_nft` (`nft_contract_address`,`token_id`),
+  KEY `idx_offers_status` (`status`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'listings'
+CREATE TABLE `listings` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `seller` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL,
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nonce` bigint unsigned NOT NULL,
+  `salt` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expiry` datetime NOT NULL,
+  `signature` varchar(132) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` tinyint unsigned NOT NULL DEFAULT '0',
+  `invalid` tinyint(1) NOT NULL DEFAULT '0',
+  `invalid_reason` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `buyer` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `fill_transaction` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_listings_hash` (`hash`),
+  KEY `idx_listings_seller` (`seller`),
+  KEY `idx_listing_nft` (`nft_contract_address`,`token_id`),
+  KEY `idx_listings_status` (`status`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'signer_nonces'
+CREATE TABLE `signer_nonces` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nonce` bigint unsigned NOT NULL DEFAULT '0',
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_signer_nonces_address` (`address`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'bidder_nonces'
+CREATE TABLE `bidder_nonces` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nonce` bigint unsigned NOT NULL DEFAULT '0',
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_bidder_nonces_address` (`address`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'auctions'
+CREATE TABLE `auctions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `type` enum('english','dutch') COLLATE utf8mb4_unicode_ci NOT NULL,
+  `seller` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL,
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `reserve_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '0',
+  `min_increment` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '0',
+  `start_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '0',
+  `end_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '0',
+  `start_time` datetime NOT NULL,
+  `end_time` datetime NOT NULL,
+  `extension_seconds` int unsigned NOT NULL DEFAULT '0',
+  `salt` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `signature` varchar(132) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` tinyint unsigned NOT NULL DEFAULT '0',
+  `winning_bid_id` bigint unsigned NOT NULL DEFAULT '0',
+  `winning_offer_id` bigint unsigned NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_auctions_hash` (`hash`),
+  KEY `idx_auctions_seller` (`seller`),
+  KEY `idx_auction_nft` (`nft_contract_address`,`token_id`),
+  KEY `idx_auctions_end_time` (`end_time`),
+  KEY `idx_auctions_status` (`status`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'auction_bids'
+CREATE TABLE `auction_bids` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `auction_id` bigint unsigned NOT NULL,
+  `hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `bidder` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nonce` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expiry` datetime NOT NULL,
+  `signature` varchar(132) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_auction_bids_hash` (`hash`),
+  KEY `idx_auction_bids_auction_id` (`auction_id`),
+  KEY `idx_auction_bids_bidder` (`bidder`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'auth_nonces'
+CREATE TABLE `auth_nonces` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `nonce` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_auth_nonces_nonce` (`nonce`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'sessions'
+CREATE TABLE `sessions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `token_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `chain_id` bigint unsigned NOT NULL,
+  `user_agent` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `expires_at` datetime NOT NULL,
+  `revoked_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_sessions_token_id` (`token_id`),
+  KEY `idx_sessions_address` (`address`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'profiles'
+CREATE TABLE `profiles` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `username` varchar(30) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `display_name` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `bio` varchar(500) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `avatar_nft_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `avatar_token_id` bigint unsigned NOT NULL DEFAULT '0',
+  `avatar_image` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `twitter` varchar(15) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `discord` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `website` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_profiles_address` (`address`),
+  UNIQUE KEY `idx_profiles_username` (`username`),
+  KEY `idx_profile_avatar` (`avatar_nft_address`,`avatar_token_id`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'favorites'
+CREATE TABLE `favorites` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_favorite` (`address`,`nft_contract_address`,`token_id`),
+  KEY `idx_favorite_nft` (`nft_contract_address`,`token_id`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'collection_watches'
+CREATE TABLE `collection_watches` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_collection_watch` (`address`,`contract_address`),
+  KEY `idx_collection_watches_contract_address` (`contract_address`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'market_activities'
+CREATE TABLE `market_activities` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `type` enum('listing','price_drop','sale') COLLATE utf8mb4_unicode_ci NOT NULL,
+  `reference` varchar(80) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` bigint unsigned NOT NULL,
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `previous_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `seller` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `buyer` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `transaction_hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_activity_reference` (`type`,`reference`),
+  KEY `idx_activity_collection` (`nft_contract_address`,`created_at`),
+  KEY `idx_activity_nft` (`nft_contract_address`,`token_id`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'webhooks'
+CREATE TABLE `webhooks` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `owner` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `url` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `secret` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `events` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `collection` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_webhooks_owner` (`owner`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'webhook_deliveries'
+CREATE TABLE `webhook_deliveries` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `webhook_id` bigint unsigned NOT NULL,
+  `event_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `event_type` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `payload` mediumtext COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` tinyint unsigned NOT NULL DEFAULT '0',
+  `attempts` int unsigned NOT NULL DEFAULT '0',
+  `next_attempt_at` datetime NOT NULL,
+  `response_status` int NOT NULL DEFAULT '0',
+  `last_error` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `replay_of` bigint unsigned NOT NULL DEFAULT '0',
+  `delivered_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_webhook_deliveries_webhook_id` (`webhook_id`),
+  KEY `idx_webhook_deliveries_event_id` (`event_id`),
+  KEY `idx_delivery_status_next` (`status`,`next_attempt_at`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'alert_rules'
+CREATE TABLE `alert_rules` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `owner` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `kind` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `collection` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `trait_type` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `trait_value` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `channels` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `email` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `last_triggered_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_alert_rules_owner` (`owner`),
+  KEY `idx_alert_rules_kind` (`kind`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'alerts'
+CREATE TABLE `alerts` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `owner` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `rule_id` bigint unsigned NOT NULL,
+  `dedup_key` varchar(191) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `kind` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `title` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `message` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `token_id` bigint unsigned NOT NULL DEFAULT '0',
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `reference` varchar(128) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `throttled` tinyint(1) NOT NULL DEFAULT '0',
+  `read_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_alerts_owner` (`owner`),
+  UNIQUE KEY `idx_alert_rule_dedup` (`rule_id`,`dedup_key`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create syntax for TABLE 'notifications'
+CREATE TABLE `notifications` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `type` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `reference` varchar(191) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `title` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `nft_contract_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `token_id` bigint unsigned NOT NULL DEFAULT '0',
+  `token_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `counterparty` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `transaction_hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `read_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_notification_ref` (`address`,`type`,`reference`),
+  KEY `idx_notification_address` (`address`,`created_at`)
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
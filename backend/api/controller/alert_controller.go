@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"backend/api/middleware"
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlertController struct {
+	useCase *usecase.AlertUseCase
+}
+
+func NewAlertController(useCase *usecase.AlertUseCase) *AlertController {
+	return &AlertController{useCase: useCase}
+}
+
+func (c *AlertController) GetRules(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	rules, err := c.useCase.GetRules(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rules)
+}
+
+func (c *AlertController) CreateRule(ctx *gin.Context) {
+	var req usecase.AlertRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	rule, err := c.useCase.CreateRule(address, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rule)
+}
+
+func (c *AlertController) UpdateRule(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的ID"))
+		return
+	}
+	var req usecase.AlertRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	rule, err := c.useCase.UpdateRule(address, uint(id), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rule)
+}
+
+func (c *AlertController) DeleteRule(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的ID"))
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.DeleteRule(address, uint(id)); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "提醒规则已删除"})
+}
+
+// 提醒收件箱，unread=true 时只返回未读，before 为上一页最后一条提醒的ID
+func (c *AlertController) GetAlerts(ctx *gin.Context) {
+	var beforeID uint64
+	if raw := ctx.Query("before"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			ctx.Error(domain.InvalidParam("before", "无效的before参数"))
+			return
+		}
+		beforeID = parsed
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil {
+		ctx.Error(domain.InvalidParam("limit", "无效的limit参数"))
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	alerts, unread, err := c.useCase.GetAlerts(address, ctx.Query("unread") == "true", uint(beforeID), limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"alerts": alerts, "unread": unread})
+}
+
+func (c *AlertController) MarkRead(ctx *gin.Context) {
+	var req struct {
+		IDs []uint `json:"ids"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	updated, err := c.useCase.MarkRead(address, req.IDs)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"updated": updated})
+}
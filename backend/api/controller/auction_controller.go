@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 推送拍卖状态的最长间隔，用于刷新荷兰式拍卖价格和剩余时间
+const auctionStreamRefreshInterval = 5 * time.Second
+
+type AuctionController struct {
+	useCase *usecase.AuctionUseCase
+}
+
+func NewAuctionController(useCase *usecase.AuctionUseCase) *AuctionController {
+	return &AuctionController{useCase: useCase}
+}
+
+type auctionRequest struct {
+	Seller           string `json:"seller" binding:"required"`
+	NFTAddress       string `json:"nftAddress" binding:"required"`
+	TokenID          uint   `json:"tokenId"`
+	TokenAddress     string `json:"tokenAddress" binding:"required"`
+	Type             string `json:"type" binding:"required"`
+	ReservePrice     string `json:"reservePrice"`
+	MinIncrement     string `json:"minIncrement"`
+	StartPrice       string `json:"startPrice"`
+	EndPrice         string `json:"endPrice"`
+	StartTime        uint64 `json:"startTime" binding:"required"`
+	EndTime          uint64 `json:"endTime" binding:"required"`
+	ExtensionSeconds uint   `json:"extensionSeconds"`
+	Salt             string `json:"salt" binding:"required"`
+	Signature        string `json:"signature"`
+}
+
+func (r auctionRequest) toUseCase() usecase.AuctionRequest {
+	orZero := func(value string) string {
+		if value == "" {
+			return "0"
+		}
+		return value
+	}
+	return usecase.AuctionRequest{
+		Seller:           r.Seller,
+		NFTAddress:       r.NFTAddress,
+		TokenID:          r.TokenID,
+		TokenAddress:     r.TokenAddress,
+		Type:             r.Type,
+		ReservePrice:     orZero(r.ReservePrice),
+		MinIncrement:     orZero(r.MinIncrement),
+		StartPrice:       orZero(r.StartPrice),
+		EndPrice:         orZero(r.EndPrice),
+		StartTime:        r.StartTime,
+		EndTime:          r.EndTime,
+		ExtensionSeconds: r.ExtensionSeconds,
+		Salt:             r.Salt,
+		Signature:        r.Signature,
+	}
+}
+
+type auctionBidRequest struct {
+	Bidder    string `json:"bidder" binding:"required"`
+	Price     string `json:"price" binding:"required"`
+	Nonce     string `json:"nonce" binding:"required"`
+	Expiry    uint64 `json:"expiry" binding:"required"`
+	Signature string `json:"signature"`
+}
+
+func (r auctionBidRequest) toUseCase() usecase.AuctionBidRequest {
+	return usecase.AuctionBidRequest{
+		Bidder:    r.Bidder,
+		Price:     r.Price,
+		Nonce:     r.Nonce,
+		Expiry:    r.Expiry,
+		Signature: r.Signature,
+	}
+}
+
+func auctionID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的拍卖ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (c *AuctionController) BuildAuctionTypedData(ctx *gin.Context) {
+	var req auctionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	typedData, err := c.useCase.BuildAuctionTypedData(req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, typedData)
+}
+
+func (c *AuctionController) CreateAuction(ctx *gin.Context) {
+	var req auctionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.Signature == "" {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	auction, err := c.useCase.CreateAuction(req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, auction)
+}
+
+func (c *AuctionController) GetAuctions(ctx *gin.Context) {
+	statuses, err := usecase.ParseAuctionStatuses(ctx.Query("status"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	auctions, err := c.useCase.GetAuctions(statuses)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, auctions)
+}
+
+func (c *AuctionController) GetAuction(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+
+	auction, err := c.useCase.GetAuction(id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, auction)
+}
+
+func (c *AuctionController) GetAuctionBids(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+
+	bids, err := c.useCase.GetAuctionBids(id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, bids)
+}
+
+func (c *AuctionController) BuildBidTypedData(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+	var req auctionBidRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	typedData, err := c.useCase.BuildBidTypedData(id, req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, typedData)
+}
+
+func (c *AuctionController) PlaceBid(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+	var req auctionBidRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.Signature == "" {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	auction, err := c.useCase.PlaceBid(id, req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, auction)
+}
+
+// 以 Server-Sent Events 推送拍卖状态：连接后先推送当前状态，之后在出价、延时和结束时推送
+func (c *AuctionController) StreamAuction(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+	auction, err := c.useCase.GetAuction(id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	updates, unsubscribe := c.useCase.Subscribe(id)
+	defer unsubscribe()
+	ticker := time.NewTicker(auctionStreamRefreshInterval)
+	defer ticker.Stop()
+
+	ctx.SSEvent("auction", auction)
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case view, ok := <-updates:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("auction", view)
+			return view.Status == usecase.AuctionStatusActive
+		case <-ticker.C:
+			view, err := c.useCase.GetAuction(id)
+			if err != nil {
+				return false
+			}
+			ctx.SSEvent("auction", view)
+			return view.Status == usecase.AuctionStatusActive
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (c *AuctionController) GetCancelTypedData(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+
+	typedData, err := c.useCase.BuildCancelTypedData(id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, typedData)
+}
+
+func (c *AuctionController) CancelAuction(ctx *gin.Context) {
+	id, ok := auctionID(ctx)
+	if !ok {
+		return
+	}
+	var req struct {
+		Signature string `json:"signature" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	auction, err := c.useCase.CancelAuction(id, req.Signature)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, auction)
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"backend/api/middleware"
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController struct {
+	useCase *usecase.AuthUseCase
+}
+
+func NewAuthController(useCase *usecase.AuthUseCase) *AuthController {
+	return &AuthController{useCase: useCase}
+}
+
+func (c *AuthController) GetNonce(ctx *gin.Context) {
+	nonce, err := c.useCase.IssueNonce()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nonce)
+}
+
+func (c *AuthController) Login(ctx *gin.Context) {
+	var req struct {
+		Message   string `json:"message" binding:"required"`
+		Signature string `json:"signature" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	session, err := c.useCase.Login(req.Message, req.Signature, ctx.Request.UserAgent())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, session)
+}
+
+func (c *AuthController) GetSession(ctx *gin.Context) {
+	session, _ := middleware.CurrentSession(ctx)
+	ctx.JSON(http.StatusOK, session)
+}
+
+func (c *AuthController) GetSessions(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	sessions, err := c.useCase.GetActiveSessions(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+func (c *AuthController) Logout(ctx *gin.Context) {
+	session, _ := middleware.CurrentSession(ctx)
+	if err := c.useCase.Logout(session); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "已退出登录"})
+}
+
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.LogoutAll(address); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "已退出全部会话"})
+}
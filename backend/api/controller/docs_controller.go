@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"backend/api/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 使用 Redoc 渲染接口文档，文档地址相对于 /api/docs
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>NFTMarket API</title>
+</head>
+<body>
+  <redoc spec-url="openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+type DocsController struct {
+	spec *openapi.Spec
+	json []byte
+}
+
+func NewDocsController(spec *openapi.Spec) (*DocsController, error) {
+	data, err := spec.JSON()
+	if err != nil {
+		return nil, err
+	}
+	return &DocsController{spec: spec, json: data}, nil
+}
+
+func (c *DocsController) GetSpecJSON(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", c.json)
+}
+
+func (c *DocsController) GetSpecYAML(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/yaml; charset=utf-8", c.spec.YAML())
+}
+
+func (c *DocsController) GetDocs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"backend/api/graphql"
+	"backend/domain"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLController struct {
+	schema *graphql.Schema
+}
+
+func NewGraphQLController(schema *graphql.Schema) *GraphQLController {
+	return &GraphQLController{schema: schema}
+}
+
+// 执行 GraphQL 查询，POST 时读取 JSON 请求体，GET 时读取 query、variables 和 operationName 参数
+func (c *GraphQLController) Query(ctx *gin.Context) {
+	var req graphql.Request
+	if ctx.Request.Method == http.MethodGet {
+		req.Query = ctx.Query("query")
+		req.OperationName = ctx.Query("operationName")
+		if variables := ctx.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				ctx.Error(domain.InvalidParam("variables", "无效的 variables 参数"))
+				return
+			}
+		}
+	} else if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+	if req.Query == "" {
+		ctx.Error(domain.InvalidParam("query", "缺少 query"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.schema.Execute(ctx.Request.Context(), req))
+}
+
+// 返回模式的 SDL 描述
+func (c *GraphQLController) GetSchema(ctx *gin.Context) {
+	ctx.String(http.StatusOK, c.schema.SDL())
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+type ListingController struct {
+	useCase *usecase.ListingUseCase
+}
+
+func NewListingController(useCase *usecase.ListingUseCase) *ListingController {
+	return &ListingController{useCase: useCase}
+}
+
+type listingRequest struct {
+	Seller       string `json:"seller" binding:"required"`
+	NFTAddress   string `json:"nftAddress" binding:"required"`
+	TokenID      uint   `json:"tokenId"`
+	TokenAddress string `json:"tokenAddress" binding:"required"`
+	Price        string `json:"price" binding:"required"`
+	Nonce        uint64 `json:"nonce"`
+	Salt         string `json:"salt" binding:"required"`
+	Expiry       uint64 `json:"expiry" binding:"required"`
+	Signature    string `json:"signature"`
+}
+
+func (r listingRequest) toUseCase() usecase.SignedListingRequest {
+	return usecase.SignedListingRequest{
+		Seller:       r.Seller,
+		NFTAddress:   r.NFTAddress,
+		TokenID:      r.TokenID,
+		TokenAddress: r.TokenAddress,
+		Price:        r.Price,
+		Nonce:        r.Nonce,
+		Salt:         r.Salt,
+		Expiry:       r.Expiry,
+		Signature:    r.Signature,
+	}
+}
+
+func (c *ListingController) BuildListingTypedData(ctx *gin.Context) {
+	var req listingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	typedData, err := c.useCase.BuildListingTypedData(req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, typedData)
+}
+
+func (c *ListingController) SubmitListing(ctx *gin.Context) {
+	var req listingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.Signature == "" {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	listing, err := c.useCase.SubmitListing(req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, listing)
+}
+
+func (c *ListingController) GetListings(ctx *gin.Context) {
+	seller := ctx.Query("seller")
+	if seller != "" && !common.IsHexAddress(seller) {
+		ctx.Error(domain.InvalidParam("seller", "无效的卖家地址"))
+		return
+	}
+	statuses, err := usecase.ParseListingStatuses(ctx.Query("status"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	listings, err := c.useCase.GetListings(seller, statuses)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, listings)
+}
+
+func (c *ListingController) GetListing(ctx *gin.Context) {
+	listing, err := c.useCase.GetListing(ctx.Param("hash"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, listing)
+}
+
+func (c *ListingController) GetSignerNonce(ctx *gin.Context) {
+	seller := ctx.Query("seller")
+	if !common.IsHexAddress(seller) {
+		ctx.Error(domain.InvalidParam("seller", "无效的卖家地址"))
+		return
+	}
+
+	nonce, err := c.useCase.GetSignerNonce(seller)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nonce)
+}
+
+func (c *ListingController) IncreaseNonce(ctx *gin.Context) {
+	var req struct {
+		Seller    string `json:"seller" binding:"required"`
+		Nonce     uint64 `json:"nonce" binding:"required"`
+		Signature string `json:"signature" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.Seller) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	nonce, err := c.useCase.IncreaseNonceWithSignature(req.Seller, req.Nonce, req.Signature)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nonce)
+}
@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"backend/domain"
 	"backend/usecase"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,7 +22,7 @@ func NewMarketController(useCase *usecase.MarketUseCase) *MarketController {
 func (c *MarketController) GetOrders(ctx *gin.Context) {
 	orders, err := c.useCase.GetAllOrders()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取订单失败"})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, orders)
@@ -29,15 +32,235 @@ func (c *MarketController) GetOrderByNFT(ctx *gin.Context) {
 	contractAddress := ctx.Param("contractAddress")
 	tokenID, err := strconv.ParseUint(ctx.Param("tokenID"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的TokenID"})
+		ctx.Error(domain.InvalidParam("tokenID", "无效的TokenID"))
 		return
 	}
 
 	order, err := c.useCase.GetOrderByNFT(contractAddress, uint(tokenID))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "订单未找到"})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, order)
 }
+
+// 批量获取NFT最新的链上订单，结果以 "<小写合约地址>:<tokenId>" 为键，没有订单的NFT为 null
+func (c *MarketController) BatchGetOrders(ctx *gin.Context) {
+	keys, ok := bindNFTBatch(ctx)
+	if !ok {
+		return
+	}
+
+	orders, err := c.useCase.BatchGetOrders(keys)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"results": orders})
+}
+
+func (c *MarketController) GetCollectionStats(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+
+	stats, err := c.useCase.GetCollectionStats(contractAddress)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, stats)
+}
+
+func (c *MarketController) GetNFTProvenance(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+	tokenID, err := strconv.ParseUint(ctx.Param("tokenID"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("tokenID", "无效的tokenID"))
+		return
+	}
+
+	provenance, err := c.useCase.GetNFTProvenance(contractAddress, uint(tokenID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, provenance)
+}
+
+func (c *MarketController) GetPriceHistory(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+	tokenAddress := ctx.Query("token")
+	if tokenAddress != "" && !common.IsHexAddress(tokenAddress) {
+		ctx.Error(domain.InvalidParam("token", "无效的代币地址"))
+		return
+	}
+
+	// from/to 为可选的 Unix 时间戳
+	var from, to time.Time
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if value := ctx.Query(param.name); value != "" {
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				ctx.Error(domain.InvalidParam(param.name, "无效的时间参数: "+param.name))
+				return
+			}
+			*param.target = time.Unix(unix, 0)
+		}
+	}
+
+	interval := ctx.DefaultQuery("interval", usecase.CandleInterval1d)
+	candles, err := c.useCase.GetPriceHistory(contractAddress, interval, tokenAddress, from, to)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"interval": interval, "candles": candles})
+}
+
+func (c *MarketController) RebuildPriceHistory(ctx *gin.Context) {
+	if err := c.useCase.RebuildPriceHistory(); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "价格历史已重建"})
+}
+
+func (c *MarketController) GetOrderPermit(ctx *gin.Context) {
+	orderIndex, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的订单ID"))
+		return
+	}
+	buyer := ctx.Query("buyer")
+	if !common.IsHexAddress(buyer) {
+		ctx.Error(domain.InvalidParam("buyer", "无效的买家地址"))
+		return
+	}
+	var deadline uint64
+	if raw := ctx.Query("deadline"); raw != "" {
+		if deadline, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			ctx.Error(domain.InvalidParam("deadline", "无效的deadline"))
+			return
+		}
+	}
+
+	permit, err := c.useCase.BuildOrderPermit(uint(orderIndex), buyer, deadline)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, permit)
+}
+
+type verifyPermitRequest struct {
+	Buyer     string `json:"buyer" binding:"required"`
+	Deadline  uint64 `json:"deadline" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+func (c *MarketController) VerifyOrderPermit(ctx *gin.Context) {
+	orderIndex, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的订单ID"))
+		return
+	}
+	var req verifyPermitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.Buyer) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	signature, err := c.useCase.VerifyOrderPermit(uint(orderIndex), req.Buyer, req.Deadline, req.Signature)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, signature)
+}
+
+type simulateBuyRequest struct {
+	Buyer     string `json:"buyer" binding:"required"`
+	Deadline  uint64 `json:"deadline" binding:"required"`
+	Signature string `json:"signature"`
+	V         uint8  `json:"v"`
+	R         string `json:"r"`
+	S         string `json:"s"`
+}
+
+func (c *MarketController) SimulateBuy(ctx *gin.Context) {
+	orderIndex, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的订单ID"))
+		return
+	}
+	var req simulateBuyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.Buyer) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	// 支持完整签名或拆分后的 v/r/s
+	signature := req.Signature
+	if signature == "" {
+		if signature, err = usecase.JoinSignature(req.V, req.R, req.S); err != nil {
+			ctx.Error(err)
+			return
+		}
+	}
+
+	simulation, err := c.useCase.SimulateBuy(uint(orderIndex), req.Buyer, req.Deadline, signature)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, simulation)
+}
+
+type listingPreflightRequest struct {
+	Seller       string `json:"seller" binding:"required"`
+	NFTAddress   string `json:"nftAddress" binding:"required"`
+	TokenID      *uint  `json:"tokenId" binding:"required"`
+	TokenAddress string `json:"tokenAddress" binding:"required"`
+	Price        string `json:"price" binding:"required"`
+}
+
+func (c *MarketController) PreflightListing(ctx *gin.Context) {
+	var req listingPreflightRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil ||
+		!common.IsHexAddress(req.Seller) || !common.IsHexAddress(req.NFTAddress) || !common.IsHexAddress(req.TokenAddress) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	preflight, err := c.useCase.PreflightListing(usecase.ListingRequest{
+		Seller:       req.Seller,
+		NFTAddress:   req.NFTAddress,
+		TokenID:      *req.TokenID,
+		TokenAddress: req.TokenAddress,
+		Price:        req.Price,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preflight)
+}
@@ -1,27 +1,46 @@
 package controller
 
 import (
+	"backend/api/middleware"
+	"backend/domain"
 	"backend/usecase"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type NFTController struct {
-	useCase *usecase.NFTUseCase
+	useCase     *usecase.NFTUseCase
+	watchlistUC *usecase.WatchlistUseCase
 }
 
-func NewNFTController(useCase *usecase.NFTUseCase) *NFTController {
-	return &NFTController{useCase: useCase}
+func NewNFTController(useCase *usecase.NFTUseCase, watchlistUC *usecase.WatchlistUseCase) *NFTController {
+	return &NFTController{useCase: useCase, watchlistUC: watchlistUC}
 }
 
 func (c *NFTController) GetCollection(ctx *gin.Context) {
 	contractAddress := ctx.Param("contractAddress")
 
-	collection, nfts, err := c.useCase.GetCollectionByAddress(contractAddress)
+	filter, err := parseTraitFilter(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "NFT系列未找到"})
+		ctx.Error(domain.ErrInvalidRequest.WithMessage("无效的属性筛选参数"))
+		return
+	}
+
+	sort := ctx.DefaultQuery("sort", domain.NFTSortTokenID)
+	switch sort {
+	case domain.NFTSortTokenID, domain.NFTSortRarity, domain.NFTSortRarityDesc:
+	default:
+		ctx.Error(domain.InvalidParam("sort", "无效的排序方式"))
+		return
+	}
+
+	collection, nfts, err := c.useCase.GetCollectionByAddress(contractAddress, filter, sort)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
@@ -31,10 +50,86 @@ func (c *NFTController) GetCollection(ctx *gin.Context) {
 	})
 }
 
+// 解析属性筛选参数:
+// trait=类型:取值 (可重复, 同类型取值为"或"), range=类型:最小值:最大值 (可省略一端), match=all|any
+func parseTraitFilter(ctx *gin.Context) (*domain.TraitFilter, error) {
+	filter := &domain.TraitFilter{}
+
+	index := make(map[string]int)
+	for _, param := range ctx.QueryArray("trait") {
+		traitType, value, found := strings.Cut(param, ":")
+		if !found || traitType == "" {
+			return nil, fmt.Errorf("无效的属性条件: %s", param)
+		}
+		if i, exists := index[traitType]; exists {
+			filter.Traits[i].Values = append(filter.Traits[i].Values, value)
+			continue
+		}
+		index[traitType] = len(filter.Traits)
+		filter.Traits = append(filter.Traits, domain.TraitCondition{TraitType: traitType, Values: []string{value}})
+	}
+
+	for _, param := range ctx.QueryArray("range") {
+		rest, maxValue, found := cutLast(param, ":")
+		if !found {
+			return nil, fmt.Errorf("无效的区间条件: %s", param)
+		}
+		traitType, minValue, found := cutLast(rest, ":")
+		if !found || traitType == "" {
+			return nil, fmt.Errorf("无效的区间条件: %s", param)
+		}
+		rng := domain.TraitRange{TraitType: traitType}
+		if minValue != "" {
+			v, err := strconv.ParseFloat(minValue, 64)
+			if err != nil {
+				return nil, fmt.Errorf("无效的区间最小值: %s", minValue)
+			}
+			rng.Min = &v
+		}
+		if maxValue != "" {
+			v, err := strconv.ParseFloat(maxValue, 64)
+			if err != nil {
+				return nil, fmt.Errorf("无效的区间最大值: %s", maxValue)
+			}
+			rng.Max = &v
+		}
+		filter.Ranges = append(filter.Ranges, rng)
+	}
+
+	switch ctx.DefaultQuery("match", "all") {
+	case "all":
+	case "any":
+		filter.MatchAny = true
+	default:
+		return nil, fmt.Errorf("无效的匹配模式: %s", ctx.Query("match"))
+	}
+
+	return filter, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func (c *NFTController) GetCollectionTraits(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+
+	facets, err := c.useCase.GetCollectionTraitFacets(contractAddress)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, facets)
+}
+
 func (c *NFTController) GetCollections(ctx *gin.Context) {
 	collections, err := c.useCase.GetAllCollections()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取NFT系列失败"})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, collections)
@@ -44,33 +139,95 @@ func (c *NFTController) GetNFT(ctx *gin.Context) {
 	contractAddress := ctx.Param("contractAddress")
 	tokenID, err := strconv.ParseUint(ctx.Param("tokenID"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的tokenID"})
+		ctx.Error(domain.InvalidParam("tokenID", "无效的tokenID"))
 		return
 	}
 
 	nft, attributes, err := c.useCase.GetNFTByTokenID(contractAddress, uint(tokenID))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "NFT未找到"})
+		ctx.Error(err)
+		return
+	}
+
+	// 稀有度尚未计算时返回 null
+	var rarity *domain.NFTRarity
+	if r, err := c.useCase.GetNFTRarity(nft.ID); err == nil {
+		rarity = r
+	}
+
+	// 收藏数，登录时同时返回当前用户是否已收藏
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	favoriteCount, favorited, err := c.watchlistUC.GetFavoriteStatus(address, contractAddress, uint(tokenID))
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"nft":        nft,
-		"attributes": attributes,
+		"nft":           nft,
+		"attributes":    attributes,
+		"rarity":        rarity,
+		"favoriteCount": favoriteCount,
+		"favorited":     favorited,
 	})
 }
 
+// 批量查询的NFT列表
+type nftBatchRequest struct {
+	NFTs []struct {
+		NFTAddress string `json:"nftAddress" binding:"required"`
+		TokenID    *uint  `json:"tokenId" binding:"required"`
+	} `json:"nfts" binding:"required,dive"`
+}
+
+func bindNFTBatch(ctx *gin.Context) ([]domain.NFTKey, bool) {
+	var req nftBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return nil, false
+	}
+	keys := make([]domain.NFTKey, len(req.NFTs))
+	for i, nft := range req.NFTs {
+		keys[i] = domain.NFTKey{ContractAddress: nft.NFTAddress, TokenID: *nft.TokenID}
+	}
+	return keys, true
+}
+
+// 批量获取NFT及其属性，结果以 "<小写合约地址>:<tokenId>" 为键，不存在的NFT为 null
+func (c *NFTController) BatchGetNFTs(ctx *gin.Context) {
+	keys, ok := bindNFTBatch(ctx)
+	if !ok {
+		return
+	}
+
+	nfts, attributes, err := c.useCase.BatchGetNFTs(keys)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	results := make(map[string]gin.H, len(nfts))
+	for key, nft := range nfts {
+		if nft == nil {
+			results[key] = nil
+			continue
+		}
+		results[key] = gin.H{"nft": nft, "attributes": attributes[key]}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"results": results})
+}
+
 func (c *NFTController) GetNFTTransferHistory(ctx *gin.Context) {
 	contractAddress := ctx.Param("contractAddress")
 	tokenID, err := strconv.ParseUint(ctx.Param("tokenID"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的tokenID"})
+		ctx.Error(domain.InvalidParam("tokenID", "无效的tokenID"))
 		return
 	}
 
 	history, err := c.useCase.GetNFTTransferHistory(contractAddress, uint(tokenID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取转移历史失败"})
+		ctx.Error(err)
 		return
 	}
 
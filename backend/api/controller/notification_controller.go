@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"backend/api/middleware"
+	"backend/domain"
+	"backend/usecase"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 通知流在没有新通知时定期推送未读数量，同时用于检测会话是否过期
+const notificationStreamHeartbeatInterval = 30 * time.Second
+
+type NotificationController struct {
+	useCase *usecase.NotificationUseCase
+}
+
+func NewNotificationController(useCase *usecase.NotificationUseCase) *NotificationController {
+	return &NotificationController{useCase: useCase}
+}
+
+// 通知列表，unread=true 时只返回未读，before 为上一页最后一条通知的ID
+func (c *NotificationController) GetNotifications(ctx *gin.Context) {
+	var beforeID uint64
+	if raw := ctx.Query("before"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			ctx.Error(domain.InvalidParam("before", "无效的before参数"))
+			return
+		}
+		beforeID = parsed
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil {
+		ctx.Error(domain.InvalidParam("limit", "无效的limit参数"))
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	notifications, unread, err := c.useCase.GetNotifications(address, ctx.Query("unread") == "true", uint(beforeID), limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread": unread})
+}
+
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	var req struct {
+		IDs []uint `json:"ids"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	updated, err := c.useCase.MarkRead(address, req.IDs)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// 通过 SSE 推送新通知，会话过期后断开
+func (c *NotificationController) StreamNotifications(ctx *gin.Context) {
+	session, _ := middleware.CurrentSession(ctx)
+	unread, err := c.useCase.CountUnread(session.Address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	updates, unsubscribe := c.useCase.Subscribe(session.Address)
+	defer unsubscribe()
+	ticker := time.NewTicker(notificationStreamHeartbeatInterval)
+	defer ticker.Stop()
+
+	ctx.SSEvent("unread", gin.H{"unread": unread})
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case view, ok := <-updates:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("notification", view)
+			return true
+		case <-ticker.C:
+			if time.Now().After(session.ExpiresAt) {
+				return false
+			}
+			unread, err := c.useCase.CountUnread(session.Address)
+			if err != nil {
+				return false
+			}
+			ctx.SSEvent("unread", gin.H{"unread": unread})
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+type OfferController struct {
+	useCase *usecase.OfferUseCase
+}
+
+func NewOfferController(useCase *usecase.OfferUseCase) *OfferController {
+	return &OfferController{useCase: useCase}
+}
+
+type offerRequest struct {
+	Bidder         string `json:"bidder" binding:"required"`
+	NFTAddress     string `json:"nftAddress" binding:"required"`
+	TokenID        uint   `json:"tokenId"`
+	CollectionWide bool   `json:"collectionWide"`
+	TokenAddress   string `json:"tokenAddress" binding:"required"`
+	Price          string `json:"price" binding:"required"`
+	Nonce          string `json:"nonce" binding:"required"`
+	Expiry         uint64 `json:"expiry" binding:"required"`
+	Signature      string `json:"signature"`
+}
+
+func (r offerRequest) toUseCase() usecase.OfferRequest {
+	return usecase.OfferRequest{
+		Bidder:         r.Bidder,
+		NFTAddress:     r.NFTAddress,
+		TokenID:        r.TokenID,
+		CollectionWide: r.CollectionWide,
+		TokenAddress:   r.TokenAddress,
+		Price:          r.Price,
+		Nonce:          r.Nonce,
+		Expiry:         r.Expiry,
+		Signature:      r.Signature,
+	}
+}
+
+func (c *OfferController) BuildOfferTypedData(ctx *gin.Context) {
+	var req offerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	typedData, err := c.useCase.BuildOfferTypedData(req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, typedData)
+}
+
+func (c *OfferController) SubmitOffer(ctx *gin.Context) {
+	var req offerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.Signature == "" {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	offer, err := c.useCase.SubmitOffer(req.toUseCase())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, offer)
+}
+
+func (c *OfferController) GetOffer(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的出价ID"))
+		return
+	}
+
+	offer, err := c.useCase.GetOffer(uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, offer)
+}
+
+func (c *OfferController) GetNFTOffers(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+	tokenID, err := strconv.ParseUint(ctx.Param("tokenID"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("tokenID", "无效的tokenID"))
+		return
+	}
+
+	offers, err := c.useCase.GetOffersForNFT(contractAddress, uint(tokenID), ctx.Query("includeInvalid") == "true")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, offers)
+}
+
+func (c *OfferController) GetCollectionOffers(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+
+	offers, err := c.useCase.GetCollectionOffers(contractAddress, ctx.Query("includeInvalid") == "true")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, offers)
+}
+
+func (c *OfferController) GetBidderOffers(ctx *gin.Context) {
+	bidder := ctx.Query("bidder")
+	if !common.IsHexAddress(bidder) {
+		ctx.Error(domain.InvalidParam("bidder", "无效的买家地址"))
+		return
+	}
+
+	offers, err := c.useCase.GetOffersByBidder(bidder)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, offers)
+}
+
+func (c *OfferController) GetCancelTypedData(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的出价ID"))
+		return
+	}
+
+	typedData, err := c.useCase.BuildCancelTypedData(uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, typedData)
+}
+
+func (c *OfferController) CancelOffer(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的出价ID"))
+		return
+	}
+	var req struct {
+		Signature string `json:"signature" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	offer, err := c.useCase.CancelOffer(uint(id), req.Signature)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, offer)
+}
+
+func (c *OfferController) GetBidderNonce(ctx *gin.Context) {
+	bidder := ctx.Query("bidder")
+	if !common.IsHexAddress(bidder) {
+		ctx.Error(domain.InvalidParam("bidder", "无效的买家地址"))
+		return
+	}
+
+	nonce, err := c.useCase.GetBidderNonce(bidder)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nonce)
+}
+
+func (c *OfferController) IncreaseNonce(ctx *gin.Context) {
+	var req struct {
+		Bidder    string `json:"bidder" binding:"required"`
+		Nonce     uint64 `json:"nonce" binding:"required"`
+		Signature string `json:"signature" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.Bidder) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	nonce, err := c.useCase.IncreaseNonceWithSignature(req.Bidder, req.Nonce, req.Signature)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nonce)
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"backend/api/middleware"
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileController struct {
+	useCase *usecase.ProfileUseCase
+}
+
+func NewProfileController(useCase *usecase.ProfileUseCase) *ProfileController {
+	return &ProfileController{useCase: useCase}
+}
+
+// 按地址或用户名获取资料
+func (c *ProfileController) GetProfile(ctx *gin.Context) {
+	profile, err := c.useCase.GetProfile(ctx.Param("address"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// 获取当前登录地址的资料
+func (c *ProfileController) GetMyProfile(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	profile, err := c.useCase.GetProfile(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// 修改当前登录地址的资料
+func (c *ProfileController) UpdateProfile(ctx *gin.Context) {
+	var req struct {
+		Username         string `json:"username"`
+		DisplayName      string `json:"displayName"`
+		Bio              string `json:"bio"`
+		AvatarNFTAddress string `json:"avatarNftAddress"`
+		AvatarTokenID    uint   `json:"avatarTokenId"`
+		Twitter          string `json:"twitter"`
+		Discord          string `json:"discord"`
+		Website          string `json:"website"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	profile, err := c.useCase.UpdateProfile(address, usecase.ProfileRequest{
+		Username:         req.Username,
+		DisplayName:      req.DisplayName,
+		Bio:              req.Bio,
+		AvatarNFTAddress: req.AvatarNFTAddress,
+		AvatarTokenID:    req.AvatarTokenID,
+		Twitter:          req.Twitter,
+		Discord:          req.Discord,
+		Website:          req.Website,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
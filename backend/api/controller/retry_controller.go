@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RetryController struct {
+	useCase *usecase.RetryUseCase
+}
+
+func NewRetryController(useCase *usecase.RetryUseCase) *RetryController {
+	return &RetryController{useCase: useCase}
+}
+
+func (c *RetryController) GetFailedJobs(ctx *gin.Context) {
+	jobs, err := c.useCase.GetFailedJobs()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+func (c *RetryController) RetryJob(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("id", "无效的任务ID"))
+		return
+	}
+
+	job, err := c.useCase.RetryJob(uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
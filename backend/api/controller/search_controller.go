@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type SearchController struct {
+	useCase *usecase.SearchUseCase
+}
+
+func NewSearchController(useCase *usecase.SearchUseCase) *SearchController {
+	return &SearchController{useCase: useCase}
+}
+
+func (c *SearchController) Search(ctx *gin.Context) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		ctx.Error(domain.InvalidParam("q", "搜索关键词不能为空"))
+		return
+	}
+
+	docType := ctx.Query("type")
+	switch docType {
+	case "", domain.SearchTypeCollection, domain.SearchTypeNFT, domain.SearchTypeAddress, domain.SearchTypeTransaction:
+	default:
+		ctx.Error(domain.InvalidParam("type", "无效的搜索类型"))
+		return
+	}
+
+	limit, err := parseLimit(ctx, defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("limit", "无效的limit"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.useCase.Search(query, docType, limit))
+}
+
+func (c *SearchController) Autocomplete(ctx *gin.Context) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		ctx.JSON(http.StatusOK, []string{})
+		return
+	}
+
+	limit, err := parseLimit(ctx, 10, maxSearchLimit)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("limit", "无效的limit"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.useCase.Autocomplete(query, limit))
+}
+
+// 解析 limit 查询参数，超过上限时取上限
+func parseLimit(ctx *gin.Context, defaultLimit, maxLimit int) (int, error) {
+	raw := ctx.Query("limit")
+	if raw == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return limit, nil
+}
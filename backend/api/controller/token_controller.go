@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+type TokenController struct {
+	useCase *usecase.TokenUseCase
+}
+
+func NewTokenController(useCase *usecase.TokenUseCase) *TokenController {
+	return &TokenController{useCase: useCase}
+}
+
+func (c *TokenController) GetTokens(ctx *gin.Context) {
+	tokens, err := c.useCase.GetAllTokens()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+func (c *TokenController) GetToken(ctx *gin.Context) {
+	address := ctx.Param("address")
+	if !common.IsHexAddress(address) {
+		ctx.Error(domain.InvalidParam("address", "无效的代币地址"))
+		return
+	}
+
+	token, err := c.useCase.GetToken(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, token)
+}
+
+func (c *TokenController) GetTokenPrice(ctx *gin.Context) {
+	address := ctx.Param("address")
+	if !common.IsHexAddress(address) {
+		ctx.Error(domain.InvalidParam("address", "无效的代币地址"))
+		return
+	}
+
+	quote, err := c.useCase.GetTokenPrice(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, quote)
+}
+
+func (c *TokenController) RefreshToken(ctx *gin.Context) {
+	address := ctx.Param("address")
+	if !common.IsHexAddress(address) {
+		ctx.Error(domain.InvalidParam("address", "无效的代币地址"))
+		return
+	}
+
+	token, err := c.useCase.RefreshToken(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, token)
+}
+
+var tokenListStatuses = map[string]uint{
+	"none":    usecase.TokenListStatusNone,
+	"allowed": usecase.TokenListStatusAllowed,
+	"denied":  usecase.TokenListStatusDenied,
+}
+
+func (c *TokenController) SetTokenListStatus(ctx *gin.Context) {
+	address := ctx.Param("address")
+	if !common.IsHexAddress(address) {
+		ctx.Error(domain.InvalidParam("address", "无效的代币地址"))
+		return
+	}
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+	status, ok := tokenListStatuses[req.Status]
+	if !ok {
+		ctx.Error(domain.InvalidParam("status", "无效的名单状态"))
+		return
+	}
+
+	token, err := c.useCase.SetTokenListStatus(address, status)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, token)
+}
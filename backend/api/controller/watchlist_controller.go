@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"backend/api/middleware"
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistController struct {
+	useCase *usecase.WatchlistUseCase
+}
+
+func NewWatchlistController(useCase *usecase.WatchlistUseCase) *WatchlistController {
+	return &WatchlistController{useCase: useCase}
+}
+
+func (c *WatchlistController) GetFavorites(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	nfts, err := c.useCase.GetFavorites(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nfts)
+}
+
+func (c *WatchlistController) AddFavorite(ctx *gin.Context) {
+	var req struct {
+		NFTAddress string `json:"nftAddress" binding:"required"`
+		TokenID    uint   `json:"tokenId"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.NFTAddress) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.AddFavorite(address, req.NFTAddress, req.TokenID); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"message": "已收藏"})
+}
+
+func (c *WatchlistController) RemoveFavorite(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+	tokenID, err := strconv.ParseUint(ctx.Param("tokenID"), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam("tokenID", "无效的tokenID"))
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.RemoveFavorite(address, contractAddress, uint(tokenID)); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "已取消收藏"})
+}
+
+func (c *WatchlistController) GetWatchlist(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	collections, err := c.useCase.GetWatchedCollections(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collections)
+}
+
+func (c *WatchlistController) AddWatch(ctx *gin.Context) {
+	var req struct {
+		ContractAddress string `json:"contractAddress" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.ContractAddress) {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.AddWatch(address, req.ContractAddress); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"message": "已关注"})
+}
+
+func (c *WatchlistController) RemoveWatch(ctx *gin.Context) {
+	contractAddress := ctx.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		ctx.Error(domain.InvalidParam("contractAddress", "无效的合约地址"))
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.RemoveWatch(address, contractAddress); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "已取消关注"})
+}
+
+// 关注系列的动态，type 为逗号分隔的 listing/price_drop/sale，before 为上一页最后一条动态的ID
+func (c *WatchlistController) GetFeed(ctx *gin.Context) {
+	var beforeID uint64
+	if raw := ctx.Query("before"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			ctx.Error(domain.InvalidParam("before", "无效的before参数"))
+			return
+		}
+		beforeID = parsed
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil {
+		ctx.Error(domain.InvalidParam("limit", "无效的limit参数"))
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	feed, err := c.useCase.GetFeed(address, usecase.ParseActivityTypes(ctx.Query("type")), uint(beforeID), limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, feed)
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"backend/api/middleware"
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	useCase *usecase.WebhookUseCase
+}
+
+func NewWebhookController(useCase *usecase.WebhookUseCase) *WebhookController {
+	return &WebhookController{useCase: useCase}
+}
+
+func parseWebhookID(ctx *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(param), 10, 64)
+	if err != nil {
+		ctx.Error(domain.InvalidParam(param, "无效的ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	var req usecase.WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	webhook, secret, err := c.useCase.CreateWebhook(address, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"webhook": webhook, "secret": secret})
+}
+
+func (c *WebhookController) GetWebhooks(ctx *gin.Context) {
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	webhooks, err := c.useCase.GetWebhooks(address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, webhooks)
+}
+
+func (c *WebhookController) GetWebhook(ctx *gin.Context) {
+	id, ok := parseWebhookID(ctx, "id")
+	if !ok {
+		return
+	}
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	webhook, err := c.useCase.GetWebhook(address, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+func (c *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	id, ok := parseWebhookID(ctx, "id")
+	if !ok {
+		return
+	}
+	var req usecase.WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(domain.ErrInvalidRequest)
+		return
+	}
+
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	webhook, err := c.useCase.UpdateWebhook(address, id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	id, ok := parseWebhookID(ctx, "id")
+	if !ok {
+		return
+	}
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	if err := c.useCase.DeleteWebhook(address, id); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook已删除"})
+}
+
+func (c *WebhookController) RotateSecret(ctx *gin.Context) {
+	id, ok := parseWebhookID(ctx, "id")
+	if !ok {
+		return
+	}
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	webhook, secret, err := c.useCase.RotateSecret(address, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"webhook": webhook, "secret": secret})
+}
+
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	id, ok := parseWebhookID(ctx, "id")
+	if !ok {
+		return
+	}
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	deliveries, err := c.useCase.GetDeliveries(address, id, ctx.Query("status"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (c *WebhookController) ReplayDelivery(ctx *gin.Context) {
+	id, ok := parseWebhookID(ctx, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseWebhookID(ctx, "deliveryID")
+	if !ok {
+		return
+	}
+	address, _ := middleware.AuthenticatedAddress(ctx)
+	delivery, err := c.useCase.ReplayDelivery(address, id, deliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, delivery)
+}
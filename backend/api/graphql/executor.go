@@ -0,0 +1,580 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 查询的最大嵌套深度，避免构造过深的查询拖垮数据库
+const maxQueryDepth = 10
+
+// Arg 表示字段参数，Default 为未传参时使用的值
+type Arg struct {
+	Name    string
+	Type    string
+	Default interface{}
+}
+
+// ResolveParams 是单个对象字段解析函数的参数
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// BatchParams 是批量字段解析函数的参数，同一层级的所有父对象一次解析
+type BatchParams struct {
+	Context context.Context
+	Sources []interface{}
+	Args    map[string]interface{}
+}
+
+// Field 表示对象类型的字段，Resolve 和 Batch 二选一，Batch 返回的结果与 Sources 一一对应
+type Field struct {
+	Name        string
+	Type        string // 如 String!、[NFT!]!
+	Description string
+	Args        []Arg
+	Resolve     func(p ResolveParams) (interface{}, error)
+	Batch       func(p BatchParams) ([]interface{}, error)
+}
+
+// Object 表示对象类型
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+	fields      map[string]*Field
+}
+
+// InputObject 表示输入类型，只用于生成 SDL，参数值按 map 传给解析函数
+type InputObject struct {
+	Name   string
+	Fields []Arg
+}
+
+// Schema 表示只读的查询模式
+type Schema struct {
+	query   *Object
+	objects map[string]*Object
+	order   []*Object
+	inputs  []*InputObject
+}
+
+// NewSchema 创建模式，query 为根类型，objects 为其余对象类型
+func NewSchema(query *Object, objects []*Object, inputs []*InputObject) *Schema {
+	s := &Schema{query: query, objects: make(map[string]*Object), inputs: inputs}
+	for _, obj := range append([]*Object{query}, objects...) {
+		obj.fields = make(map[string]*Field, len(obj.Fields))
+		for _, f := range obj.Fields {
+			obj.fields[f.Name] = f
+		}
+		s.objects[obj.Name] = obj
+		s.order = append(s.order, obj)
+	}
+	return s
+}
+
+// SDL 返回模式的 GraphQL SDL 描述
+func (s *Schema) SDL() string {
+	var sb strings.Builder
+	for _, obj := range s.order {
+		if obj.Description != "" {
+			fmt.Fprintf(&sb, "\"\"\"%s\"\"\"\n", obj.Description)
+		}
+		fmt.Fprintf(&sb, "type %s {\n", obj.Name)
+		for _, f := range obj.Fields {
+			if f.Description != "" {
+				fmt.Fprintf(&sb, "  \"%s\"\n", f.Description)
+			}
+			fmt.Fprintf(&sb, "  %s%s: %s\n", f.Name, formatArgs(f.Args), f.Type)
+		}
+		sb.WriteString("}\n\n")
+	}
+	for _, input := range s.inputs {
+		fmt.Fprintf(&sb, "input %s {\n", input.Name)
+		for _, arg := range input.Fields {
+			fmt.Fprintf(&sb, "  %s: %s\n", arg.Name, arg.Type)
+		}
+		sb.WriteString("}\n\n")
+	}
+	fmt.Fprintf(&sb, "schema {\n  query: %s\n}\n", s.query.Name)
+	return sb.String()
+}
+
+func formatArgs(args []Arg) string {
+	if len(args) == 0 {
+		return ""
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.Name + ": " + arg.Type
+		if arg.Default != nil {
+			value, _ := json.Marshal(arg.Default)
+			parts[i] += " = " + string(value)
+		}
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// Request 表示 GraphQL 请求
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Error 表示返回给客户端的错误，Path 为出错字段在结果中的键
+type Error struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+// Response 表示 GraphQL 响应
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Execute 解析并执行查询，解析或校验失败时 Data 为 nil
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parseDocument(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.kind != "query" {
+		return &Response{Errors: []*Error{{Message: "只支持 query 操作"}}}
+	}
+	variables, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{schema: s, ctx: ctx, doc: doc, variables: variables}
+	results := e.executeSelections(s.query, []interface{}{struct{}{}}, op.selections, nil, 1)
+	return &Response{Data: results[0], Errors: e.errors}
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("查询包含多个操作时必须指定 operationName")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("未找到操作 %s", name)
+}
+
+// 使用默认值补全变量，并检查必填变量
+func coerceVariables(op *operation, provided map[string]interface{}) (map[string]interface{}, error) {
+	variables := make(map[string]interface{})
+	for _, def := range op.variables {
+		value, exists := provided[def.name]
+		if !exists && def.hasDefault {
+			value, exists = constantValue(def.defaultValue), true
+		}
+		if (!exists || value == nil) && strings.HasSuffix(def.typ, "!") {
+			return nil, fmt.Errorf("缺少必填变量 $%s", def.name)
+		}
+		if exists {
+			variables[def.name] = value
+		}
+	}
+	return variables, nil
+}
+
+// 将常量值节点转为 Go 值
+func constantValue(value interface{}) interface{} {
+	v, _ := resolveValue(value, nil)
+	return v
+}
+
+// 将值节点转为 Go 值，变量替换为请求中的值
+func resolveValue(value interface{}, variables map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *variableRef:
+		if resolved, exists := variables[v.name]; exists {
+			return resolved, nil
+		}
+		return nil, nil
+	case enumValue:
+		return string(v), nil
+	case listValue:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case objectValue:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = resolved
+		}
+		return object, nil
+	}
+	return value, nil
+}
+
+// resultMap 按查询中字段的顺序输出 JSON
+type resultMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newResultMap() *resultMap {
+	return &resultMap{values: make(map[string]interface{})}
+}
+
+func (m *resultMap) set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *resultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type executor struct {
+	schema    *Schema
+	ctx       context.Context
+	doc       *document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) addError(path []string, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: append([]string(nil), path...)})
+}
+
+// 同一响应键下合并的字段
+type collectedField struct {
+	key    string
+	fields []*field
+}
+
+// 展开片段并按响应键合并字段，处理 @skip 和 @include
+func (e *executor) collectFields(obj *Object, selections []selection, collected []*collectedField, visited map[string]bool) []*collectedField {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *field:
+			if !e.included(s.directives) {
+				continue
+			}
+			merged := false
+			for _, c := range collected {
+				if c.key == s.responseKey() {
+					c.fields = append(c.fields, s)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				collected = append(collected, &collectedField{key: s.responseKey(), fields: []*field{s}})
+			}
+		case *inlineFragment:
+			if !e.included(s.directives) || (s.typeCondition != "" && s.typeCondition != obj.Name) {
+				continue
+			}
+			collected = e.collectFields(obj, s.selections, collected, visited)
+		case *fragmentSpread:
+			if !e.included(s.directives) || visited[s.name] {
+				continue
+			}
+			frag, exists := e.doc.fragments[s.name]
+			if !exists {
+				e.addError(nil, "未定义的片段 %s", s.name)
+				continue
+			}
+			if frag.typeCondition != obj.Name {
+				continue
+			}
+			visited[s.name] = true
+			collected = e.collectFields(obj, frag.selections, collected, visited)
+		}
+	}
+	return collected
+}
+
+func (e *executor) included(directives []*directive) bool {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+		for _, arg := range d.arguments {
+			if arg.name != "if" {
+				continue
+			}
+			value, _ := resolveValue(arg.value, e.variables)
+			condition, _ := value.(bool)
+			if d.name == "skip" && condition {
+				return false
+			}
+			if d.name == "include" && !condition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// 对同一类型的一组对象执行选择集。按层级广度优先解析，
+// 同一层级所有对象的同一字段只调用一次 Batch，从而把 N+1 次查询合并为一次
+func (e *executor) executeSelections(obj *Object, sources []interface{}, selections []selection, path []string, depth int) []*resultMap {
+	results := make([]*resultMap, len(sources))
+	for i := range results {
+		results[i] = newResultMap()
+	}
+	if depth > maxQueryDepth {
+		e.addError(path, "查询嵌套超过 %d 层", maxQueryDepth)
+		return results
+	}
+
+	for _, collected := range e.collectFields(obj, selections, nil, make(map[string]bool)) {
+		first := collected.fields[0]
+		fieldPath := append(append([]string(nil), path...), collected.key)
+
+		if first.name == "__typename" {
+			for _, result := range results {
+				result.set(collected.key, obj.Name)
+			}
+			continue
+		}
+
+		def, exists := obj.fields[first.name]
+		if !exists {
+			e.addError(fieldPath, "类型 %s 没有字段 %s", obj.Name, first.name)
+			continue
+		}
+		for _, result := range results {
+			result.set(collected.key, nil)
+		}
+
+		args, err := e.coerceArguments(def, first.arguments)
+		if err != nil {
+			e.addError(fieldPath, "%v", err)
+			continue
+		}
+
+		values, err := e.resolveField(def, sources, args)
+		if err != nil {
+			e.addError(fieldPath, "%v", err)
+			continue
+		}
+
+		subSelections := make([]selection, 0)
+		for _, f := range collected.fields {
+			subSelections = append(subSelections, f.selections...)
+		}
+		completed := e.completeValues(def.Type, values, subSelections, fieldPath, depth)
+		for i, result := range results {
+			result.set(collected.key, completed[i])
+		}
+	}
+	return results
+}
+
+func (e *executor) coerceArguments(def *Field, arguments []*argument) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	for _, arg := range arguments {
+		known := false
+		for _, a := range def.Args {
+			if a.Name == arg.name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("字段 %s 没有参数 %s", def.Name, arg.name)
+		}
+		value, err := resolveValue(arg.value, e.variables)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			args[arg.name] = value
+		}
+	}
+	for _, a := range def.Args {
+		if _, exists := args[a.Name]; exists {
+			continue
+		}
+		if a.Default != nil {
+			args[a.Name] = a.Default
+		} else if strings.HasSuffix(a.Type, "!") {
+			return nil, fmt.Errorf("字段 %s 缺少必填参数 %s", def.Name, a.Name)
+		}
+	}
+	return args, nil
+}
+
+func (e *executor) resolveField(def *Field, sources []interface{}, args map[string]interface{}) ([]interface{}, error) {
+	if def.Batch != nil {
+		values, err := def.Batch(BatchParams{Context: e.ctx, Sources: sources, Args: args})
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != len(sources) {
+			return nil, fmt.Errorf("字段 %s 的批量解析结果数量不匹配", def.Name)
+		}
+		return values, nil
+	}
+
+	values := make([]interface{}, len(sources))
+	for i, source := range sources {
+		value, err := def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// 按字段类型补全解析结果：对象类型继续执行子选择集，列表展开后整体执行一次
+func (e *executor) completeValues(typ string, values []interface{}, selections []selection, path []string, depth int) []interface{} {
+	baseType, isList := unwrapType(typ)
+	obj, isObject := e.schema.objects[baseType]
+	completed := make([]interface{}, len(values))
+
+	if !isObject {
+		if len(selections) > 0 {
+			e.addError(path, "标量字段不能有子选择集")
+			return completed
+		}
+		for i, value := range values {
+			if !isNil(value) {
+				completed[i] = value
+			}
+		}
+		return completed
+	}
+	if len(selections) == 0 {
+		e.addError(path, "对象类型 %s 的字段必须指定子选择集", baseType)
+		return completed
+	}
+
+	// 展开所有非空对象，记录每个值对应的区间
+	items := make([]interface{}, 0)
+	counts := make([]int, len(values))
+	for i, value := range values {
+		if isNil(value) {
+			counts[i] = -1
+			continue
+		}
+		if !isList {
+			items = append(items, value)
+			counts[i] = 1
+			continue
+		}
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice {
+			e.addError(path, "字段应返回列表")
+			counts[i] = -1
+			continue
+		}
+		for j := 0; j < list.Len(); j++ {
+			item := list.Index(j)
+			if item.Kind() == reflect.Struct && item.CanAddr() {
+				item = item.Addr()
+			}
+			items = append(items, item.Interface())
+		}
+		counts[i] = list.Len()
+	}
+
+	nonNil := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if !isNil(item) {
+			nonNil = append(nonNil, item)
+		}
+	}
+	executed := e.executeSelections(obj, nonNil, selections, path, depth+1)
+
+	next := 0
+	itemResult := func(item interface{}) interface{} {
+		if isNil(item) {
+			return nil
+		}
+		result := executed[next]
+		next++
+		return result
+	}
+	offset := 0
+	for i, count := range counts {
+		if count < 0 {
+			continue
+		}
+		if !isList {
+			completed[i] = itemResult(items[offset])
+			offset++
+			continue
+		}
+		list := make([]interface{}, count)
+		for j := 0; j < count; j++ {
+			list[j] = itemResult(items[offset])
+			offset++
+		}
+		completed[i] = list
+	}
+	return completed
+}
+
+// 去掉非空和列表修饰，返回基础类型名和是否为列表
+func unwrapType(typ string) (string, bool) {
+	typ = strings.TrimSuffix(typ, "!")
+	if strings.HasPrefix(typ, "[") {
+		return strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(typ, "["), "]"), "!"), true
+	}
+	return typ, false
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
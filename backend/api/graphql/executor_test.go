@@ -0,0 +1,361 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testNFT struct {
+	ID    int64
+	Name  string
+	Owner string
+}
+
+type testUser struct {
+	Address string
+}
+
+type testNode struct {
+	Depth int
+}
+
+// 构造测试用的模式，batchCalls 记录每个批量字段被调用的次数
+func newTestSchema(batchCalls map[string]int) *Schema {
+	nfts := []*testNFT{
+		{ID: 1, Name: "Alpha", Owner: "0xA"},
+		{ID: 2, Name: "Beta", Owner: "0xB"},
+		{ID: 3, Name: "Gamma", Owner: "0xA"},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: []*Field{
+			{
+				Name: "nfts",
+				Type: "[NFT!]!",
+				Args: []Arg{{Name: "first", Type: "Int", Default: int64(2)}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					first := p.Args["first"].(int64)
+					if first > int64(len(nfts)) {
+						first = int64(len(nfts))
+					}
+					return nfts[:first], nil
+				},
+			},
+			{
+				Name: "nft",
+				Type: "NFT",
+				Args: []Arg{{Name: "id", Type: "Int!"}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					for _, nft := range nfts {
+						if nft.ID == p.Args["id"].(int64) {
+							return nft, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			{
+				Name: "echo",
+				Type: "String",
+				Args: []Arg{{Name: "value", Type: "String"}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					if value, exists := p.Args["value"]; exists {
+						return fmt.Sprint(value), nil
+					}
+					return "<unset>", nil
+				},
+			},
+			{
+				Name: "root",
+				Type: "Node!",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return &testNode{Depth: 1}, nil
+				},
+			},
+		},
+	}
+	nft := &Object{
+		Name: "NFT",
+		Fields: []*Field{
+			{Name: "id", Type: "Int!", Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testNFT).ID, nil }},
+			{Name: "name", Type: "String!", Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testNFT).Name, nil }},
+			{
+				Name: "owner",
+				Type: "User!",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					batchCalls["owner"]++
+					owners := make([]interface{}, len(p.Sources))
+					for i, source := range p.Sources {
+						owners[i] = &testUser{Address: source.(*testNFT).Owner}
+					}
+					return owners, nil
+				},
+			},
+			{
+				Name: "brokenOwner",
+				Type: "User",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					batchCalls["brokenOwner"]++
+					return []interface{}{&testUser{Address: "0xA"}}, nil
+				},
+			},
+		},
+	}
+	user := &Object{
+		Name: "User",
+		Fields: []*Field{
+			{Name: "address", Type: "String!", Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testUser).Address, nil }},
+		},
+	}
+	node := &Object{
+		Name: "Node",
+		Fields: []*Field{
+			{Name: "depth", Type: "Int!", Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testNode).Depth, nil }},
+			{Name: "child", Type: "Node!", Resolve: func(p ResolveParams) (interface{}, error) {
+				return &testNode{Depth: p.Source.(*testNode).Depth + 1}, nil
+			}},
+		},
+	}
+	return NewSchema(query, []*Object{nft, user, node}, nil)
+}
+
+// 嵌套 child 字段 n 层的查询
+func nestedNodeQuery(n int) string {
+	return "{ root { " + strings.Repeat("child { ", n) + "depth" + strings.Repeat(" }", n) + " } }"
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    Request
+		wantData   string // 为空表示 data 为 null
+		wantErrors []string
+		batchCalls map[string]int
+	}{
+		{
+			name:     "参数默认值",
+			request:  Request{Query: "{ nfts { id name } }"},
+			wantData: `{"nfts":[{"id":1,"name":"Alpha"},{"id":2,"name":"Beta"}]}`,
+		},
+		{
+			name:     "别名",
+			request:  Request{Query: "{ a: nft(id: 1) { name } b: nft(id: 2) { label: name } }"},
+			wantData: `{"a":{"name":"Alpha"},"b":{"label":"Beta"}}`,
+		},
+		{
+			name:     "别名相同的字段合并子选择集",
+			request:  Request{Query: "{ x: nft(id: 1) { id } x: nft(id: 1) { name } }"},
+			wantData: `{"x":{"id":1,"name":"Alpha"}}`,
+		},
+		{
+			name:     "别名与字段名冲突时按响应键合并",
+			request:  Request{Query: "{ nft(id: 3) { name: id name } }"},
+			wantData: `{"nft":{"name":3}}`,
+		},
+		{
+			name:     "__typename",
+			request:  Request{Query: "{ __typename nft(id: 1) { kind: __typename } }"},
+			wantData: `{"__typename":"Query","nft":{"kind":"NFT"}}`,
+		},
+		{
+			name:     "片段和内联片段",
+			request:  Request{Query: "query { nft(id: 2) { ...F ... on NFT { name } ... on User { address } } } fragment F on NFT { id }"},
+			wantData: `{"nft":{"id":2,"name":"Beta"}}`,
+		},
+		{
+			name:     "片段循环引用只展开一次",
+			request:  Request{Query: "{ nft(id: 1) { ...A } } fragment A on NFT { id ...B } fragment B on NFT { name ...A }"},
+			wantData: `{"nft":{"id":1,"name":"Alpha"}}`,
+		},
+		{
+			name:       "未定义的片段",
+			request:    Request{Query: "{ nft(id: 1) { id ...Missing } }"},
+			wantData:   `{"nft":{"id":1}}`,
+			wantErrors: []string{"未定义的片段 Missing"},
+		},
+		{
+			name:     "变量",
+			request:  Request{Query: "query Q($id: Int!) { nft(id: $id) { name } }", Variables: map[string]interface{}{"id": int64(3)}},
+			wantData: `{"nft":{"name":"Gamma"}}`,
+		},
+		{
+			name:     "变量默认值",
+			request:  Request{Query: "query Q($first: Int = 3) { nfts(first: $first) { id } }"},
+			wantData: `{"nfts":[{"id":1},{"id":2},{"id":3}]}`,
+		},
+		{
+			name:     "传入的变量覆盖默认值",
+			request:  Request{Query: "query Q($first: Int = 3) { nfts(first: $first) { id } }", Variables: map[string]interface{}{"first": int64(1)}},
+			wantData: `{"nfts":[{"id":1}]}`,
+		},
+		{
+			name:     "未传入的可选变量使用参数默认值",
+			request:  Request{Query: "query Q($first: Int) { nfts(first: $first) { id } }"},
+			wantData: `{"nfts":[{"id":1},{"id":2}]}`,
+		},
+		{
+			name:     "列表和对象中的变量",
+			request:  Request{Query: `query Q($v: String) { echo(value: [{a: $v}, "b"]) }`, Variables: map[string]interface{}{"v": "x"}},
+			wantData: `{"echo":"[map[a:x] b]"}`,
+		},
+		{
+			name:       "缺少必填变量",
+			request:    Request{Query: "query Q($id: Int!) { nft(id: $id) { name } }"},
+			wantErrors: []string{"缺少必填变量 $id"},
+		},
+		{
+			name:       "必填变量为 null",
+			request:    Request{Query: "query Q($id: Int!) { nft(id: $id) { name } }", Variables: map[string]interface{}{"id": nil}},
+			wantErrors: []string{"缺少必填变量 $id"},
+		},
+		{
+			name:       "缺少必填参数",
+			request:    Request{Query: "{ nft { name } }"},
+			wantData:   `{"nft":null}`,
+			wantErrors: []string{"字段 nft 缺少必填参数 id"},
+		},
+		{
+			name:       "未知参数",
+			request:    Request{Query: "{ nft(id: 1, foo: 2) { name } }"},
+			wantData:   `{"nft":null}`,
+			wantErrors: []string{"字段 nft 没有参数 foo"},
+		},
+		{
+			name:       "未知字段",
+			request:    Request{Query: "{ nft(id: 1) { id price } }"},
+			wantData:   `{"nft":{"id":1}}`,
+			wantErrors: []string{"类型 NFT 没有字段 price"},
+		},
+		{
+			name: "@include 和 @skip",
+			request: Request{
+				Query:     "query Q($yes: Boolean!, $no: Boolean!) { nft(id: 1) { id @skip(if: $yes) name @include(if: $no) owner @include(if: $yes) { address } } }",
+				Variables: map[string]interface{}{"yes": true, "no": false},
+			},
+			wantData: `{"nft":{"owner":{"address":"0xA"}}}`,
+		},
+		{
+			name:     "@skip 为 false 时保留字段",
+			request:  Request{Query: "{ nft(id: 1) { id @skip(if: false) name @include(if: true) } }"},
+			wantData: `{"nft":{"id":1,"name":"Alpha"}}`,
+		},
+		{
+			name:     "@skip 和 @include 同时存在时任一排除即跳过",
+			request:  Request{Query: "{ nft(id: 1) { id name @skip(if: true) @include(if: true) } }"},
+			wantData: `{"nft":{"id":1}}`,
+		},
+		{
+			name:     "片段上的指令",
+			request:  Request{Query: "{ nft(id: 1) { id ...F @skip(if: true) ... @include(if: false) { owner { address } } } } fragment F on NFT { name }"},
+			wantData: `{"nft":{"id":1}}`,
+		},
+		{
+			name:       "批量字段每层只解析一次",
+			request:    Request{Query: "{ nfts(first: 3) { owner { address } } }"},
+			wantData:   `{"nfts":[{"owner":{"address":"0xA"}},{"owner":{"address":"0xB"}},{"owner":{"address":"0xA"}}]}`,
+			batchCalls: map[string]int{"owner": 1},
+		},
+		{
+			name:       "批量字段返回数量不匹配",
+			request:    Request{Query: "{ nfts(first: 3) { id brokenOwner { address } } }"},
+			wantData:   `{"nfts":[{"id":1,"brokenOwner":null},{"id":2,"brokenOwner":null},{"id":3,"brokenOwner":null}]}`,
+			wantErrors: []string{"字段 brokenOwner 的批量解析结果数量不匹配"},
+			batchCalls: map[string]int{"brokenOwner": 1},
+		},
+		{
+			name:       "标量字段带子选择集",
+			request:    Request{Query: "{ nft(id: 1) { name { x } } }"},
+			wantData:   `{"nft":{"name":null}}`,
+			wantErrors: []string{"标量字段不能有子选择集"},
+		},
+		{
+			name:       "对象字段缺少子选择集",
+			request:    Request{Query: "{ nft(id: 1) }"},
+			wantData:   `{"nft":null}`,
+			wantErrors: []string{"对象类型 NFT 的字段必须指定子选择集"},
+		},
+		{
+			name:     "深度限制以内",
+			request:  Request{Query: nestedNodeQuery(maxQueryDepth - 2)},
+			wantData: `{"root":` + strings.Repeat(`{"child":`, maxQueryDepth-2) + fmt.Sprintf(`{"depth":%d}`, maxQueryDepth-1) + strings.Repeat("}", maxQueryDepth-2) + "}",
+		},
+		{
+			name:       "超过深度限制",
+			request:    Request{Query: nestedNodeQuery(maxQueryDepth - 1)},
+			wantData:   `{"root":` + strings.Repeat(`{"child":`, maxQueryDepth-1) + "{}" + strings.Repeat("}", maxQueryDepth-1) + "}",
+			wantErrors: []string{fmt.Sprintf("查询嵌套超过 %d 层", maxQueryDepth)},
+		},
+		{
+			name:       "语法错误",
+			request:    Request{Query: "{ nft(id: 1) { id }"},
+			wantErrors: []string{"查询意外结束"},
+		},
+		{
+			name:       "多个操作未指定名称",
+			request:    Request{Query: "query A { nfts { id } } query B { nfts { name } }"},
+			wantErrors: []string{"查询包含多个操作时必须指定 operationName"},
+		},
+		{
+			name:     "按名称选择操作",
+			request:  Request{Query: "query A { nfts { id } } query B { nft(id: 2) { name } }", OperationName: "B"},
+			wantData: `{"nft":{"name":"Beta"}}`,
+		},
+		{
+			name:       "操作不存在",
+			request:    Request{Query: "query A { nfts { id } }", OperationName: "C"},
+			wantErrors: []string{"未找到操作 C"},
+		},
+		{
+			name:       "不支持 mutation",
+			request:    Request{Query: "mutation { nfts { id } }"},
+			wantErrors: []string{"只支持 query 操作"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchCalls := make(map[string]int)
+			resp := newTestSchema(batchCalls).Execute(context.Background(), tt.request)
+
+			data, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Fatalf("序列化结果失败: %v", err)
+			}
+			wantData := tt.wantData
+			if wantData == "" {
+				wantData = "null"
+			}
+			if string(data) != wantData {
+				t.Errorf("data 为 %s，应为 %s", data, wantData)
+			}
+
+			messages := make([]string, len(resp.Errors))
+			for i, e := range resp.Errors {
+				messages[i] = e.Message
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.wantErrors, "\n") {
+				t.Errorf("errors 为 %q，应为 %q", messages, tt.wantErrors)
+			}
+
+			for name, want := range tt.batchCalls {
+				if batchCalls[name] != want {
+					t.Errorf("%s 批量解析调用了 %d 次，应为 %d 次", name, batchCalls[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteErrorPath(t *testing.T) {
+	resp := newTestSchema(make(map[string]int)).Execute(context.Background(), Request{Query: "{ list: nfts { id brokenOwner { address } } }"})
+	if len(resp.Errors) != 1 {
+		t.Fatalf("errors 数量为 %d，应为 1", len(resp.Errors))
+	}
+	if got := strings.Join(resp.Errors[0].Path, "."); got != "list.brokenOwner" {
+		t.Fatalf("错误路径为 %s，应为 list.brokenOwner", got)
+	}
+}
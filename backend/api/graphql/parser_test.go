@@ -0,0 +1,155 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDocumentMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{"空查询", "", "没有任何操作"},
+		{"只有注释", "# comment\n", "没有任何操作"},
+		{"未闭合的选择集", "{ nfts { id }", "意外结束"},
+		{"空选择集", "{ }", "选择集不能为空"},
+		{"无法识别的字符", "{ nfts % }", "无法识别的字符"},
+		{"未结束的字符串", `{ nft(id: "abc) { id } }`, "字符串未结束"},
+		{"字符串中换行", "{ nft(id: \"a\nb\") { id } }", "字符串未结束"},
+		{"未结束的块字符串", `{ nft(id: """abc) { id } }`, "字符串未结束"},
+		{"无效的转义", `{ nft(id: "\q") { id } }`, "无效的转义序列"},
+		{"无效的 unicode 转义", `{ nft(id: "\u12G4") { id } }`, "无效的转义序列"},
+		{"缺少数字", "{ nft(id: -) { id } }", "无效的数字"},
+		{"小数点后缺少数字", "{ nft(id: 1.) { id } }", "无效的数字"},
+		{"指数缺少数字", "{ nft(id: 1e) { id } }", "无效的数字"},
+		{"整数超出范围", "{ nft(id: 99999999999999999999) { id } }", "整数超出范围"},
+		{"参数缺少冒号", "{ nft(id 1) { id } }", "意外的"},
+		{"未闭合的参数", "{ nft(id: 1 { id } }", "意外的"},
+		{"默认值中使用变量", "query Q($a: Int = $b) { nft(id: $a) { id } }", "意外的"},
+		{"变量缺少类型", "query Q($a) { nft(id: $a) { id } }", "意外的"},
+		{"未闭合的列表类型", "query Q($a: [Int) { nft(id: $a) { id } }", "意外的"},
+		{"片段缺少 on", "fragment F NFT { id } { nft { ...F } }", "意外的"},
+		{"片段重复定义", "fragment F on NFT { id } fragment F on NFT { name } { nft { ...F } }", "片段 F 重复定义"},
+		{"顶层未知关键字", "schema { query: Query }", "意外的"},
+		{"别名后缺少字段名", "{ a: }", "意外的"},
+		{"未闭合的对象值", "{ nft(filter: {a: 1) { id } }", "意外的"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDocument(tt.query)
+			if err == nil {
+				t.Fatalf("parseDocument(%q) 应返回错误", tt.query)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("parseDocument(%q) 错误为 %q，应包含 %q", tt.query, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDocumentValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  interface{}
+	}{
+		{"整数", "42", int64(42)},
+		{"负整数", "-7", int64(-7)},
+		{"浮点数", "1.5", 1.5},
+		{"指数", "2e3", 2000.0},
+		{"字符串", `"a\"b\n"`, "a\"b\n"},
+		{"unicode 转义", `"\u4e2d"`, "中"},
+		{"块字符串原样保留", `"""a\nb"""`, `a\nb`},
+		{"布尔值", "true", true},
+		{"null", "null", nil},
+		{"枚举", "PRICE_ASC", enumValue("PRICE_ASC")},
+		{"变量", "$id", &variableRef{name: "id"}},
+		{"列表", "[1, \"a\", [true]]", listValue{int64(1), "a", listValue{true}}},
+		{"对象", "{a: 1, b: {c: $x}}", objectValue{"a": int64(1), "b": objectValue{"c": &variableRef{name: "x"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseDocument("{ nft(v: " + tt.value + ") { id } }")
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			f := doc.operations[0].selections[0].(*field)
+			if len(f.arguments) != 1 {
+				t.Fatalf("参数数量为 %d，应为 1", len(f.arguments))
+			}
+			if got := f.arguments[0].value; !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("参数值为 %#v，应为 %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDocumentStructure(t *testing.T) {
+	query := `
+		# 注释和逗号会被忽略
+		query Market($first: Int = 10, $ids: [ID!]!, $withOwner: Boolean!) @cached {
+			top: nfts(first: $first, ids: $ids) {
+				...NFTFields
+				owner @include(if: $withOwner)
+				... on NFT @skip(if: false) { name }
+				... { tokenID }
+			}
+		}
+		fragment NFTFields on NFT { id }
+		{ __typename }
+	`
+	doc, err := parseDocument(query)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(doc.operations) != 2 {
+		t.Fatalf("操作数量为 %d，应为 2", len(doc.operations))
+	}
+
+	op := doc.operations[0]
+	if op.kind != "query" || op.name != "Market" {
+		t.Fatalf("操作为 %s %s，应为 query Market", op.kind, op.name)
+	}
+	wantVariables := []*variableDefinition{
+		{name: "first", typ: "Int", defaultValue: int64(10), hasDefault: true},
+		{name: "ids", typ: "[ID!]!"},
+		{name: "withOwner", typ: "Boolean!"},
+	}
+	if !reflect.DeepEqual(op.variables, wantVariables) {
+		t.Fatalf("变量定义为 %#v，应为 %#v", op.variables, wantVariables)
+	}
+
+	top := op.selections[0].(*field)
+	if top.alias != "top" || top.name != "nfts" || top.responseKey() != "top" {
+		t.Fatalf("字段别名为 %q、名称为 %q", top.alias, top.name)
+	}
+	if len(top.selections) != 4 {
+		t.Fatalf("子选择数量为 %d，应为 4", len(top.selections))
+	}
+	if spread, ok := top.selections[0].(*fragmentSpread); !ok || spread.name != "NFTFields" {
+		t.Fatalf("第一个子选择应为片段 NFTFields，实际为 %#v", top.selections[0])
+	}
+	owner := top.selections[1].(*field)
+	if len(owner.directives) != 1 || owner.directives[0].name != "include" {
+		t.Fatalf("owner 的指令为 %#v", owner.directives)
+	}
+	if inline, ok := top.selections[2].(*inlineFragment); !ok || inline.typeCondition != "NFT" || len(inline.directives) != 1 {
+		t.Fatalf("第三个子选择应为带指令的内联片段，实际为 %#v", top.selections[2])
+	}
+	if inline, ok := top.selections[3].(*inlineFragment); !ok || inline.typeCondition != "" {
+		t.Fatalf("第四个子选择应为无类型条件的内联片段，实际为 %#v", top.selections[3])
+	}
+
+	frag, exists := doc.fragments["NFTFields"]
+	if !exists || frag.typeCondition != "NFT" {
+		t.Fatalf("片段 NFTFields 为 %#v", frag)
+	}
+	if anonymous := doc.operations[1]; anonymous.kind != "query" || anonymous.name != "" {
+		t.Fatalf("简写查询为 %s %q", anonymous.kind, anonymous.name)
+	}
+}
@@ -0,0 +1,545 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/domain"
+	"backend/usecase"
+
+	"gorm.io/gorm"
+)
+
+// connection 表示分页结果，offset 用于生成游标
+type connection struct {
+	totalCount int64
+	nodes      interface{}
+	offset     int
+	count      int
+}
+
+// pageInfo 表示分页信息
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   string
+}
+
+// 游标为 base64("offset:N")，N 为下一页的起始偏移
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), "offset:") {
+		return 0, fmt.Errorf("无效的游标")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), "offset:"))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("无效的游标")
+	}
+	return offset, nil
+}
+
+// 读取 first 和 after 分页参数
+func pageArgs(args map[string]interface{}) (int, int, error) {
+	offset, err := decodeCursor(stringArg(args, "after"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return offset, intArg(args, "first"), nil
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// 整数参数可能来自查询字面量 (int64) 或 JSON 变量 (float64)
+func intArg(args map[string]interface{}, name string) int {
+	return toInt(args[name])
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func listArg(args map[string]interface{}, name string) []interface{} {
+	value, _ := args[name].([]interface{})
+	return value
+}
+
+// 将 traits 参数转为属性筛选条件
+func traitFilterArg(args map[string]interface{}) *domain.TraitFilter {
+	traits := listArg(args, "traits")
+	if len(traits) == 0 {
+		return nil
+	}
+	filter := &domain.TraitFilter{}
+	for _, item := range traits {
+		input, _ := item.(map[string]interface{})
+		condition := domain.TraitCondition{TraitType: stringArg(input, "traitType")}
+		for _, value := range listArg(input, "values") {
+			if s, ok := value.(string); ok {
+				condition.Values = append(condition.Values, s)
+			}
+		}
+		if condition.TraitType != "" && len(condition.Values) > 0 {
+			filter.Traits = append(filter.Traits, condition)
+		}
+	}
+	return filter
+}
+
+func nftKeyOf(nft *domain.NFTView) domain.NFTKey {
+	return domain.NFTKey{ContractAddress: nft.ContractAddress, TokenID: nft.TokenID}
+}
+
+// 将取值函数包装为解析函数
+func get(fn func(source interface{}) interface{}) func(p ResolveParams) (interface{}, error) {
+	return func(p ResolveParams) (interface{}, error) {
+		return fn(p.Source), nil
+	}
+}
+
+func nftField(name, typ string, fn func(nft *domain.NFTView) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.NFTView)) })}
+}
+
+func orderField(name, typ string, fn func(order *domain.OrderView) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.OrderView)) })}
+}
+
+func transferField(name, typ string, fn func(event *domain.NFTTransferEventView) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.NFTTransferEventView)) })}
+}
+
+func collectionField(name, typ string, fn func(collection *domain.NFTCollection) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.NFTCollection)) })}
+}
+
+func profileField(name string, fn func(profile *domain.ProfileSummary) interface{}) *Field {
+	return &Field{Name: name, Type: "String", Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.ProfileSummary)) })}
+}
+
+func tokenField(name, typ string, fn func(token *domain.PaymentToken) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.PaymentToken)) })}
+}
+
+func floorField(name, typ string, fn func(floor *domain.TokenFloor) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.TokenFloor)) })}
+}
+
+func statsField(name, typ string, fn func(stats *domain.CollectionStats) interface{}) *Field {
+	return &Field{Name: name, Type: typ, Resolve: get(func(s interface{}) interface{} { return fn(s.(*domain.CollectionStats)) })}
+}
+
+// 生成 XxxConnection 类型
+func connectionObject(name, nodeType string) *Object {
+	return &Object{
+		Name: name,
+		Fields: []*Field{
+			{Name: "totalCount", Type: "Int!", Resolve: get(func(s interface{}) interface{} { return s.(*connection).totalCount })},
+			{Name: "nodes", Type: "[" + nodeType + "!]!", Resolve: get(func(s interface{}) interface{} { return s.(*connection).nodes })},
+			{Name: "pageInfo", Type: "PageInfo!", Resolve: get(func(s interface{}) interface{} {
+				c := s.(*connection)
+				info := &pageInfo{hasNextPage: int64(c.offset+c.count) < c.totalCount}
+				if c.count > 0 {
+					info.endCursor = encodeCursor(c.offset + c.count)
+				}
+				return info
+			})},
+		},
+	}
+}
+
+var pageArgDefs = []Arg{
+	{Name: "first", Type: "Int", Default: int64(20)},
+	{Name: "after", Type: "String"},
+}
+
+// NewMarketSchema 创建市场数据的 GraphQL 模式。
+// NFT 的系列、属性、订单和转移记录以及订单的 NFT 都按层级批量查询，避免逐条访问数据库
+func NewMarketSchema(nftUC *usecase.NFTUseCase, marketUC *usecase.MarketUseCase) *Schema {
+	nftsPage := func(contractAddress string, args map[string]interface{}) (interface{}, error) {
+		offset, limit, err := pageArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		nfts, total, err := nftUC.GetNFTsPage(contractAddress, stringArg(args, "owner"), traitFilterArg(args), offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		return &connection{totalCount: total, nodes: nfts, offset: offset, count: len(nfts)}, nil
+	}
+	nftsArgs := append([]Arg{{Name: "owner", Type: "String"}, {Name: "traits", Type: "[TraitInput!]"}}, pageArgDefs...)
+
+	// NFT 持有者、订单卖家等地址的资料已由用例附加，这里直接返回
+	profile := &Object{
+		Name: "Profile",
+		Fields: []*Field{
+			{Name: "address", Type: "String!", Resolve: get(func(s interface{}) interface{} { return s.(*domain.ProfileSummary).Address })},
+			profileField("username", func(p *domain.ProfileSummary) interface{} { return p.Username }),
+			profileField("displayName", func(p *domain.ProfileSummary) interface{} { return p.DisplayName }),
+			profileField("avatarImage", func(p *domain.ProfileSummary) interface{} { return p.AvatarImage }),
+		},
+	}
+
+	paymentToken := &Object{
+		Name: "PaymentToken",
+		Fields: []*Field{
+			tokenField("address", "String!", func(t *domain.PaymentToken) interface{} { return t.Address }),
+			tokenField("name", "String", func(t *domain.PaymentToken) interface{} { return t.Name }),
+			tokenField("symbol", "String", func(t *domain.PaymentToken) interface{} { return t.Symbol }),
+			tokenField("decimals", "Int!", func(t *domain.PaymentToken) interface{} { return t.Decimals }),
+		},
+	}
+
+	attribute := &Object{
+		Name: "Attribute",
+		Fields: []*Field{
+			{Name: "traitType", Type: "String!", Resolve: get(func(s interface{}) interface{} { return s.(*domain.NFTAttribute).TraitType })},
+			{Name: "value", Type: "String!", Resolve: get(func(s interface{}) interface{} { return s.(*domain.NFTAttribute).Value })},
+		},
+	}
+
+	transfer := &Object{
+		Name: "Transfer",
+		Fields: []*Field{
+			transferField("type", "String!", func(e *domain.NFTTransferEventView) interface{} { return e.EventType }),
+			transferField("from", "String!", func(e *domain.NFTTransferEventView) interface{} { return e.FromAddress }),
+			transferField("to", "String!", func(e *domain.NFTTransferEventView) interface{} { return e.ToAddress }),
+			transferField("fromProfile", "Profile", func(e *domain.NFTTransferEventView) interface{} { return e.FromProfile }),
+			transferField("toProfile", "Profile", func(e *domain.NFTTransferEventView) interface{} { return e.ToProfile }),
+			transferField("transactionHash", "String!", func(e *domain.NFTTransferEventView) interface{} { return e.TransactionHash }),
+			transferField("blockNumber", "Int!", func(e *domain.NFTTransferEventView) interface{} { return e.BlockNumber }),
+			transferField("timestamp", "String!", func(e *domain.NFTTransferEventView) interface{} { return e.BlockTimestamp.Format(time.RFC3339) }),
+		},
+	}
+
+	tokenFloor := &Object{
+		Name: "TokenFloor",
+		Fields: []*Field{
+			floorField("tokenAddress", "String!", func(f *domain.TokenFloor) interface{} { return f.TokenAddress }),
+			floorField("paymentToken", "PaymentToken", func(f *domain.TokenFloor) interface{} { return f.PaymentToken }),
+			floorField("price", "String!", func(f *domain.TokenFloor) interface{} { return f.Price }),
+			floorField("priceFormatted", "String!", func(f *domain.TokenFloor) interface{} { return f.PriceFormatted }),
+			floorField("priceUSD", "Float", func(f *domain.TokenFloor) interface{} { return f.PriceUSD }),
+			floorField("listedCount", "Int!", func(f *domain.TokenFloor) interface{} { return f.ListedCount }),
+		},
+	}
+
+	stats := &Object{
+		Name: "CollectionStats",
+		Fields: []*Field{
+			statsField("listedCount", "Int!", func(s *domain.CollectionStats) interface{} { return s.ListedCount }),
+			statsField("floorPriceUSD", "Float", func(s *domain.CollectionStats) interface{} { return s.FloorPriceUSD }),
+			statsField("floors", "[TokenFloor!]!", func(s *domain.CollectionStats) interface{} { return s.Floors }),
+			statsField("salesCount", "Int!", func(s *domain.CollectionStats) interface{} { return s.SalesCount }),
+			statsField("volumeUSD", "Float!", func(s *domain.CollectionStats) interface{} { return s.VolumeUSD }),
+			statsField("unpricedSales", "Int!", func(s *domain.CollectionStats) interface{} { return s.UnpricedSales }),
+		},
+	}
+
+	collection := &Object{
+		Name: "Collection",
+		Fields: []*Field{
+			collectionField("address", "String!", func(c *domain.NFTCollection) interface{} { return c.ContractAddress }),
+			collectionField("name", "String", func(c *domain.NFTCollection) interface{} { return c.Name }),
+			collectionField("symbol", "String", func(c *domain.NFTCollection) interface{} { return c.Symbol }),
+			collectionField("iconURI", "String", func(c *domain.NFTCollection) interface{} { return c.TokenIconURI }),
+			{
+				Name: "nfts",
+				Type: "NFTConnection!",
+				Args: nftsArgs,
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return nftsPage(p.Source.(*domain.NFTCollection).ContractAddress, p.Args)
+				},
+			},
+			{
+				Name: "stats",
+				Type: "CollectionStats!",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return marketUC.GetCollectionStats(p.Source.(*domain.NFTCollection).ContractAddress)
+				},
+			},
+		},
+	}
+
+	nft := &Object{
+		Name: "NFT",
+		Fields: []*Field{
+			nftField("contract", "String!", func(n *domain.NFTView) interface{} { return n.ContractAddress }),
+			nftField("tokenId", "Int!", func(n *domain.NFTView) interface{} { return n.TokenID }),
+			nftField("name", "String", func(n *domain.NFTView) interface{} { return n.Name }),
+			nftField("description", "String", func(n *domain.NFTView) interface{} { return n.Description }),
+			nftField("image", "String", func(n *domain.NFTView) interface{} { return n.Image }),
+			nftField("tokenURI", "String", func(n *domain.NFTView) interface{} { return n.TokenURI }),
+			nftField("owner", "String!", func(n *domain.NFTView) interface{} { return n.Owner }),
+			nftField("ownerProfile", "Profile", func(n *domain.NFTView) interface{} { return n.OwnerProfile }),
+			{
+				Name: "collection",
+				Type: "Collection",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					addresses := make([]string, len(p.Sources))
+					for i, source := range p.Sources {
+						addresses[i] = source.(*domain.NFTView).ContractAddress
+					}
+					collections, err := nftUC.GetCollectionsByAddresses(addresses)
+					if err != nil {
+						return nil, err
+					}
+					results := make([]interface{}, len(p.Sources))
+					for i, address := range addresses {
+						results[i] = collections[strings.ToLower(address)]
+					}
+					return results, nil
+				},
+			},
+			{
+				Name: "attributes",
+				Type: "[Attribute!]!",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					ids := make([]uint, len(p.Sources))
+					for i, source := range p.Sources {
+						ids[i] = source.(*domain.NFTView).ID
+					}
+					attributes, err := nftUC.GetAttributesByNFTIDs(ids)
+					if err != nil {
+						return nil, err
+					}
+					results := make([]interface{}, len(p.Sources))
+					for i, id := range ids {
+						if list, exists := attributes[id]; exists {
+							results[i] = list
+						} else {
+							results[i] = []domain.NFTAttribute{}
+						}
+					}
+					return results, nil
+				},
+			},
+			{
+				Name:        "order",
+				Type:        "Order",
+				Description: "最新的链上订单",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					keys := make([]domain.NFTKey, len(p.Sources))
+					for i, source := range p.Sources {
+						keys[i] = nftKeyOf(source.(*domain.NFTView))
+					}
+					orders, err := marketUC.GetOrdersByNFTKeys(keys)
+					if err != nil {
+						return nil, err
+					}
+					results := make([]interface{}, len(p.Sources))
+					for i, key := range keys {
+						results[i] = orders[key.String()]
+					}
+					return results, nil
+				},
+			},
+			{
+				Name: "transfers",
+				Type: "[Transfer!]!",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					keys := make([]domain.NFTKey, len(p.Sources))
+					for i, source := range p.Sources {
+						keys[i] = nftKeyOf(source.(*domain.NFTView))
+					}
+					transfers, err := nftUC.GetTransfersByKeys(keys)
+					if err != nil {
+						return nil, err
+					}
+					results := make([]interface{}, len(p.Sources))
+					for i, key := range keys {
+						if list, exists := transfers[key.String()]; exists {
+							results[i] = list
+						} else {
+							results[i] = []domain.NFTTransferEventView{}
+						}
+					}
+					return results, nil
+				},
+			},
+		},
+	}
+
+	order := &Object{
+		Name: "Order",
+		Fields: []*Field{
+			orderField("index", "Int!", func(o *domain.OrderView) interface{} { return o.ID - 1 }),
+			orderField("contract", "String!", func(o *domain.OrderView) interface{} { return o.NFTContractAddress }),
+			orderField("tokenId", "Int!", func(o *domain.OrderView) interface{} { return o.TokenID }),
+			orderField("seller", "String!", func(o *domain.OrderView) interface{} { return o.Seller }),
+			orderField("sellerProfile", "Profile", func(o *domain.OrderView) interface{} { return o.SellerProfile }),
+			orderField("tokenAddress", "String!", func(o *domain.OrderView) interface{} { return o.TokenAddress }),
+			orderField("paymentToken", "PaymentToken", func(o *domain.OrderView) interface{} { return o.PaymentToken }),
+			orderField("price", "String!", func(o *domain.OrderView) interface{} { return o.Price }),
+			orderField("priceFormatted", "String!", func(o *domain.OrderView) interface{} { return o.PriceFormatted }),
+			orderField("priceUSD", "Float", func(o *domain.OrderView) interface{} { return o.PriceUSD }),
+			orderField("status", "Int!", func(o *domain.OrderView) interface{} { return o.Status }),
+			orderField("invalid", "Boolean!", func(o *domain.OrderView) interface{} { return o.Invalid }),
+			orderField("invalidReason", "String", func(o *domain.OrderView) interface{} { return o.InvalidReason }),
+			{
+				Name: "nft",
+				Type: "NFT",
+				Batch: func(p BatchParams) ([]interface{}, error) {
+					keys := make([]domain.NFTKey, len(p.Sources))
+					for i, source := range p.Sources {
+						o := source.(*domain.OrderView)
+						keys[i] = domain.NFTKey{ContractAddress: o.NFTContractAddress, TokenID: o.TokenID}
+					}
+					nfts, err := nftUC.GetNFTsByKeys(keys)
+					if err != nil {
+						return nil, err
+					}
+					results := make([]interface{}, len(p.Sources))
+					for i, key := range keys {
+						results[i] = nfts[key.String()]
+					}
+					return results, nil
+				},
+			},
+		},
+	}
+
+	page := &Object{
+		Name: "PageInfo",
+		Fields: []*Field{
+			{Name: "hasNextPage", Type: "Boolean!", Resolve: get(func(s interface{}) interface{} { return s.(*pageInfo).hasNextPage })},
+			{Name: "endCursor", Type: "String", Resolve: get(func(s interface{}) interface{} { return s.(*pageInfo).endCursor })},
+		},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: []*Field{
+			{
+				Name: "collections",
+				Type: "CollectionConnection!",
+				Args: pageArgDefs,
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					offset, limit, err := pageArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					collections, total, err := nftUC.GetCollectionsPage(offset, limit)
+					if err != nil {
+						return nil, err
+					}
+					return &connection{totalCount: total, nodes: collections, offset: offset, count: len(collections)}, nil
+				},
+			},
+			{
+				Name: "collection",
+				Type: "Collection",
+				Args: []Arg{{Name: "address", Type: "String!"}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					address := stringArg(p.Args, "address")
+					collections, err := nftUC.GetCollectionsByAddresses([]string{address})
+					if err != nil {
+						return nil, err
+					}
+					return collections[strings.ToLower(address)], nil
+				},
+			},
+			{
+				Name: "nfts",
+				Type: "NFTConnection!",
+				Args: append([]Arg{{Name: "collection", Type: "String"}}, nftsArgs...),
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return nftsPage(stringArg(p.Args, "collection"), p.Args)
+				},
+			},
+			{
+				Name: "nft",
+				Type: "NFT",
+				Args: []Arg{{Name: "contract", Type: "String!"}, {Name: "tokenId", Type: "Int!"}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					key := domain.NFTKey{ContractAddress: stringArg(p.Args, "contract"), TokenID: uint(intArg(p.Args, "tokenId"))}
+					nfts, err := nftUC.GetNFTsByKeys([]domain.NFTKey{key})
+					if err != nil {
+						return nil, err
+					}
+					return nfts[key.String()], nil
+				},
+			},
+			{
+				Name: "orders",
+				Type: "OrderConnection!",
+				Args: append([]Arg{{Name: "collection", Type: "String"}, {Name: "seller", Type: "String"}, {Name: "status", Type: "[Int!]"}}, pageArgDefs...),
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					offset, limit, err := pageArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					statuses := make([]uint, 0)
+					for _, value := range listArg(p.Args, "status") {
+						statuses = append(statuses, uint(toInt(value)))
+					}
+					orders, total, err := marketUC.GetOrdersPage(stringArg(p.Args, "collection"), stringArg(p.Args, "seller"), statuses, offset, limit)
+					if err != nil {
+						return nil, err
+					}
+					return &connection{totalCount: total, nodes: orders, offset: offset, count: len(orders)}, nil
+				},
+			},
+			{
+				Name: "order",
+				Type: "Order",
+				Args: []Arg{{Name: "index", Type: "Int!"}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					index := intArg(p.Args, "index")
+					if index < 0 {
+						return nil, nil
+					}
+					order, err := marketUC.GetOrderViewByID(uint(index))
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return nil, nil
+					}
+					return order, err
+				},
+			},
+			{
+				Name: "stats",
+				Type: "CollectionStats!",
+				Args: []Arg{{Name: "collection", Type: "String!"}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return marketUC.GetCollectionStats(stringArg(p.Args, "collection"))
+				},
+			},
+		},
+	}
+
+	return NewSchema(query, []*Object{
+		collection,
+		nft,
+		attribute,
+		order,
+		transfer,
+		profile,
+		paymentToken,
+		stats,
+		tokenFloor,
+		connectionObject("CollectionConnection", "Collection"),
+		connectionObject("NFTConnection", "NFT"),
+		connectionObject("OrderConnection", "Order"),
+		page,
+	}, []*InputObject{
+		{Name: "TraitInput", Fields: []Arg{{Name: "traitType", Type: "String!"}, {Name: "values", Type: "[String!]!"}}},
+	})
+}
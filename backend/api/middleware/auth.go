@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"backend/domain"
+	"backend/usecase"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gin.Context 中保存当前会话的键
+const sessionKey = "authSession"
+
+// 从 Authorization: Bearer <token> 中读取令牌。allowQuery 为 true 时 GET 请求也可以使用 access_token 查询参数，
+// 只用于浏览器 EventSource 无法设置请求头的 SSE 接口，其他接口不接受，避免令牌出现在访问日志中
+func bearerToken(ctx *gin.Context, allowQuery bool) string {
+	header := ctx.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	if allowQuery && ctx.Request.Method == http.MethodGet {
+		return ctx.Query("access_token")
+	}
+	return ""
+}
+
+// RequireAuth 要求请求携带有效的会话令牌，否则返回 401
+func RequireAuth(authUC *usecase.AuthUseCase) gin.HandlerFunc {
+	return requireAuth(authUC, false)
+}
+
+// RequireAuthSSE 与 RequireAuth 相同，但同时接受 access_token 查询参数，只用于 SSE 接口
+func RequireAuthSSE(authUC *usecase.AuthUseCase) gin.HandlerFunc {
+	return requireAuth(authUC, true)
+}
+
+func requireAuth(authUC *usecase.AuthUseCase, allowQuery bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := bearerToken(ctx, allowQuery)
+		if token == "" {
+			ctx.Error(usecase.ErrUnauthorized.WithMessage("未登录"))
+			ctx.Abort()
+			return
+		}
+		session, err := authUC.Authenticate(token)
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Set(sessionKey, session)
+		ctx.Next()
+	}
+}
+
+// RequireAdmin 要求当前会话的地址在管理员列表中，否则返回 403，需放在 RequireAuth 之后
+func RequireAdmin(authUC *usecase.AuthUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		address, ok := AuthenticatedAddress(ctx)
+		if !ok {
+			ctx.Error(usecase.ErrUnauthorized.WithMessage("未登录"))
+			ctx.Abort()
+			return
+		}
+		if !authUC.IsAdmin(address) {
+			ctx.Error(usecase.ErrForbidden.WithMessage("无权访问管理接口"))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// OptionalAuth 在携带有效令牌时记录会话，未登录的请求照常处理
+func OptionalAuth(authUC *usecase.AuthUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token := bearerToken(ctx, false); token != "" {
+			if session, err := authUC.Authenticate(token); err == nil {
+				ctx.Set(sessionKey, session)
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// CurrentSession 返回当前请求的会话
+func CurrentSession(ctx *gin.Context) (*domain.Session, bool) {
+	value, exists := ctx.Get(sessionKey)
+	if !exists {
+		return nil, false
+	}
+	session, ok := value.(*domain.Session)
+	return session, ok
+}
+
+// AuthenticatedAddress 返回当前登录的钱包地址（EIP-55 校验和格式）
+func AuthenticatedAddress(ctx *gin.Context) (string, bool) {
+	session, ok := CurrentSession(ctx)
+	if !ok {
+		return "", false
+	}
+	return session.Address, true
+}
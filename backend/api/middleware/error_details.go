@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 错误详情的英文翻译。key 为 fmt.Errorf("%w: 详情", ErrX) 和 domain.InvalidParam 使用的中文详情模板，
+// 模板中的 %d、%s、%v、%q 依次匹配实际的参数，并按相同顺序填入英文翻译
+var englishDetails = map[string]string{
+	// 挂单、出价和拍卖
+	"地址格式错误":                  "Malformed address",
+	"价格必须为大于0的整数":             "Price must be an integer greater than 0",
+	"salt必须为非负整数":             "Salt must be a non-negative integer",
+	"nonce必须为非负整数":            "Nonce must be a non-negative integer",
+	"过期时间必须晚于当前时间":            "Expiry must be later than the current time",
+	"nonce已失效，当前nonce为 %d":    "Nonce is no longer valid, the current nonce is %d",
+	"新的nonce必须大于当前nonce":      "The new nonce must be greater than the current nonce",
+	"支付代币不是有效的ERC-20合约":       "Payment token is not a valid ERC-20 contract",
+	"市场不接受该支付代币":              "The market does not accept this payment token",
+	"NFT不存在或合约不支持ERC-721":     "NFT does not exist or the contract does not support ERC-721",
+	"卖家未持有该NFT":               "Seller does not own the NFT",
+	"市场合约未获授权":                "The market contract is not approved",
+	"未知的挂单状态 %s":              "Unknown listing status %s",
+	"系列出价的tokenId必须为0":        "tokenId must be 0 for collection offers",
+	"无法读取买家的代币余额":             "Unable to read the bidder's token balance",
+	"买家余额不足":                  "Insufficient bidder balance",
+	"买家未授权市场合约使用足够的代币":        "Bidder has not approved the market contract to spend enough tokens",
+	"结束时间必须晚于开始时间和当前时间":       "End time must be later than the start time and the current time",
+	"保留价必须为大于0的整数":            "Reserve price must be an integer greater than 0",
+	"最小加价幅度必须为大于0的整数":         "Minimum increment must be an integer greater than 0",
+	"起始价必须为大于0的整数":            "Start price must be an integer greater than 0",
+	"结束价必须为大于0且低于起始价的整数":      "End price must be an integer greater than 0 and below the start price",
+	"荷兰式拍卖不支持延时":              "Dutch auctions do not support time extensions",
+	"拍卖类型必须为 english 或 dutch": "Auction type must be english or dutch",
+	"该NFT已有进行中的拍卖 (拍卖ID: %d)": "The NFT already has an active auction (auction ID: %d)",
+	"已有出价的拍卖不能取消":             "Auctions with bids cannot be cancelled",
+	"未知的拍卖状态 %s":              "Unknown auction status %s",
+	"拍卖尚未开始":                  "The auction has not started yet",
+	"卖家不能参与自己的拍卖":             "Sellers cannot bid on their own auction",
+	"出价签名的有效期必须至少持续到拍卖结束后 %s": "The bid signature must remain valid for at least %s after the auction ends",
+	"重复的出价":                   "Duplicate bid",
+	"出价不能低于 %s":               "Bid must be at least %s",
+
+	// 签名
+	"签名格式错误":      "Malformed signature",
+	"签名已过期":       "Signature has expired",
+	"签名者不一致":      "Signer does not match",
+	"签名者与买家不一致":   "Signer does not match the buyer",
+	"签名者与登录地址不一致": "Signer does not match the login address",
+	"合约钱包未认可该签名":  "The contract wallet did not accept the signature",
+	"r 格式错误":      "Malformed r",
+	"s 格式错误":      "Malformed s",
+
+	// 登录消息
+	"缺少登录声明":               "Missing sign-in statement",
+	"缺少域名":                 "Missing domain",
+	"地址必须为EIP-55校验和格式":     "Address must be in EIP-55 checksum format",
+	"地址后缺少空行":              "Missing blank line after the address",
+	"声明后缺少空行":              "Missing blank line after the statement",
+	"资源格式错误":               "Malformed resources",
+	"无法解析的行 %q":            "Unparsable line %q",
+	"未知或顺序错误的字段 %s":        "Unknown or out-of-order field %s",
+	"缺少字段 %s":              "Missing field %s",
+	"不支持的版本 %s":            "Unsupported version %s",
+	"Chain ID 格式错误":        "Malformed Chain ID",
+	"Nonce 必须为至少8位的字母数字":   "Nonce must be at least 8 alphanumeric characters",
+	"Issued At 格式错误":       "Malformed Issued At",
+	"Expiration Time 格式错误": "Malformed Expiration Time",
+	"Not Before 格式错误":      "Malformed Not Before",
+	"URI 必须是http或https地址":  "URI must be an http or https address",
+	"URI 与域名 %s 不一致":       "URI does not match the domain %s",
+	"不受信任的域名 %s":           "Untrusted domain %s",
+	"Chain ID 与市场所在链不一致":   "Chain ID does not match the market's chain",
+	"Issued At 晚于当前时间":     "Issued At is later than the current time",
+	"消息已过期":                "The message has expired",
+	"消息尚未生效":               "The message is not valid yet",
+	"nonce 无效、已使用或已过期":     "Nonce is invalid, used or expired",
+
+	// 资料
+	"用户名只能包含字母、数字和下划线，长度3到30": "Username may only contain letters, digits and underscores, 3 to 30 characters long",
+	"用户名不能是地址":              "Username cannot be an address",
+	"显示名称不能超过50个字符":         "Display name cannot exceed 50 characters",
+	"简介不能超过500个字符":          "Bio cannot exceed 500 characters",
+	"Twitter 用户名格式错误":       "Malformed Twitter username",
+	"Discord 用户名不能超过64个字符":  "Discord username cannot exceed 64 characters",
+	"网站必须为 http 或 https 链接": "Website must be an http or https link",
+	"头像NFT合约地址格式错误":         "Malformed avatar NFT contract address",
+	"头像NFT未找到":              "Avatar NFT not found",
+	"只能使用自己持有的NFT作为头像":      "Only NFTs you own can be used as an avatar",
+
+	// 提醒和 Webhook
+	"地板价提醒需要指定系列和价格":     "Floor price alerts require a collection and a price",
+	"属性挂单提醒需要指定系列和属性":    "Trait listing alerts require a collection and a trait",
+	"未知的提醒类型 %s":         "Unknown alert type %s",
+	"无效的合约地址":            "Invalid contract address",
+	"价格必须是代币最小单位的非负整数":   "Price must be a non-negative integer in the token's smallest unit",
+	"指定价格时需要指定支付代币":      "A payment token is required when a price is given",
+	"无效的代币地址":            "Invalid token address",
+	"未配置邮件服务":            "Email delivery is not configured",
+	"无效的邮箱地址":            "Invalid email address",
+	"未知的提醒渠道 %s":         "Unknown alert channel %s",
+	"URL不能指向本机或内网地址":     "URL cannot point to localhost or a private network address",
+	"无法解析主机名 %s":         "Unable to resolve host %s",
+	"URL必须是http或https地址": "URL must be an http or https address",
+	"未知的事件类型 %s":         "Unknown event type %s",
+	"无效的地址":              "Invalid address",
+	"未知的投递状态 %s":         "Unknown delivery status %s",
+
+	// 批量查询和K线
+	"查询列表为空":         "The lookup list is empty",
+	"单次最多查询 %d 个NFT": "At most %d NFTs can be looked up at once",
+	"无效的合约地址 %s":     "Invalid contract address %s",
+	"可选值为 1h、1d、1w":  "Allowed values are 1h, 1d and 1w",
+
+	// 请求参数
+	"无效的ID":            "Invalid ID",
+	"无效的任务ID":          "Invalid job ID",
+	"无效的订单ID":          "Invalid order ID",
+	"无效的出价ID":          "Invalid offer ID",
+	"无效的拍卖ID":          "Invalid auction ID",
+	"无效的tokenID":       "Invalid tokenID",
+	"无效的TokenID":       "Invalid tokenID",
+	"无效的卖家地址":          "Invalid seller address",
+	"无效的买家地址":          "Invalid buyer address",
+	"无效的名单状态":          "Invalid list status",
+	"无效的排序方式":          "Invalid sort order",
+	"无效的搜索类型":          "Invalid search type",
+	"搜索关键词不能为空":        "Search query cannot be empty",
+	"无效的limit":         "Invalid limit",
+	"无效的limit参数":       "Invalid limit parameter",
+	"无效的before参数":      "Invalid before parameter",
+	"无效的deadline":      "Invalid deadline",
+	"无效的时间参数: %s":      "Invalid time parameter: %s",
+	"无效的 variables 参数": "Invalid variables parameter",
+	"缺少 query":         "Missing query",
+}
+
+// 模板中的格式化动词
+var detailVerb = regexp.MustCompile(`%[dsvq]`)
+
+type detailTemplate struct {
+	pattern *regexp.Regexp
+	english string
+}
+
+// 带参数的模板，按固定文字从长到短排列，使更具体的模板优先匹配
+var detailTemplates = compileDetailTemplates(englishDetails)
+
+func compileDetailTemplates(translations map[string]string) []detailTemplate {
+	var templates []detailTemplate
+	for format, english := range translations {
+		verbs := detailVerb.FindAllStringIndex(format, -1)
+		if len(verbs) == 0 {
+			continue
+		}
+		var pattern strings.Builder
+		pattern.WriteString("^")
+		last := 0
+		for _, verb := range verbs {
+			pattern.WriteString(regexp.QuoteMeta(format[last:verb[0]]))
+			pattern.WriteString("(.*?)")
+			last = verb[1]
+		}
+		pattern.WriteString(regexp.QuoteMeta(format[last:]))
+		pattern.WriteString("$")
+		templates = append(templates, detailTemplate{pattern: regexp.MustCompile(pattern.String()), english: english})
+	}
+	literal := func(t detailTemplate) int { return len(t.pattern.String()) }
+	sort.Slice(templates, func(i, j int) bool {
+		return literal(templates[i]) > literal(templates[j])
+	})
+	return templates
+}
+
+// 将中文详情翻译为英文。没有翻译的中文详情返回空串，不含中文的详情（如请求校验器和合约返回的说明）原样返回
+func englishDetail(details string) string {
+	if english, exists := englishDetails[details]; exists {
+		return english
+	}
+	for _, template := range detailTemplates {
+		match := template.pattern.FindStringSubmatch(details)
+		if match == nil {
+			continue
+		}
+		args := match[1:]
+		return detailVerb.ReplaceAllStringFunc(template.english, func(string) string {
+			arg := args[0]
+			args = args[1:]
+			return arg
+		})
+	}
+	if strings.IndexFunc(details, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0 {
+		return ""
+	}
+	return details
+}
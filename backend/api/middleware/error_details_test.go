@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestEnglishDetail(t *testing.T) {
+	tests := []struct {
+		name    string
+		details string
+		want    string
+	}{
+		{"无参数", "买家余额不足", "Insufficient bidder balance"},
+		{"整数参数", "单次最多查询 500 个NFT", "At most 500 NFTs can be looked up at once"},
+		{"字符串参数", "未知的挂单状态 foo", "Unknown listing status foo"},
+		{"参数中含中文", "未知的挂单状态 成交", "Unknown listing status 成交"},
+		{"带引号的参数", `无法解析的行 "abc"`, `Unparsable line "abc"`},
+		{"更具体的模板优先", "无效的合约地址 0x01", "Invalid contract address 0x01"},
+		{"英文详情原样返回", `parameter "limit" must be at most 100`, `parameter "limit" must be at most 100`},
+		{"没有翻译的中文详情", "没有翻译的详情", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := englishDetail(tt.details); got != tt.want {
+				t.Fatalf("englishDetail(%q) = %q，应为 %q", tt.details, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnglishDetailsPlaceholders(t *testing.T) {
+	for format, english := range englishDetails {
+		if got, want := detailVerb.FindAllString(english, -1), detailVerb.FindAllString(format, -1); strings.Join(got, "") != strings.Join(want, "") {
+			t.Errorf("%q 的翻译 %q 的参数为 %v，应为 %v", format, english, got, want)
+		}
+	}
+}
+
+// 所有返回给客户端的中文详情都必须有英文翻译
+func TestEnglishDetailsCoverSources(t *testing.T) {
+	dirs := []string{"../../usecase", "../controller", "."}
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatalf("列出源文件失败: %v", err)
+		}
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				t.Fatalf("解析 %s 失败: %v", path, err)
+			}
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				details, ok := sourceDetails(call)
+				if !ok || !strings.ContainsFunc(details, func(r rune) bool { return unicode.Is(unicode.Han, r) }) {
+					return true
+				}
+				if _, exists := englishDetails[details]; !exists {
+					t.Errorf("%s: 详情 %q 缺少英文翻译", fset.Position(call.Pos()), details)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// 提取 fmt.Errorf("%w: 详情", ...) 和 domain.InvalidParam(field, "说明") 中的详情模板，
+// 字面量与变量拼接时变量部分记为 %s
+func sourceDetails(call *ast.CallExpr) (string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	switch selector.Sel.Name {
+	case "Errorf":
+		if len(call.Args) < 2 {
+			return "", false
+		}
+		format, ok := stringTemplate(call.Args[0])
+		if !ok {
+			return "", false
+		}
+		details, found := strings.CutPrefix(format, "%w")
+		if !found || details == "" {
+			return "", false
+		}
+		return strings.TrimLeft(details, ":：，, "), true
+	case "InvalidParam":
+		if len(call.Args) != 2 {
+			return "", false
+		}
+		return stringTemplate(call.Args[1])
+	}
+	return "", false
+}
+
+func stringTemplate(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		left, ok := stringTemplate(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringTemplate(e.Y)
+		if !ok {
+			right = "%s"
+		}
+		return left + right, true
+	}
+	return "", false
+}
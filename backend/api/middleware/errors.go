@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"backend/domain"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 支持的响应语言，未指定或不支持时使用中文
+const (
+	langZH = "zh"
+	langEN = "en"
+)
+
+// 错误码对应的英文说明，中文说明使用错误本身的 Message
+var englishMessages = map[string]string{
+	"internal_error":          "Internal server error",
+	"invalid_request":         "Invalid request parameters",
+	"validation_failed":       "Request does not match the API specification",
+	"chain_unavailable":       "Blockchain node request failed",
+	"unauthorized":            "Not logged in or session expired",
+	"invalid_login_signature": "Invalid login signature",
+	"invalid_siwe_message":    "Invalid sign-in message",
+	"forbidden":               "Access denied",
+	"collection_not_found":    "NFT collection not found",
+	"nft_not_found":           "NFT not found",
+	"order_not_found":         "Order not found",
+	"listing_not_found":       "Listing not found",
+	"offer_not_found":         "Offer not found",
+	"auction_not_found":       "Auction not found",
+	"token_not_found":         "Payment token not found",
+	"profile_not_found":       "Profile not found",
+	"job_not_found":           "Job not found",
+	"job_running":             "Job is already running",
+	"webhook_not_found":       "Webhook not found",
+	"delivery_not_found":      "Webhook delivery not found",
+	"alert_rule_not_found":    "Alert rule not found",
+	"favorite_not_found":      "NFT is not in favorites",
+	"watch_not_found":         "Collection is not on the watchlist",
+	"invalid_listing":         "Invalid listing",
+	"listing_exists":          "Listing already exists",
+	"invalid_offer":           "Invalid offer",
+	"offer_exists":            "Offer already exists",
+	"offer_not_active":        "Offer is no longer active",
+	"invalid_auction":         "Invalid auction",
+	"auction_exists":          "Auction already exists",
+	"auction_not_active":      "Auction has ended",
+	"invalid_bid":             "Invalid auction bid",
+	"order_not_active":        "Order is not available for purchase",
+	"invalid_signature":       "Invalid signature",
+	"invalid_interval":        "Invalid candle interval, must be one of 1h, 1d, 1w",
+	"invalid_batch":           "Invalid batch lookup",
+	"invalid_profile":         "Invalid profile",
+	"username_taken":          "Username is already taken",
+	"invalid_webhook":         "Invalid webhook",
+	"invalid_alert_rule":      "Invalid alert rule",
+	"invalid_activity_type":   "Invalid activity type",
+	"price_unavailable":       "No price available",
+	"token_unreadable":        "Failed to read token information",
+}
+
+var kindStatuses = map[domain.ErrorKind]int{
+	domain.ErrKindInternal:     http.StatusInternalServerError,
+	domain.ErrKindInvalid:      http.StatusBadRequest,
+	domain.ErrKindUnauthorized: http.StatusUnauthorized,
+	domain.ErrKindForbidden:    http.StatusForbidden,
+	domain.ErrKindNotFound:     http.StatusNotFound,
+	domain.ErrKindConflict:     http.StatusConflict,
+	domain.ErrKindUpstream:     http.StatusBadGateway,
+}
+
+// ErrorHandler 将处理器通过 ctx.Error 记录的最后一个错误写为 JSON 响应：
+// {"error": 按 Accept-Language 本地化的说明, "code": 稳定的错误码}，参数错误时附带 "field"，
+// 有具体原因时附带 "details"。不是 domain.Error 的错误按服务器内部错误返回，详细信息只写入日志
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+		err := ctx.Errors.Last().Err
+		var appErr *domain.Error
+		if !errors.As(err, &appErr) {
+			appErr = domain.ErrInternal
+		}
+		status, exists := kindStatuses[appErr.Kind]
+		if !exists {
+			status = http.StatusInternalServerError
+		}
+		if status >= http.StatusInternalServerError {
+			log.Printf("处理请求失败 %s %s: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+		}
+
+		lang := preferredLanguage(ctx.GetHeader("Accept-Language"))
+		body := gin.H{"error": localizedMessage(lang, appErr, err), "code": appErr.Code}
+		if appErr.Field != "" {
+			body["field"] = appErr.Field
+		}
+		if details := errorDetails(lang, appErr, err); details != "" {
+			body["details"] = details
+		}
+		ctx.Header("Content-Language", lang)
+		ctx.JSON(status, body)
+	}
+}
+
+// 中文返回完整的错误说明（含 fmt.Errorf 补充的详情），服务器错误只返回概括说明以免泄露内部信息；
+// 英文按错误码查表，详情由 details 单独返回
+func localizedMessage(lang string, appErr *domain.Error, err error) string {
+	if lang == langEN {
+		if appErr.Field != "" {
+			return "Invalid parameter: " + appErr.Field
+		}
+		if message, exists := englishMessages[appErr.Code]; exists {
+			return message
+		}
+		return http.StatusText(kindStatuses[appErr.Kind])
+	}
+	switch appErr.Kind {
+	case domain.ErrKindInternal, domain.ErrKindUpstream:
+		return appErr.Message
+	}
+	return err.Error()
+}
+
+// 错误的具体原因：参数错误的说明，或 fmt.Errorf("%w: 详情", ErrX) 补充的详情。
+// 英文按 englishDetails 翻译，缺少翻译时不返回中文详情；服务器错误不返回详情
+func errorDetails(lang string, appErr *domain.Error, err error) string {
+	switch appErr.Kind {
+	case domain.ErrKindInternal, domain.ErrKindUpstream:
+		return ""
+	}
+	details := appErr.Message
+	if appErr.Field == "" {
+		var found bool
+		if details, found = strings.CutPrefix(err.Error(), appErr.Message); !found {
+			return ""
+		}
+		details = strings.TrimLeft(details, ":：，, ")
+	}
+	if lang == langEN && details != "" {
+		return englishDetail(details)
+	}
+	return details
+}
+
+// 按 Accept-Language 的权重选择支持的语言，如 "en-US,en;q=0.9,zh;q=0.8" 选择英文
+func preferredLanguage(header string) string {
+	best, bestQ := langZH, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if (primary == langZH || primary == langEN) && q > bestQ {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
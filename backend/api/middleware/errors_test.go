@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorHandler(t *testing.T) {
+	errInvalidListing := domain.NewError(domain.ErrKindInvalid, "invalid_listing", "无效的挂单")
+
+	tests := []struct {
+		name       string
+		err        error
+		lang       string
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{
+			name:       "中文返回完整说明和详情",
+			err:        fmt.Errorf("%w: 卖家未持有该NFT", errInvalidListing),
+			lang:       "zh-CN",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "无效的挂单: 卖家未持有该NFT", "code": "invalid_listing", "details": "卖家未持有该NFT"},
+		},
+		{
+			name:       "英文按错误码本地化并翻译详情",
+			err:        fmt.Errorf("%w: 卖家未持有该NFT", errInvalidListing),
+			lang:       "en-US,en;q=0.9",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "Invalid listing", "code": "invalid_listing", "details": "Seller does not own the NFT"},
+		},
+		{
+			name:       "英文详情填入参数",
+			err:        fmt.Errorf("%w: nonce已失效，当前nonce为 %d", errInvalidListing, 7),
+			lang:       "en",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "Invalid listing", "code": "invalid_listing", "details": "Nonce is no longer valid, the current nonce is 7"},
+		},
+		{
+			name:       "缺少翻译的中文详情不返回给英文客户端",
+			err:        fmt.Errorf("%w: 没有翻译的详情", errInvalidListing),
+			lang:       "en",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "Invalid listing", "code": "invalid_listing"},
+		},
+		{
+			name:       "没有详情时不返回 details",
+			err:        domain.ErrNFTNotFound,
+			lang:       "en",
+			wantStatus: http.StatusNotFound,
+			wantBody:   map[string]string{"error": "NFT not found", "code": "nft_not_found"},
+		},
+		{
+			name:       "参数错误的说明作为详情",
+			err:        domain.InvalidParam("tokenID", "无效的tokenID"),
+			lang:       "en",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "Invalid parameter: tokenID", "code": "invalid_request", "field": "tokenID", "details": "Invalid tokenID"},
+		},
+		{
+			name:       "校验失败的详情",
+			err:        fmt.Errorf("%w: %v", domain.ErrValidationFailed, errors.New(`parameter "limit" must be at most 100`)),
+			lang:       "en",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "Request does not match the API specification", "code": "validation_failed", "details": `parameter "limit" must be at most 100`},
+		},
+		{
+			name:       "链上节点出错不返回内部详情",
+			err:        domain.ErrChainUnavailable.Wrap(errors.New("dial tcp 10.0.0.1:8545: connection refused")),
+			lang:       "zh",
+			wantStatus: http.StatusBadGateway,
+			wantBody:   map[string]string{"error": "链上节点请求失败", "code": "chain_unavailable"},
+		},
+		{
+			name:       "非领域错误按内部错误返回",
+			err:        errors.New("数据库连接失败"),
+			lang:       "en",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   map[string]string{"error": "Internal server error", "code": "internal_error"},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.GET("/", func(ctx *gin.Context) {
+				ctx.Error(tt.err)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tt.lang)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("状态码为 %d，应为 %d", recorder.Code, tt.wantStatus)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if len(body) != len(tt.wantBody) {
+				t.Errorf("响应为 %v，应为 %v", body, tt.wantBody)
+			}
+			for key, want := range tt.wantBody {
+				if body[key] != want {
+					t.Errorf("%s 为 %q，应为 %q", key, body[key], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"backend/api/openapi"
+	"backend/domain"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求体最大读取长度，超出时交由控制器处理
+const maxValidatedBody = 1 << 20
+
+// OpenAPIValidator 按接口文档校验路径参数、查询参数和 JSON 请求体，不符合时返回 400 validation_failed。
+// validateResponses 为 true 时同时校验 JSON 响应，只记录日志不影响返回，用于开发时发现文档与实现不一致。
+// 文档中未定义的路由不做校验。
+func OpenAPIValidator(spec *openapi.Spec, validateResponses bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		op := spec.Operation(ctx.Request.Method, ctx.FullPath())
+		if op == nil {
+			ctx.Next()
+			return
+		}
+
+		if err := spec.ValidateParams(op, ctx.Param, ctx.Request.URL.Query()); err != nil {
+			ctx.Error(fmt.Errorf("%w: %v", domain.ErrValidationFailed, err))
+			ctx.Abort()
+			return
+		}
+
+		if op.RequestBody != nil && ctx.Request.Body != nil {
+			body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxValidatedBody+1))
+			if err != nil {
+				ctx.Error(domain.ErrInvalidRequest.WithMessage("读取请求体失败"))
+				ctx.Abort()
+				return
+			}
+			// 读取后放回，控制器仍可正常绑定
+			ctx.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), ctx.Request.Body))
+			if len(body) <= maxValidatedBody {
+				if err := spec.ValidateBody(op, ctx.ContentType(), body); err != nil {
+					ctx.Error(fmt.Errorf("%w: %v", domain.ErrValidationFailed, err))
+					ctx.Abort()
+					return
+				}
+			}
+		}
+
+		if !validateResponses || streamsEvents(op) {
+			ctx.Next()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		// 错误响应由 ErrorHandler 在之后写出，这里看不到
+		if !writer.Written() {
+			return
+		}
+		if err := spec.ValidateResponse(op, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Printf("响应与接口文档不一致 %s %s: %v", op.Method, op.Path, err)
+		}
+	}
+}
+
+// SSE 接口持续写入，不缓存响应
+func streamsEvents(op *openapi.Operation) bool {
+	for _, response := range op.Responses {
+		for contentType := range response.Content {
+			if strings.HasPrefix(contentType, "text/event-stream") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordingWriter 在写出响应的同时保留一份用于校验
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 接口文档，路由变更时需要同步修改
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+// Spec 是 OpenAPI 3 文档中校验请求和生成客户端需要的部分
+type Spec struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Servers    []Server             `yaml:"servers"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+
+	raw        []byte
+	operations map[string]*Operation // 以 "<方法> <gin 路由>" 为键
+}
+
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `yaml:"schemas"`
+	Parameters map[string]*Parameter `yaml:"parameters"`
+	Responses  map[string]*Response  `yaml:"responses"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Post       *Operation   `yaml:"post"`
+	Put        *Operation   `yaml:"put"`
+	Delete     *Operation   `yaml:"delete"`
+}
+
+// Operations 按方法返回路径下定义的操作
+func (p *PathItem) Operations() map[string]*Operation {
+	operations := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPost:   p.Post,
+		http.MethodPut:    p.Put,
+		http.MethodDelete: p.Delete,
+	} {
+		if op != nil {
+			operations[method] = op
+		}
+	}
+	return operations
+}
+
+type Operation struct {
+	OperationID string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary"`
+	Description string                `yaml:"description"`
+	Tags        []string              `yaml:"tags"`
+	Parameters  []*Parameter          `yaml:"parameters"`
+	RequestBody *RequestBody          `yaml:"requestBody"`
+	Responses   map[string]*Response  `yaml:"responses"`
+	Security    []map[string][]string `yaml:"security"`
+
+	Method string `yaml:"-"`
+	Path   string `yaml:"-"` // 文档中的路径，如 /nft/{contractAddress}
+}
+
+type Parameter struct {
+	Ref         string  `yaml:"$ref"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Required    bool    `yaml:"required"`
+	Description string  `yaml:"description"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Ref         string               `yaml:"$ref"`
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema 是 JSON Schema 中本项目用到的关键字
+type Schema struct {
+	Ref                  string                `yaml:"$ref"`
+	Type                 string                `yaml:"type"`
+	Format               string                `yaml:"format"`
+	Description          string                `yaml:"description"`
+	Nullable             bool                  `yaml:"nullable"`
+	Enum                 []string              `yaml:"enum"`
+	Pattern              string                `yaml:"pattern"`
+	MinLength            *int                  `yaml:"minLength"`
+	Minimum              *float64              `yaml:"minimum"`
+	Maximum              *float64              `yaml:"maximum"`
+	MinItems             *int                  `yaml:"minItems"`
+	MaxItems             *int                  `yaml:"maxItems"`
+	Properties           map[string]*Schema    `yaml:"properties"`
+	Required             []string              `yaml:"required"`
+	Items                *Schema               `yaml:"items"`
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	AllOf                []*Schema             `yaml:"allOf"`
+}
+
+// AdditionalProperties 可以是布尔值或 Schema，未定义时允许任意额外字段
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+// Load 解析内嵌的接口文档
+func Load() (*Spec, error) {
+	return Parse(specYAML)
+}
+
+// Parse 解析 YAML 格式的接口文档，展开参数和响应的引用并建立路由索引
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{raw: data}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("解析接口文档失败: %w", err)
+	}
+
+	prefix := ""
+	if len(spec.Servers) > 0 {
+		prefix = strings.TrimSuffix(spec.Servers[0].URL, "/")
+	}
+
+	spec.operations = make(map[string]*Operation)
+	for path, item := range spec.Paths {
+		for method, op := range item.Operations() {
+			op.Method = method
+			op.Path = path
+
+			// 路径级参数在操作未重新定义同名参数时生效
+			params := make([]*Parameter, 0, len(item.Parameters)+len(op.Parameters))
+			defined := make(map[string]bool)
+			for _, param := range op.Parameters {
+				resolved, err := spec.resolveParameter(param)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, path, err)
+				}
+				defined[resolved.In+":"+resolved.Name] = true
+				params = append(params, resolved)
+			}
+			for _, param := range item.Parameters {
+				resolved, err := spec.resolveParameter(param)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, path, err)
+				}
+				if !defined[resolved.In+":"+resolved.Name] {
+					params = append(params, resolved)
+				}
+			}
+			op.Parameters = params
+
+			for status, response := range op.Responses {
+				resolved, err := spec.resolveResponse(response)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, path, err)
+				}
+				op.Responses[status] = resolved
+			}
+
+			spec.operations[method+" "+prefix+ginPath(path)] = op
+		}
+	}
+	return spec, nil
+}
+
+func (s *Spec) resolveParameter(param *Parameter) (*Parameter, error) {
+	if param.Ref == "" {
+		return param, nil
+	}
+	resolved, ok := s.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+	if !ok {
+		return nil, fmt.Errorf("未定义的参数: %s", param.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) resolveResponse(response *Response) (*Response, error) {
+	if response.Ref == "" {
+		return response, nil
+	}
+	resolved, ok := s.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	if !ok {
+		return nil, fmt.Errorf("未定义的响应: %s", response.Ref)
+	}
+	return resolved, nil
+}
+
+// ResolveSchema 展开 Schema 引用
+func (s *Spec) ResolveSchema(schema *Schema) (*Schema, error) {
+	for schema != nil && schema.Ref != "" {
+		resolved, ok := s.Components.Schemas[RefName(schema.Ref)]
+		if !ok {
+			return nil, fmt.Errorf("未定义的 Schema: %s", schema.Ref)
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+// RefName 返回引用指向的组件名
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// 将 /nft/{contractAddress} 转换为 gin 的 /nft/:contractAddress
+func ginPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Operation 按请求方法和 gin 路由（如 /api/nft/:contractAddress）查找操作，未定义时返回 nil
+func (s *Spec) Operation(method, fullPath string) *Operation {
+	return s.operations[method+" "+fullPath]
+}
+
+// SortedOperations 按路径和方法排序返回全部操作
+func (s *Spec) SortedOperations() []*Operation {
+	operations := make([]*Operation, 0, len(s.operations))
+	for _, op := range s.operations {
+		operations = append(operations, op)
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return operations[i].Method < operations[j].Method
+	})
+	return operations
+}
+
+// YAML 返回原始文档
+func (s *Spec) YAML() []byte {
+	return s.raw
+}
+
+// JSON 返回转换为 JSON 的文档
+func (s *Spec) JSON() ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(s.raw, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
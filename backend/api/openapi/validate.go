@@ -0,0 +1,320 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var patternCache sync.Map // pattern -> *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// ValidateParams 校验路径参数和查询参数，未在文档中定义的查询参数不做限制
+func (s *Spec) ValidateParams(op *Operation, pathParam func(name string) string, query url.Values) error {
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			if err := s.validateParam(param, []string{pathParam(param.Name)}); err != nil {
+				return err
+			}
+		case "query":
+			values, exists := query[param.Name]
+			if !exists {
+				if param.Required {
+					return fmt.Errorf("缺少查询参数 %s", param.Name)
+				}
+				continue
+			}
+			if err := s.validateParam(param, values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Spec) validateParam(param *Parameter, values []string) error {
+	schema, err := s.ResolveSchema(param.Schema)
+	if err != nil || schema == nil {
+		return err
+	}
+
+	if schema.Type != "array" {
+		if len(values) > 1 {
+			return fmt.Errorf("参数 %s 只能出现一次", param.Name)
+		}
+		return s.validateParamValue(param.Name, schema, values[0])
+	}
+
+	items, err := s.ResolveSchema(schema.Items)
+	if err != nil {
+		return err
+	}
+	for i, raw := range values {
+		if err := s.validateParamValue(fmt.Sprintf("%s[%d]", param.Name, i), items, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 将参数按 Schema 的类型转换后校验
+func (s *Spec) validateParamValue(name string, schema *Schema, raw string) error {
+	if schema == nil {
+		return nil
+	}
+	var value interface{} = raw
+	switch schema.Type {
+	case "integer", "number":
+		value = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("参数 %s 必须为布尔值", name)
+		}
+		value = b
+	}
+	return s.ValidateValue(schema, value, "参数 "+name)
+}
+
+// ValidateBody 校验 JSON 请求体
+func (s *Spec) ValidateBody(op *Operation, contentType string, body []byte) error {
+	if op.RequestBody == nil {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return errors.New("缺少请求体")
+		}
+		return nil
+	}
+
+	media, ok := op.RequestBody.Content[mediaType(contentType)]
+	if !ok {
+		if _, jsonOnly := op.RequestBody.Content["application/json"]; jsonOnly {
+			return errors.New("请求体必须为 application/json")
+		}
+		return nil
+	}
+	value, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("请求体不是有效的JSON: %w", err)
+	}
+	return s.ValidateValue(media.Schema, value, "body")
+}
+
+// ValidateResponse 校验 JSON 响应，非 JSON 响应只检查状态码是否已定义
+func (s *Spec) ValidateResponse(op *Operation, status int, contentType string, body []byte) error {
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		if response, ok = op.Responses["default"]; !ok {
+			return fmt.Errorf("未定义的响应状态码 %d", status)
+		}
+	}
+	media, ok := response.Content[mediaType(contentType)]
+	if !ok {
+		if len(response.Content) > 0 && len(body) > 0 {
+			return fmt.Errorf("未定义的响应类型 %s", contentType)
+		}
+		return nil
+	}
+	if mediaType(contentType) != "application/json" {
+		return nil
+	}
+	value, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("响应不是有效的JSON: %w", err)
+	}
+	return s.ValidateValue(media.Schema, value, "response")
+}
+
+func mediaType(contentType string) string {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.TrimSpace(strings.ToLower(contentType))
+	}
+	return media
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("JSON 之后存在多余内容")
+	}
+	return value, nil
+}
+
+// ValidateValue 按 Schema 校验 encoding/json 解码（UseNumber）得到的值，path 用于错误信息
+func (s *Spec) ValidateValue(schema *Schema, value interface{}, path string) error {
+	schema, err := s.ResolveSchema(schema)
+	if err != nil || schema == nil {
+		return err
+	}
+
+	if value == nil {
+		if schema.Nullable || isAny(schema) {
+			return nil
+		}
+		return fmt.Errorf("%s 不能为 null", path)
+	}
+
+	for _, sub := range schema.AllOf {
+		if err := s.ValidateValue(sub, value, path); err != nil {
+			return err
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		str, _ := value.(string)
+		if !containsString(schema.Enum, str) {
+			return fmt.Errorf("%s 必须为 %s 之一", path, strings.Join(schema.Enum, ", "))
+		}
+	}
+
+	switch schema.Type {
+	case "object", "":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			if schema.Type == "" {
+				return nil
+			}
+			return fmt.Errorf("%s 必须为对象", path)
+		}
+		return s.validateObject(schema, object, path)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s 必须为数组", path)
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			return fmt.Errorf("%s 至少需要 %d 项", path, *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			return fmt.Errorf("%s 最多 %d 项", path, *schema.MaxItems)
+		}
+		for i, item := range array {
+			if err := s.ValidateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s 必须为字符串", path)
+		}
+		if schema.MinLength != nil && utf8.RuneCountInString(str) < *schema.MinLength {
+			if *schema.MinLength == 1 {
+				return fmt.Errorf("%s 不能为空", path)
+			}
+			return fmt.Errorf("%s 长度不能小于 %d", path, *schema.MinLength)
+		}
+		if schema.Pattern != "" {
+			re, err := compilePattern(schema.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s 的正则表达式无效: %w", path, err)
+			}
+			if !re.MatchString(str) {
+				return fmt.Errorf("%s 格式错误", path)
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s 必须为数字", path)
+		}
+		n, ok := new(big.Float).SetString(number.String())
+		if !ok {
+			return fmt.Errorf("%s 必须为数字", path)
+		}
+		if schema.Type == "integer" && !n.IsInt() {
+			return fmt.Errorf("%s 必须为整数", path)
+		}
+		if schema.Minimum != nil && n.Cmp(big.NewFloat(*schema.Minimum)) < 0 {
+			return fmt.Errorf("%s 不能小于 %v", path, *schema.Minimum)
+		}
+		if schema.Maximum != nil && n.Cmp(big.NewFloat(*schema.Maximum)) > 0 {
+			return fmt.Errorf("%s 不能大于 %v", path, *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s 必须为布尔值", path)
+		}
+	}
+	return nil
+}
+
+func (s *Spec) validateObject(schema *Schema, object map[string]interface{}, path string) error {
+	for _, name := range schema.Required {
+		if _, exists := object[name]; !exists {
+			return fmt.Errorf("%s 缺少字段 %s", path, name)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fieldPath := path + "." + name
+		if property, ok := schema.Properties[name]; ok {
+			if err := s.ValidateValue(property, object[name], fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		additional := schema.AdditionalProperties
+		if additional == nil || len(schema.AllOf) > 0 {
+			continue
+		}
+		if !additional.Allowed {
+			return fmt.Errorf("%s 不支持的字段", fieldPath)
+		}
+		if err := s.ValidateValue(additional.Schema, object[name], fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 没有任何约束的 Schema 接受任意值
+func isAny(schema *Schema) bool {
+	return schema.Type == "" && len(schema.AllOf) == 0 && len(schema.Properties) == 0 && len(schema.Enum) == 0
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,24 +2,150 @@ package route
 
 import (
 	"backend/api/controller"
+	"backend/api/middleware"
+	"backend/api/openapi"
+	"backend/usecase"
+	"log"
+	"strings"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, nftController *controller.NFTController, marketController *controller.MarketController) {
+func SetupRoutes(r *gin.Engine, nftController *controller.NFTController, marketController *controller.MarketController, retryController *controller.RetryController, searchController *controller.SearchController, tokenController *controller.TokenController, offerController *controller.OfferController, listingController *controller.ListingController, auctionController *controller.AuctionController, authController *controller.AuthController, profileController *controller.ProfileController, watchlistController *controller.WatchlistController, webhookController *controller.WebhookController, alertController *controller.AlertController, notificationController *controller.NotificationController, graphqlController *controller.GraphQLController, docsController *controller.DocsController, spec *openapi.Spec, authUC *usecase.AuthUseCase) {
 	// 设置 CORS
 	r.Use(cors.Default())
+	// 统一将 ctx.Error 记录的错误转换为带错误码的本地化响应
+	r.Use(middleware.ErrorHandler())
 
 	api := r.Group("/api")
+	// 按接口文档校验请求，调试模式下同时校验响应
+	api.Use(middleware.OpenAPIValidator(spec, gin.IsDebugging()))
+	requireAuth := middleware.RequireAuth(authUC)
+	optionalAuth := middleware.OptionalAuth(authUC)
+	requireAdmin := middleware.RequireAdmin(authUC)
+	requireAuthSSE := middleware.RequireAuthSSE(authUC)
 	{
+		// API docs routes
+		api.GET("/openapi.json", docsController.GetSpecJSON)
+		api.GET("/openapi.yaml", docsController.GetSpecYAML)
+		api.GET("/docs", docsController.GetDocs)
+		// Auth routes
+		api.GET("/auth/nonce", authController.GetNonce)
+		api.POST("/auth/login", authController.Login)
+		api.GET("/auth/session", requireAuth, authController.GetSession)
+		api.GET("/auth/sessions", requireAuth, authController.GetSessions)
+		api.POST("/auth/logout", requireAuth, authController.Logout)
+		api.POST("/auth/logout/all", requireAuth, authController.LogoutAll)
+		// Profile routes
+		api.GET("/profile", requireAuth, profileController.GetMyProfile)
+		api.PUT("/profile", requireAuth, profileController.UpdateProfile)
+		api.GET("/profiles/:address", profileController.GetProfile)
+		// Favorite and watchlist routes
+		api.GET("/favorites", requireAuth, watchlistController.GetFavorites)
+		api.POST("/favorites", requireAuth, watchlistController.AddFavorite)
+		api.DELETE("/favorites/:contractAddress/:tokenID", requireAuth, watchlistController.RemoveFavorite)
+		api.GET("/watchlist", requireAuth, watchlistController.GetWatchlist)
+		api.POST("/watchlist", requireAuth, watchlistController.AddWatch)
+		api.GET("/watchlist/feed", requireAuth, watchlistController.GetFeed)
+		api.DELETE("/watchlist/:contractAddress", requireAuth, watchlistController.RemoveWatch)
+
+		// Webhook routes
+		api.GET("/webhooks", requireAuth, webhookController.GetWebhooks)
+		api.POST("/webhooks", requireAuth, webhookController.CreateWebhook)
+		api.GET("/webhooks/:id", requireAuth, webhookController.GetWebhook)
+		api.PUT("/webhooks/:id", requireAuth, webhookController.UpdateWebhook)
+		api.DELETE("/webhooks/:id", requireAuth, webhookController.DeleteWebhook)
+		api.POST("/webhooks/:id/secret", requireAuth, webhookController.RotateSecret)
+		api.GET("/webhooks/:id/deliveries", requireAuth, webhookController.GetDeliveries)
+		api.POST("/webhooks/:id/deliveries/:deliveryID/replay", requireAuth, webhookController.ReplayDelivery)
+
+		// Alert routes
+		api.GET("/alerts", requireAuth, alertController.GetAlerts)
+		api.POST("/alerts/read", requireAuth, alertController.MarkRead)
+		api.GET("/alerts/rules", requireAuth, alertController.GetRules)
+		api.POST("/alerts/rules", requireAuth, alertController.CreateRule)
+		api.PUT("/alerts/rules/:id", requireAuth, alertController.UpdateRule)
+		api.DELETE("/alerts/rules/:id", requireAuth, alertController.DeleteRule)
+
+		// Notification routes
+		api.GET("/notifications", requireAuth, notificationController.GetNotifications)
+		api.POST("/notifications/read", requireAuth, notificationController.MarkRead)
+		api.GET("/notifications/stream", requireAuthSSE, notificationController.StreamNotifications)
+
+		// GraphQL routes
+		api.POST("/graphql", graphqlController.Query)
+		api.GET("/graphql", graphqlController.Query)
+		api.GET("/graphql/schema", graphqlController.GetSchema)
 		// NFT routes
 		api.GET("/nft", nftController.GetCollections)
 		api.GET("/nft/:contractAddress", nftController.GetCollection)
-		api.GET("/nft/:contractAddress/:tokenID", nftController.GetNFT)
+		api.POST("/nft/batch", nftController.BatchGetNFTs)
+		api.GET("/nft/:contractAddress/traits", nftController.GetCollectionTraits)
+		api.GET("/nft/:contractAddress/stats", marketController.GetCollectionStats)
+		api.GET("/nft/:contractAddress/history/prices", marketController.GetPriceHistory)
+		api.GET("/nft/:contractAddress/offers", offerController.GetCollectionOffers)
+		api.GET("/nft/:contractAddress/:tokenID", optionalAuth, nftController.GetNFT)
 		api.GET("/nft/:contractAddress/:tokenID/history", nftController.GetNFTTransferHistory)
+		api.GET("/nft/:contractAddress/:tokenID/provenance", marketController.GetNFTProvenance)
+		api.GET("/nft/:contractAddress/:tokenID/offers", offerController.GetNFTOffers)
 		// Market routes
 		api.GET("/orders", marketController.GetOrders)
 		api.GET("/order/:contractAddress/:tokenID", marketController.GetOrderByNFT)
+		api.POST("/orders/batch", marketController.BatchGetOrders)
+		api.POST("/orders/preflight", marketController.PreflightListing)
+		// gin 不允许同一位置的通配符使用不同的参数名，GET /order/:id/permit 会与上面的
+		// /order/:contractAddress/:tokenID 冲突，因此按订单索引访问的接口统一挂在 /orders/:id 下
+		api.GET("/orders/:id/permit", marketController.GetOrderPermit)
+		api.POST("/orders/:id/permit/verify", marketController.VerifyOrderPermit)
+		api.POST("/orders/:id/simulate", marketController.SimulateBuy)
+		// Signed listing routes
+		api.GET("/listings", listingController.GetListings)
+		api.POST("/listings", listingController.SubmitListing)
+		api.POST("/listings/typed-data", listingController.BuildListingTypedData)
+		api.GET("/listings/nonce", listingController.GetSignerNonce)
+		api.POST("/listings/nonce", listingController.IncreaseNonce)
+		api.GET("/listings/:hash", listingController.GetListing)
+		// Offer routes
+		api.GET("/offers", offerController.GetBidderOffers)
+		api.POST("/offers", offerController.SubmitOffer)
+		api.POST("/offers/typed-data", offerController.BuildOfferTypedData)
+		api.GET("/offers/nonce", offerController.GetBidderNonce)
+		api.POST("/offers/nonce", offerController.IncreaseNonce)
+		api.GET("/offers/:id", offerController.GetOffer)
+		api.GET("/offers/:id/cancel", offerController.GetCancelTypedData)
+		api.POST("/offers/:id/cancel", offerController.CancelOffer)
+		// Auction routes
+		api.GET("/auctions", auctionController.GetAuctions)
+		api.POST("/auctions", auctionController.CreateAuction)
+		api.POST("/auctions/typed-data", auctionController.BuildAuctionTypedData)
+		api.GET("/auctions/:id", auctionController.GetAuction)
+		api.GET("/auctions/:id/bids", auctionController.GetAuctionBids)
+		api.POST("/auctions/:id/bids", auctionController.PlaceBid)
+		api.POST("/auctions/:id/bids/typed-data", auctionController.BuildBidTypedData)
+		api.GET("/auctions/:id/stream", auctionController.StreamAuction)
+		api.GET("/auctions/:id/cancel", auctionController.GetCancelTypedData)
+		api.POST("/auctions/:id/cancel", auctionController.CancelAuction)
+		// Payment token routes
+		api.GET("/tokens", tokenController.GetTokens)
+		api.GET("/tokens/:address", tokenController.GetToken)
+		api.GET("/tokens/:address/price", tokenController.GetTokenPrice)
+		// Search routes
+		api.GET("/search", searchController.Search)
+		api.GET("/search/autocomplete", searchController.Autocomplete)
+		// Admin routes
+		admin := api.Group("/admin", requireAuth, requireAdmin)
+		admin.GET("/jobs", retryController.GetFailedJobs)
+		admin.POST("/jobs/:id/retry", retryController.RetryJob)
+		admin.POST("/price-history/rebuild", marketController.RebuildPriceHistory)
+		admin.PUT("/tokens/:address/status", tokenController.SetTokenListStatus)
+		admin.POST("/tokens/:address/refresh", tokenController.RefreshToken)
+	}
+
+	// 接口文档中缺少的路由不会被校验，也不会出现在生成的客户端中
+	for _, route := range r.Routes() {
+		if strings.HasPrefix(route.Path, "/api/") && spec.Operation(route.Method, route.Path) == nil {
+			log.Printf("接口文档中缺少路由: %s %s", route.Method, route.Path)
+		}
 	}
 }
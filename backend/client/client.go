@@ -0,0 +1,170 @@
+// Package client 是 NFTMarket 接口的 Go 客户端，类型和接口方法由 cmd/clientgen 根据接口文档生成
+package client
+
+//go:generate go run ../cmd/clientgen -o client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Client 调用 NFTMarket 接口，baseURL 包含 /api 前缀，如 http://localhost:8081/api
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	language   string
+}
+
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// SetToken 设置登录后得到的会话令牌，之后的请求都会携带
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// SetLanguage 设置 Accept-Language，接口按其返回中文或英文错误说明
+func (c *Client) SetLanguage(language string) {
+	c.language = language
+}
+
+// APIError 是接口返回的错误，Code 为稳定的错误码，可用于判断错误类型
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Field      string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("接口返回 %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("接口返回 %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("编码请求体失败: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	return req, nil
+}
+
+func (c *Client) send(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		var body Error
+		if json.Unmarshal(data, &body) == nil && body.Error != "" {
+			apiErr.Code, apiErr.Message, apiErr.Field = body.Code, body.Error, body.Field
+		}
+		return nil, apiErr
+	}
+	return data, nil
+}
+
+// do 发送 JSON 请求，out 不为 nil 时解码响应
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	data, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("解码响应失败: %w", err)
+	}
+	return nil
+}
+
+// doText 发送请求并返回文本响应
+func (c *Client) doText(ctx context.Context, method, path string, query url.Values) (string, error) {
+	req, err := c.newRequest(ctx, method, path, query, nil)
+	if err != nil {
+		return "", err
+	}
+	data, err := c.send(req)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func pathParam(value interface{}) string {
+	return url.PathEscape(fmt.Sprint(value))
+}
+
+// encodeQuery 按 query 标签编码查询参数结构体，零值字段不发送
+func encodeQuery(params interface{}) url.Values {
+	values := url.Values{}
+	v := reflect.ValueOf(params)
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return values
+	}
+	v = reflect.Indirect(v)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("query")
+		field := v.Field(i)
+		if name == "" || field.IsZero() {
+			continue
+		}
+		if field.Kind() == reflect.Slice {
+			for j := 0; j < field.Len(); j++ {
+				values.Add(name, fmt.Sprint(field.Index(j).Interface()))
+			}
+			continue
+		}
+		values.Set(name, fmt.Sprint(field.Interface()))
+	}
+	return values
+}
@@ -0,0 +1,1694 @@
+// Code generated by clientgen. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"time"
+)
+
+type Alert struct {
+	CreatedAt          time.Time  `json:"CreatedAt,omitempty"`
+	DedupKey           string     `json:"DedupKey,omitempty"`
+	ID                 int64      `json:"ID,omitempty"`
+	Kind               string     `json:"Kind,omitempty"`
+	Message            string     `json:"Message,omitempty"`
+	NFTContractAddress string     `json:"NFTContractAddress,omitempty"`
+	Owner              string     `json:"Owner,omitempty"`
+	Price              string     `json:"Price,omitempty"`
+	ReadAt             *time.Time `json:"ReadAt,omitempty"`
+	Reference          string     `json:"Reference,omitempty"`
+	RuleID             int64      `json:"RuleID,omitempty"`
+	Throttled          bool       `json:"Throttled,omitempty"`
+	Title              string     `json:"Title,omitempty"`
+	TokenAddress       string     `json:"TokenAddress,omitempty"`
+	TokenID            int64      `json:"TokenID,omitempty"`
+}
+
+type AlertRule struct {
+	Active bool `json:"Active,omitempty"`
+	// 逗号分隔的 inbox、email、webhook
+	Channels        string     `json:"Channels,omitempty"`
+	Collection      string     `json:"Collection,omitempty"`
+	CreatedAt       time.Time  `json:"CreatedAt,omitempty"`
+	Email           string     `json:"Email,omitempty"`
+	ID              int64      `json:"ID,omitempty"`
+	Kind            string     `json:"Kind,omitempty"`
+	LastTriggeredAt *time.Time `json:"LastTriggeredAt,omitempty"`
+	Owner           string     `json:"Owner,omitempty"`
+	Price           string     `json:"Price,omitempty"`
+	TokenAddress    string     `json:"TokenAddress,omitempty"`
+	TraitType       string     `json:"TraitType,omitempty"`
+	TraitValue      string     `json:"TraitValue,omitempty"`
+	UpdatedAt       time.Time  `json:"UpdatedAt,omitempty"`
+}
+
+type AlertRuleRequest struct {
+	Active       *bool    `json:"active,omitempty"`
+	Channels     []string `json:"channels,omitempty"`
+	Collection   string   `json:"collection,omitempty"`
+	Email        string   `json:"email,omitempty"`
+	Kind         string   `json:"kind"`
+	Price        string   `json:"price,omitempty"`
+	TokenAddress string   `json:"tokenAddress,omitempty"`
+	TraitType    string   `json:"traitType,omitempty"`
+	TraitValue   string   `json:"traitValue,omitempty"`
+}
+
+type Auction struct {
+	CreatedAt          time.Time `json:"CreatedAt,omitempty"`
+	EndPrice           string    `json:"EndPrice,omitempty"`
+	EndTime            time.Time `json:"EndTime,omitempty"`
+	ExtensionSeconds   int64     `json:"ExtensionSeconds,omitempty"`
+	Hash               string    `json:"Hash,omitempty"`
+	ID                 int64     `json:"ID,omitempty"`
+	MinIncrement       string    `json:"MinIncrement,omitempty"`
+	NFTContractAddress string    `json:"NFTContractAddress,omitempty"`
+	ReservePrice       string    `json:"ReservePrice,omitempty"`
+	Salt               string    `json:"Salt,omitempty"`
+	Seller             string    `json:"Seller,omitempty"`
+	Signature          string    `json:"Signature,omitempty"`
+	StartPrice         string    `json:"StartPrice,omitempty"`
+	StartTime          time.Time `json:"StartTime,omitempty"`
+	// 0: 进行中, 1: 已成交, 2: 已取消, 3: 流拍
+	Status         int64     `json:"Status,omitempty"`
+	TokenAddress   string    `json:"TokenAddress,omitempty"`
+	TokenID        int64     `json:"TokenID,omitempty"`
+	Type           string    `json:"Type,omitempty"`
+	UpdatedAt      time.Time `json:"UpdatedAt,omitempty"`
+	WinningBidID   int64     `json:"WinningBidID,omitempty"`
+	WinningOfferID int64     `json:"WinningOfferID,omitempty"`
+}
+
+type AuctionBid struct {
+	AuctionID int64     `json:"AuctionID,omitempty"`
+	Bidder    string    `json:"Bidder,omitempty"`
+	CreatedAt time.Time `json:"CreatedAt,omitempty"`
+	Expiry    time.Time `json:"Expiry,omitempty"`
+	Hash      string    `json:"Hash,omitempty"`
+	ID        int64     `json:"ID,omitempty"`
+	Nonce     string    `json:"Nonce,omitempty"`
+	Price     string    `json:"Price,omitempty"`
+	Signature string    `json:"Signature,omitempty"`
+}
+
+type AuctionBidRequest struct {
+	Bidder string `json:"bidder"`
+	Expiry int64  `json:"expiry"`
+	// 不能小于买家当前的出价 nonce（GET /offers/nonce）
+	Nonce     string `json:"nonce"`
+	Price     string `json:"price"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type AuctionRequest struct {
+	EndPrice         string `json:"endPrice,omitempty"`
+	EndTime          int64  `json:"endTime"`
+	ExtensionSeconds int64  `json:"extensionSeconds,omitempty"`
+	MinIncrement     string `json:"minIncrement,omitempty"`
+	NFTAddress       string `json:"nftAddress"`
+	ReservePrice     string `json:"reservePrice,omitempty"`
+	Salt             string `json:"salt"`
+	Seller           string `json:"seller"`
+	Signature        string `json:"signature,omitempty"`
+	StartPrice       string `json:"startPrice,omitempty"`
+	StartTime        int64  `json:"startTime"`
+	TokenAddress     string `json:"tokenAddress"`
+	TokenID          int64  `json:"tokenId,omitempty"`
+	Type             string `json:"type"`
+}
+
+type AuctionView struct {
+	Auction
+	BidCount              int64           `json:"BidCount,omitempty"`
+	CurrentPrice          string          `json:"CurrentPrice,omitempty"`
+	CurrentPriceFormatted string          `json:"CurrentPriceFormatted,omitempty"`
+	CurrentPriceUSD       *float64        `json:"CurrentPriceUSD,omitempty"`
+	Leader                *AuctionBid     `json:"Leader,omitempty"`
+	MinNextBid            string          `json:"MinNextBid,omitempty"`
+	PaymentToken          *PaymentToken   `json:"PaymentToken,omitempty"`
+	SecondsRemaining      int64           `json:"SecondsRemaining,omitempty"`
+	SellerProfile         *ProfileSummary `json:"SellerProfile,omitempty"`
+}
+
+type AuthNonce struct {
+	ChainID   int64     `json:"ChainID,omitempty"`
+	ExpiresAt time.Time `json:"ExpiresAt,omitempty"`
+	Nonce     string    `json:"Nonce,omitempty"`
+}
+
+type AuthSession struct {
+	Address   string    `json:"Address,omitempty"`
+	ChainID   int64     `json:"ChainID,omitempty"`
+	ExpiresAt time.Time `json:"ExpiresAt,omitempty"`
+	Token     string    `json:"Token,omitempty"`
+}
+
+type BidderNonceInfo struct {
+	Bidder        string            `json:"Bidder,omitempty"`
+	IncreaseNonce SignableTypedData `json:"IncreaseNonce,omitempty"`
+	Nonce         int64             `json:"Nonce,omitempty"`
+}
+
+type BuySimulation struct {
+	PreflightChecks
+	CanFill      bool   `json:"CanFill,omitempty"`
+	GasEstimate  int64  `json:"GasEstimate,omitempty"`
+	OrderIndex   int64  `json:"OrderIndex,omitempty"`
+	RevertReason string `json:"RevertReason,omitempty"`
+}
+
+type CollectionStats struct {
+	ContractAddress string       `json:"ContractAddress,omitempty"`
+	FloorPriceUSD   *float64     `json:"FloorPriceUSD,omitempty"`
+	Floors          []TokenFloor `json:"Floors,omitempty"`
+	ListedCount     int64        `json:"ListedCount,omitempty"`
+	SalesCount      int64        `json:"SalesCount,omitempty"`
+	UnpricedSales   int64        `json:"UnpricedSales,omitempty"`
+	VolumeUSD       float64      `json:"VolumeUSD,omitempty"`
+}
+
+type Error struct {
+	// 机器可读的错误码，如 internal_error、invalid_request、validation_failed、unauthorized、order_not_found、chain_unavailable（链上节点请求失败，可稍后重试）
+	Code string `json:"code"`
+	// 错误的具体原因，如挂单无效的原因或请求校验失败的详情，按 Accept-Language 本地化；缺少英文翻译时英文响应不返回。服务器错误不返回
+	Details string `json:"details,omitempty"`
+	// 按 Accept-Language 本地化的错误说明
+	Error string `json:"error"`
+	// 参数错误时对应的参数名
+	Field string `json:"field,omitempty"`
+}
+
+type FavoriteRequest struct {
+	NFTAddress string `json:"nftAddress"`
+	TokenID    int64  `json:"tokenId,omitempty"`
+}
+
+type GraphQLError struct {
+	Message string   `json:"message,omitempty"`
+	Path    []string `json:"path,omitempty"`
+}
+
+type GraphQLRequest struct {
+	OperationName string                 `json:"operationName,omitempty"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Errors []GraphQLError         `json:"errors,omitempty"`
+}
+
+type Holding struct {
+	AcquiredAt            time.Time       `json:"AcquiredAt,omitempty"`
+	AcquiredVia           string          `json:"AcquiredVia,omitempty"`
+	AcquisitionPrice      string          `json:"AcquisitionPrice,omitempty"`
+	DisposalPrice         string          `json:"DisposalPrice,omitempty"`
+	HoldingSeconds        int64           `json:"HoldingSeconds,omitempty"`
+	Owner                 string          `json:"Owner,omitempty"`
+	OwnerProfile          *ProfileSummary `json:"OwnerProfile,omitempty"`
+	RealizedGain          string          `json:"RealizedGain,omitempty"`
+	RealizedGainFormatted string          `json:"RealizedGainFormatted,omitempty"`
+	ReleasedAt            *time.Time      `json:"ReleasedAt,omitempty"`
+	ReleasedVia           string          `json:"ReleasedVia,omitempty"`
+	TokenAddress          string          `json:"TokenAddress,omitempty"`
+}
+
+type IncreaseNonceRequest struct {
+	Nonce     int64  `json:"nonce"`
+	Seller    string `json:"seller"`
+	Signature string `json:"signature"`
+}
+
+type IncreaseOfferNonceRequest struct {
+	Bidder    string `json:"bidder"`
+	Nonce     int64  `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+type Listing struct {
+	Buyer              string    `json:"Buyer,omitempty"`
+	CreatedAt          time.Time `json:"CreatedAt,omitempty"`
+	Expiry             time.Time `json:"Expiry,omitempty"`
+	FillTransaction    string    `json:"FillTransaction,omitempty"`
+	Hash               string    `json:"Hash,omitempty"`
+	ID                 int64     `json:"ID,omitempty"`
+	Invalid            bool      `json:"Invalid,omitempty"`
+	InvalidReason      string    `json:"InvalidReason,omitempty"`
+	NFTContractAddress string    `json:"NFTContractAddress,omitempty"`
+	Nonce              int64     `json:"Nonce,omitempty"`
+	Price              string    `json:"Price,omitempty"`
+	Salt               string    `json:"Salt,omitempty"`
+	Seller             string    `json:"Seller,omitempty"`
+	Signature          string    `json:"Signature,omitempty"`
+	// 0: 有效, 1: 已成交, 2: 已取消, 3: 已过期
+	Status       int64     `json:"Status,omitempty"`
+	TokenAddress string    `json:"TokenAddress,omitempty"`
+	TokenID      int64     `json:"TokenID,omitempty"`
+	UpdatedAt    time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type ListingPreflight struct {
+	PreflightChecks
+	CanList              bool                  `json:"CanList,omitempty"`
+	RequiredTransactions []RequiredTransaction `json:"RequiredTransactions,omitempty"`
+}
+
+type ListingPreflightRequest struct {
+	NFTAddress   string `json:"nftAddress"`
+	Price        string `json:"price"`
+	Seller       string `json:"seller"`
+	TokenAddress string `json:"tokenAddress"`
+	TokenID      int64  `json:"tokenId"`
+}
+
+type ListingRequest struct {
+	Expiry       int64  `json:"expiry"`
+	NFTAddress   string `json:"nftAddress"`
+	Nonce        int64  `json:"nonce,omitempty"`
+	Price        string `json:"price"`
+	Salt         string `json:"salt"`
+	Seller       string `json:"seller"`
+	Signature    string `json:"signature,omitempty"`
+	TokenAddress string `json:"tokenAddress"`
+	TokenID      int64  `json:"tokenId,omitempty"`
+}
+
+type ListingView struct {
+	Listing
+	BuyerProfile   *ProfileSummary `json:"BuyerProfile,omitempty"`
+	PaymentToken   *PaymentToken   `json:"PaymentToken,omitempty"`
+	PriceFormatted string          `json:"PriceFormatted,omitempty"`
+	PriceUSD       *float64        `json:"PriceUSD,omitempty"`
+	SellerProfile  *ProfileSummary `json:"SellerProfile,omitempty"`
+}
+
+type LoginRequest struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+type MarkReadRequest struct {
+	IDs []int64 `json:"ids,omitempty"`
+}
+
+type MarketActivity struct {
+	Buyer              string    `json:"Buyer,omitempty"`
+	CreatedAt          time.Time `json:"CreatedAt,omitempty"`
+	ID                 int64     `json:"ID,omitempty"`
+	NFTContractAddress string    `json:"NFTContractAddress,omitempty"`
+	PreviousPrice      string    `json:"PreviousPrice,omitempty"`
+	Price              string    `json:"Price,omitempty"`
+	Reference          string    `json:"Reference,omitempty"`
+	Seller             string    `json:"Seller,omitempty"`
+	TokenAddress       string    `json:"TokenAddress,omitempty"`
+	TokenID            int64     `json:"TokenID,omitempty"`
+	TransactionHash    string    `json:"TransactionHash,omitempty"`
+	Type               string    `json:"Type,omitempty"`
+}
+
+type MarketActivityView struct {
+	MarketActivity
+	BuyerProfile           *ProfileSummary `json:"BuyerProfile,omitempty"`
+	NFT                    *NFT            `json:"NFT,omitempty"`
+	PaymentToken           *PaymentToken   `json:"PaymentToken,omitempty"`
+	PreviousPriceFormatted string          `json:"PreviousPriceFormatted,omitempty"`
+	PriceFormatted         string          `json:"PriceFormatted,omitempty"`
+	PriceUSD               *float64        `json:"PriceUSD,omitempty"`
+	SellerProfile          *ProfileSummary `json:"SellerProfile,omitempty"`
+}
+
+type Message struct {
+	Message string `json:"message"`
+}
+
+type NFT struct {
+	CollectionID    int64  `json:"CollectionID,omitempty"`
+	ContractAddress string `json:"ContractAddress,omitempty"`
+	Description     string `json:"Description,omitempty"`
+	ID              int64  `json:"ID,omitempty"`
+	Image           string `json:"Image,omitempty"`
+	Name            string `json:"Name,omitempty"`
+	Owner           string `json:"Owner,omitempty"`
+	TokenID         int64  `json:"TokenID,omitempty"`
+	TokenURI        string `json:"TokenURI,omitempty"`
+}
+
+type NFTAttribute struct {
+	ID        int64  `json:"ID,omitempty"`
+	NFTID     int64  `json:"NFTID,omitempty"`
+	TraitType string `json:"TraitType,omitempty"`
+	Value     string `json:"Value,omitempty"`
+}
+
+type NFTBatchEntry struct {
+	Attributes []NFTAttribute `json:"attributes"`
+	NFT        NFTView        `json:"nft"`
+}
+
+type NFTBatchRequestNFTsItem struct {
+	NFTAddress string `json:"nftAddress"`
+	TokenID    int64  `json:"tokenId"`
+}
+
+type NFTBatchRequest struct {
+	NFTs []NFTBatchRequestNFTsItem `json:"nfts"`
+}
+
+type NFTCollection struct {
+	ContractAddress string `json:"ContractAddress,omitempty"`
+	ID              int64  `json:"ID,omitempty"`
+	Name            string `json:"Name,omitempty"`
+	Symbol          string `json:"Symbol,omitempty"`
+	TokenIconURI    string `json:"TokenIconURI,omitempty"`
+}
+
+type NFTRarity struct {
+	CollectionID       int64   `json:"CollectionID,omitempty"`
+	ID                 int64   `json:"ID,omitempty"`
+	InformationContent float64 `json:"InformationContent,omitempty"`
+	NFTID              int64   `json:"NFTID,omitempty"`
+	Rank               int64   `json:"Rank,omitempty"`
+	RarityScore        float64 `json:"RarityScore,omitempty"`
+	StatisticalRarity  float64 `json:"StatisticalRarity,omitempty"`
+}
+
+type NFTTransferEvent struct {
+	BlockNumber     int64     `json:"BlockNumber,omitempty"`
+	BlockTimestamp  time.Time `json:"BlockTimestamp,omitempty"`
+	ContractAddress string    `json:"ContractAddress,omitempty"`
+	EventType       string    `json:"EventType,omitempty"`
+	FromAddress     string    `json:"FromAddress,omitempty"`
+	ID              int64     `json:"ID,omitempty"`
+	ToAddress       string    `json:"ToAddress,omitempty"`
+	TokenID         int64     `json:"TokenID,omitempty"`
+	TransactionHash string    `json:"TransactionHash,omitempty"`
+}
+
+type NFTTransferEventView struct {
+	NFTTransferEvent
+	FromProfile *ProfileSummary `json:"FromProfile,omitempty"`
+	ToProfile   *ProfileSummary `json:"ToProfile,omitempty"`
+}
+
+type NFTView struct {
+	NFT
+	OwnerProfile *ProfileSummary `json:"OwnerProfile,omitempty"`
+}
+
+type Notification struct {
+	Address            string     `json:"Address,omitempty"`
+	Counterparty       string     `json:"Counterparty,omitempty"`
+	CreatedAt          time.Time  `json:"CreatedAt,omitempty"`
+	ID                 int64      `json:"ID,omitempty"`
+	NFTContractAddress string     `json:"NFTContractAddress,omitempty"`
+	Price              string     `json:"Price,omitempty"`
+	ReadAt             *time.Time `json:"ReadAt,omitempty"`
+	Reference          string     `json:"Reference,omitempty"`
+	Title              string     `json:"Title,omitempty"`
+	TokenAddress       string     `json:"TokenAddress,omitempty"`
+	TokenID            int64      `json:"TokenID,omitempty"`
+	TransactionHash    string     `json:"TransactionHash,omitempty"`
+	Type               string     `json:"Type,omitempty"`
+}
+
+type NotificationView struct {
+	Notification
+	CounterpartyProfile *ProfileSummary `json:"CounterpartyProfile,omitempty"`
+	NFT                 *NFT            `json:"NFT,omitempty"`
+	PaymentToken        *PaymentToken   `json:"PaymentToken,omitempty"`
+	PriceFormatted      string          `json:"PriceFormatted,omitempty"`
+}
+
+type Offer struct {
+	Bidder             string    `json:"Bidder,omitempty"`
+	CollectionWide     bool      `json:"CollectionWide,omitempty"`
+	CreatedAt          time.Time `json:"CreatedAt,omitempty"`
+	Expiry             time.Time `json:"Expiry,omitempty"`
+	Hash               string    `json:"Hash,omitempty"`
+	ID                 int64     `json:"ID,omitempty"`
+	Invalid            bool      `json:"Invalid,omitempty"`
+	InvalidReason      string    `json:"InvalidReason,omitempty"`
+	NFTContractAddress string    `json:"NFTContractAddress,omitempty"`
+	Nonce              string    `json:"Nonce,omitempty"`
+	Price              string    `json:"Price,omitempty"`
+	Signature          string    `json:"Signature,omitempty"`
+	// 0: 有效, 1: 已取消, 2: 已过期
+	Status       int64  `json:"Status,omitempty"`
+	TokenAddress string `json:"TokenAddress,omitempty"`
+	TokenID      int64  `json:"TokenID,omitempty"`
+}
+
+type OfferRequest struct {
+	Bidder         string `json:"bidder"`
+	CollectionWide *bool  `json:"collectionWide,omitempty"`
+	Expiry         int64  `json:"expiry"`
+	NFTAddress     string `json:"nftAddress"`
+	// 不能小于买家当前的出价 nonce（GET /offers/nonce）
+	Nonce        string `json:"nonce"`
+	Price        string `json:"price"`
+	Signature    string `json:"signature,omitempty"`
+	TokenAddress string `json:"tokenAddress"`
+	TokenID      int64  `json:"tokenId,omitempty"`
+}
+
+type OfferView struct {
+	Offer
+	PaymentToken   *PaymentToken `json:"PaymentToken,omitempty"`
+	PriceFormatted string        `json:"PriceFormatted,omitempty"`
+	PriceUSD       *float64      `json:"PriceUSD,omitempty"`
+}
+
+type Order struct {
+	// 链上订单索引加一
+	ID                 int64  `json:"ID,omitempty"`
+	Invalid            bool   `json:"Invalid,omitempty"`
+	InvalidReason      string `json:"InvalidReason,omitempty"`
+	NFTContractAddress string `json:"NFTContractAddress,omitempty"`
+	Price              string `json:"Price,omitempty"`
+	Seller             string `json:"Seller,omitempty"`
+	// 0: 未售出, 1: 已售出, 2: 已取消
+	Status       int64  `json:"Status,omitempty"`
+	TokenAddress string `json:"TokenAddress,omitempty"`
+	TokenID      int64  `json:"TokenID,omitempty"`
+}
+
+type OrderPermit struct {
+	Deadline   int64     `json:"Deadline,omitempty"`
+	Nonce      string    `json:"Nonce,omitempty"`
+	OrderIndex int64     `json:"OrderIndex,omitempty"`
+	TypedData  TypedData `json:"TypedData,omitempty"`
+}
+
+type OrderView struct {
+	Order
+	Listing        *Listing        `json:"Listing,omitempty"`
+	PaymentToken   *PaymentToken   `json:"PaymentToken,omitempty"`
+	PriceFormatted string          `json:"PriceFormatted,omitempty"`
+	PriceUSD       *float64        `json:"PriceUSD,omitempty"`
+	SellerProfile  *ProfileSummary `json:"SellerProfile,omitempty"`
+	Source         string          `json:"Source,omitempty"`
+}
+
+type PaymentToken struct {
+	Address  string `json:"Address,omitempty"`
+	Decimals int64  `json:"Decimals,omitempty"`
+	ID       int64  `json:"ID,omitempty"`
+	// 0: 未设置, 1: 白名单, 2: 黑名单
+	ListStatus     int64     `json:"ListStatus,omitempty"`
+	Name           string    `json:"Name,omitempty"`
+	SupportsPermit bool      `json:"SupportsPermit,omitempty"`
+	Symbol         string    `json:"Symbol,omitempty"`
+	UpdatedAt      time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type PermitVerifyRequest struct {
+	Buyer     string `json:"buyer"`
+	Deadline  int64  `json:"deadline"`
+	Signature string `json:"signature"`
+}
+
+type PreflightCheck struct {
+	Message string `json:"Message,omitempty"`
+	Name    string `json:"Name,omitempty"`
+	Passed  bool   `json:"Passed,omitempty"`
+}
+
+type PreflightChecks struct {
+	Checks   []PreflightCheck `json:"Checks,omitempty"`
+	Failures []PreflightCheck `json:"Failures,omitempty"`
+}
+
+type PriceCandle struct {
+	Average            string    `json:"Average,omitempty"`
+	BucketStart        time.Time `json:"BucketStart,omitempty"`
+	Close              string    `json:"Close,omitempty"`
+	CloseAt            time.Time `json:"CloseAt,omitempty"`
+	Count              int64     `json:"Count,omitempty"`
+	High               string    `json:"High,omitempty"`
+	ID                 int64     `json:"ID,omitempty"`
+	Interval           string    `json:"Interval,omitempty"`
+	Low                string    `json:"Low,omitempty"`
+	NFTContractAddress string    `json:"NFTContractAddress,omitempty"`
+	Open               string    `json:"Open,omitempty"`
+	OpenAt             time.Time `json:"OpenAt,omitempty"`
+	TokenAddress       string    `json:"TokenAddress,omitempty"`
+	Volume             string    `json:"Volume,omitempty"`
+}
+
+type PriceCandleView struct {
+	PriceCandle
+	AverageFormatted string        `json:"AverageFormatted,omitempty"`
+	CloseFormatted   string        `json:"CloseFormatted,omitempty"`
+	HighFormatted    string        `json:"HighFormatted,omitempty"`
+	LowFormatted     string        `json:"LowFormatted,omitempty"`
+	OpenFormatted    string        `json:"OpenFormatted,omitempty"`
+	PaymentToken     *PaymentToken `json:"PaymentToken,omitempty"`
+	VolumeFormatted  string        `json:"VolumeFormatted,omitempty"`
+}
+
+type PriceQuote struct {
+	Source       string    `json:"Source,omitempty"`
+	TokenAddress string    `json:"TokenAddress,omitempty"`
+	USD          float64   `json:"USD,omitempty"`
+	UpdatedAt    time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type Profile struct {
+	Address          string    `json:"Address,omitempty"`
+	AvatarImage      string    `json:"AvatarImage,omitempty"`
+	AvatarNFTAddress string    `json:"AvatarNFTAddress,omitempty"`
+	AvatarTokenID    int64     `json:"AvatarTokenID,omitempty"`
+	Bio              string    `json:"Bio,omitempty"`
+	CreatedAt        time.Time `json:"CreatedAt,omitempty"`
+	Discord          string    `json:"Discord,omitempty"`
+	DisplayName      string    `json:"DisplayName,omitempty"`
+	ID               int64     `json:"ID,omitempty"`
+	Twitter          string    `json:"Twitter,omitempty"`
+	UpdatedAt        time.Time `json:"UpdatedAt,omitempty"`
+	Username         *string   `json:"Username,omitempty"`
+	Website          string    `json:"Website,omitempty"`
+}
+
+type ProfileRequest struct {
+	AvatarNFTAddress string `json:"avatarNftAddress,omitempty"`
+	AvatarTokenID    int64  `json:"avatarTokenId,omitempty"`
+	Bio              string `json:"bio,omitempty"`
+	Discord          string `json:"discord,omitempty"`
+	DisplayName      string `json:"displayName,omitempty"`
+	Twitter          string `json:"twitter,omitempty"`
+	Username         string `json:"username,omitempty"`
+	Website          string `json:"website,omitempty"`
+}
+
+type ProfileSummary struct {
+	Address     string `json:"Address,omitempty"`
+	AvatarImage string `json:"AvatarImage,omitempty"`
+	DisplayName string `json:"DisplayName,omitempty"`
+	Username    string `json:"Username,omitempty"`
+}
+
+type Provenance struct {
+	ContractAddress string            `json:"ContractAddress,omitempty"`
+	Events          []ProvenanceEvent `json:"Events,omitempty"`
+	Holdings        []Holding         `json:"Holdings,omitempty"`
+	TokenID         int64             `json:"TokenID,omitempty"`
+}
+
+type ProvenanceEvent struct {
+	BlockNumber     int64           `json:"BlockNumber,omitempty"`
+	From            string          `json:"From,omitempty"`
+	FromProfile     *ProfileSummary `json:"FromProfile,omitempty"`
+	OrderIndex      *int64          `json:"OrderIndex,omitempty"`
+	PaymentToken    *PaymentToken   `json:"PaymentToken,omitempty"`
+	Price           string          `json:"Price,omitempty"`
+	PriceFormatted  string          `json:"PriceFormatted,omitempty"`
+	Timestamp       time.Time       `json:"Timestamp,omitempty"`
+	To              string          `json:"To,omitempty"`
+	ToProfile       *ProfileSummary `json:"ToProfile,omitempty"`
+	TokenAddress    string          `json:"TokenAddress,omitempty"`
+	TransactionHash string          `json:"TransactionHash,omitempty"`
+	Type            string          `json:"Type,omitempty"`
+}
+
+type RequiredTransaction struct {
+	Args        []string `json:"Args,omitempty"`
+	Data        string   `json:"Data,omitempty"`
+	Description string   `json:"Description,omitempty"`
+	Method      string   `json:"Method,omitempty"`
+	To          string   `json:"To,omitempty"`
+}
+
+type RetryJob struct {
+	Attempts        int64     `json:"Attempts,omitempty"`
+	ContractAddress string    `json:"ContractAddress,omitempty"`
+	CreatedAt       time.Time `json:"CreatedAt,omitempty"`
+	ID              int64     `json:"ID,omitempty"`
+	JobType         string    `json:"JobType,omitempty"`
+	LastError       string    `json:"LastError,omitempty"`
+	NextRetryAt     time.Time `json:"NextRetryAt,omitempty"`
+	// 0: 待重试, 1: 已完成, 2: 死信, 3: 执行中
+	Status    int64     `json:"Status,omitempty"`
+	TokenID   int64     `json:"TokenID,omitempty"`
+	UpdatedAt time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type SearchResult struct {
+	Address         string  `json:"Address,omitempty"`
+	ContractAddress string  `json:"ContractAddress,omitempty"`
+	Image           string  `json:"Image,omitempty"`
+	Score           float64 `json:"Score,omitempty"`
+	Subtitle        string  `json:"Subtitle,omitempty"`
+	Title           string  `json:"Title,omitempty"`
+	TokenID         int64   `json:"TokenID,omitempty"`
+	TransactionHash string  `json:"TransactionHash,omitempty"`
+	Type            string  `json:"Type,omitempty"`
+}
+
+type Session struct {
+	Address   string     `json:"Address,omitempty"`
+	ChainID   int64      `json:"ChainID,omitempty"`
+	CreatedAt time.Time  `json:"CreatedAt,omitempty"`
+	ExpiresAt time.Time  `json:"ExpiresAt,omitempty"`
+	ID        int64      `json:"ID,omitempty"`
+	RevokedAt *time.Time `json:"RevokedAt,omitempty"`
+	TokenID   string     `json:"TokenID,omitempty"`
+	UserAgent string     `json:"UserAgent,omitempty"`
+}
+
+type SignableTypedData struct {
+	Hash      string    `json:"Hash,omitempty"`
+	TypedData TypedData `json:"TypedData,omitempty"`
+}
+
+type SignatureRequest struct {
+	Signature string `json:"signature"`
+}
+
+type SignerNonceInfo struct {
+	IncreaseNonce SignableTypedData `json:"IncreaseNonce,omitempty"`
+	Nonce         int64             `json:"Nonce,omitempty"`
+	Seller        string            `json:"Seller,omitempty"`
+}
+
+// SimulateBuyRequest 提供完整签名 signature，或拆分后的 v/r/s
+type SimulateBuyRequest struct {
+	Buyer     string `json:"buyer"`
+	Deadline  int64  `json:"deadline"`
+	R         string `json:"r,omitempty"`
+	S         string `json:"s,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	V         int64  `json:"v,omitempty"`
+}
+
+type SplitSignature struct {
+	Deadline   int64  `json:"Deadline,omitempty"`
+	OrderIndex int64  `json:"OrderIndex,omitempty"`
+	R          string `json:"R,omitempty"`
+	S          string `json:"S,omitempty"`
+	V          int64  `json:"V,omitempty"`
+}
+
+type TokenFloor struct {
+	ListedCount    int64         `json:"ListedCount,omitempty"`
+	PaymentToken   *PaymentToken `json:"PaymentToken,omitempty"`
+	Price          string        `json:"Price,omitempty"`
+	PriceFormatted string        `json:"PriceFormatted,omitempty"`
+	PriceUSD       *float64      `json:"PriceUSD,omitempty"`
+	TokenAddress   string        `json:"TokenAddress,omitempty"`
+}
+
+type TokenStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type TraitFacet struct {
+	Count       int64             `json:"Count,omitempty"`
+	ListedCount int64             `json:"ListedCount,omitempty"`
+	Max         *float64          `json:"Max,omitempty"`
+	Min         *float64          `json:"Min,omitempty"`
+	Numeric     bool              `json:"Numeric,omitempty"`
+	TraitType   string            `json:"TraitType,omitempty"`
+	Values      []TraitValueCount `json:"Values,omitempty"`
+}
+
+type TraitValueCount struct {
+	Count       int64  `json:"Count,omitempty"`
+	ListedCount int64  `json:"ListedCount,omitempty"`
+	TraitType   string `json:"TraitType,omitempty"`
+	Value       string `json:"Value,omitempty"`
+}
+
+type TypedDataTypesValueItem struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+// TypedData 可直接用于 eth_signTypedData_v4 的 EIP-712 数据
+type TypedData struct {
+	Domain      map[string]interface{}               `json:"domain,omitempty"`
+	Message     map[string]interface{}               `json:"message,omitempty"`
+	PrimaryType string                               `json:"primaryType,omitempty"`
+	Types       map[string][]TypedDataTypesValueItem `json:"types,omitempty"`
+}
+
+type WatchRequest struct {
+	ContractAddress string `json:"contractAddress"`
+}
+
+type Webhook struct {
+	Active     bool      `json:"Active,omitempty"`
+	Address    string    `json:"Address,omitempty"`
+	Collection string    `json:"Collection,omitempty"`
+	CreatedAt  time.Time `json:"CreatedAt,omitempty"`
+	// 逗号分隔的事件类型
+	Events    string    `json:"Events,omitempty"`
+	ID        int64     `json:"ID,omitempty"`
+	Owner     string    `json:"Owner,omitempty"`
+	URL       string    `json:"URL,omitempty"`
+	UpdatedAt time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type WebhookDelivery struct {
+	Attempts       int64      `json:"Attempts,omitempty"`
+	CreatedAt      time.Time  `json:"CreatedAt,omitempty"`
+	DeliveredAt    *time.Time `json:"DeliveredAt,omitempty"`
+	EventID        string     `json:"EventID,omitempty"`
+	EventType      string     `json:"EventType,omitempty"`
+	ID             int64      `json:"ID,omitempty"`
+	LastError      string     `json:"LastError,omitempty"`
+	NextAttemptAt  time.Time  `json:"NextAttemptAt,omitempty"`
+	Payload        string     `json:"Payload,omitempty"`
+	ReplayOf       int64      `json:"ReplayOf,omitempty"`
+	ResponseStatus int64      `json:"ResponseStatus,omitempty"`
+	// 0: 待投递, 1: 已送达, 2: 已放弃
+	Status    int64     `json:"Status,omitempty"`
+	UpdatedAt time.Time `json:"UpdatedAt,omitempty"`
+	WebhookID int64     `json:"WebhookID,omitempty"`
+}
+
+type WebhookRequest struct {
+	Active     *bool  `json:"active,omitempty"`
+	Address    string `json:"address,omitempty"`
+	Collection string `json:"collection,omitempty"`
+	// 订阅的事件类型，为空时订阅全部
+	Events []string `json:"events,omitempty"`
+	URL    string   `json:"url"`
+}
+
+type WebhookWithSecret struct {
+	Secret  string  `json:"secret"`
+	Webhook Webhook `json:"webhook"`
+}
+
+// GetAlertsParams 是 GetAlerts 的查询参数
+type GetAlertsParams struct {
+	// 为 true 时只返回未读
+	Unread bool `query:"unread"`
+	// 上一页最后一条记录的ID
+	Before int64 `query:"before"`
+	Limit  int64 `query:"limit"`
+}
+
+type GetAlertsResponse struct {
+	Alerts []Alert `json:"alerts"`
+	Unread int64   `json:"unread"`
+}
+
+type MarkAlertsReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// GetAuctionsParams 是 GetAuctions 的查询参数
+type GetAuctionsParams struct {
+	// 逗号分隔的 active、settled、cancelled、no_winner
+	Status string `query:"status"`
+}
+
+// QueryGraphQLGetParams 是 QueryGraphQLGet 的查询参数
+type QueryGraphQLGetParams struct {
+	Query string `query:"query"`
+	// JSON 编码的变量
+	Variables     string `query:"variables"`
+	OperationName string `query:"operationName"`
+}
+
+// GetListingsParams 是 GetListings 的查询参数
+type GetListingsParams struct {
+	Seller string `query:"seller"`
+	// 逗号分隔的 active、filled、cancelled、expired
+	Status string `query:"status"`
+}
+
+// GetSignerNonceParams 是 GetSignerNonce 的查询参数
+type GetSignerNonceParams struct {
+	Seller string `query:"seller"`
+}
+
+type BatchGetNFTsResponse struct {
+	Results map[string]*NFTBatchEntry `json:"results"`
+}
+
+// GetCollectionParams 是 GetCollection 的查询参数
+type GetCollectionParams struct {
+	// 属性条件 `类型:取值`，可重复，同类型的取值之间为“或”
+	Trait []string `query:"trait"`
+	// 数值区间 `类型:最小值:最大值`，可省略一端
+	Range []string `query:"range"`
+	Match string   `query:"match"`
+	Sort  string   `query:"sort"`
+}
+
+type GetCollectionResponse struct {
+	Collection NFTCollection `json:"collection"`
+	NFTs       []NFTView     `json:"nfts"`
+}
+
+// GetPriceHistoryParams 是 GetPriceHistory 的查询参数
+type GetPriceHistoryParams struct {
+	Interval string `query:"interval"`
+	// 只统计该支付代币的成交
+	Token string `query:"token"`
+	// 起始 Unix 时间戳
+	From int64 `query:"from"`
+	// 结束 Unix 时间戳
+	To int64 `query:"to"`
+}
+
+type GetPriceHistoryResponse struct {
+	Candles  []PriceCandleView `json:"candles"`
+	Interval string            `json:"interval"`
+}
+
+// GetCollectionOffersParams 是 GetCollectionOffers 的查询参数
+type GetCollectionOffersParams struct {
+	// 为 true 时包含余额或授权不足的出价
+	IncludeInvalid bool `query:"includeInvalid"`
+}
+
+type GetNFTResponse struct {
+	Attributes    []NFTAttribute `json:"attributes"`
+	FavoriteCount int64          `json:"favoriteCount"`
+	Favorited     bool           `json:"favorited"`
+	NFT           NFTView        `json:"nft"`
+	Rarity        *NFTRarity     `json:"rarity"`
+}
+
+// GetNFTOffersParams 是 GetNFTOffers 的查询参数
+type GetNFTOffersParams struct {
+	// 为 true 时包含余额或授权不足的出价
+	IncludeInvalid bool `query:"includeInvalid"`
+}
+
+// GetNotificationsParams 是 GetNotifications 的查询参数
+type GetNotificationsParams struct {
+	// 为 true 时只返回未读
+	Unread bool `query:"unread"`
+	// 上一页最后一条记录的ID
+	Before int64 `query:"before"`
+	Limit  int64 `query:"limit"`
+}
+
+type GetNotificationsResponse struct {
+	Notifications []NotificationView `json:"notifications"`
+	Unread        int64              `json:"unread"`
+}
+
+type MarkNotificationsReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// GetBidderOffersParams 是 GetBidderOffers 的查询参数
+type GetBidderOffersParams struct {
+	Bidder string `query:"bidder"`
+}
+
+// GetBidderNonceParams 是 GetBidderNonce 的查询参数
+type GetBidderNonceParams struct {
+	Bidder string `query:"bidder"`
+}
+
+type BatchGetOrdersResponse struct {
+	Results map[string]*OrderView `json:"results"`
+}
+
+// GetOrderPermitParams 是 GetOrderPermit 的查询参数
+type GetOrderPermitParams struct {
+	Buyer string `query:"buyer"`
+	// permit 过期的 Unix 时间戳，默认为一小时后
+	Deadline int64 `query:"deadline"`
+}
+
+// SearchParams 是 Search 的查询参数
+type SearchParams struct {
+	Q     string `query:"q"`
+	Type  string `query:"type"`
+	Limit int64  `query:"limit"`
+}
+
+// AutocompleteParams 是 Autocomplete 的查询参数
+type AutocompleteParams struct {
+	Q     string `query:"q"`
+	Limit int64  `query:"limit"`
+}
+
+// GetWatchlistFeedParams 是 GetWatchlistFeed 的查询参数
+type GetWatchlistFeedParams struct {
+	// 逗号分隔的 listing、price_drop、sale
+	Type string `query:"type"`
+	// 上一页最后一条记录的ID
+	Before int64 `query:"before"`
+	Limit  int64 `query:"limit"`
+}
+
+// GetWebhookDeliveriesParams 是 GetWebhookDeliveries 的查询参数
+type GetWebhookDeliveriesParams struct {
+	// 逗号分隔的 pending、succeeded、dead
+	Status string `query:"status"`
+}
+
+// GetFailedJobs 获取元数据重试死信任务
+func (c *Client) GetFailedJobs(ctx context.Context) ([]RetryJob, error) {
+	var out []RetryJob
+	err := c.do(ctx, "GET", "/admin/jobs", nil, nil, &out)
+	return out, err
+}
+
+// RetryJob 重新执行任务
+func (c *Client) RetryJob(ctx context.Context, id int64) (*RetryJob, error) {
+	var out RetryJob
+	if err := c.do(ctx, "POST", "/admin/jobs/"+pathParam(id)+"/retry", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RebuildPriceHistory 按成交记录重建K线
+func (c *Client) RebuildPriceHistory(ctx context.Context) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "POST", "/admin/price-history/rebuild", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefreshToken 重新从链上读取支付代币信息
+func (c *Client) RefreshToken(ctx context.Context, address string) (*PaymentToken, error) {
+	var out PaymentToken
+	if err := c.do(ctx, "POST", "/admin/tokens/"+pathParam(address)+"/refresh", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetTokenListStatus 设置支付代币的白名单或黑名单状态
+func (c *Client) SetTokenListStatus(ctx context.Context, address string, body TokenStatusRequest) (*PaymentToken, error) {
+	var out PaymentToken
+	if err := c.do(ctx, "PUT", "/admin/tokens/"+pathParam(address)+"/status", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAlerts 获取提醒收件箱
+func (c *Client) GetAlerts(ctx context.Context, params *GetAlertsParams) (*GetAlertsResponse, error) {
+	var out GetAlertsResponse
+	if err := c.do(ctx, "GET", "/alerts", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MarkAlertsRead 标记提醒为已读，ids 为空时标记全部
+func (c *Client) MarkAlertsRead(ctx context.Context, body MarkReadRequest) (*MarkAlertsReadResponse, error) {
+	var out MarkAlertsReadResponse
+	if err := c.do(ctx, "POST", "/alerts/read", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAlertRules 获取提醒规则
+func (c *Client) GetAlertRules(ctx context.Context) ([]AlertRule, error) {
+	var out []AlertRule
+	err := c.do(ctx, "GET", "/alerts/rules", nil, nil, &out)
+	return out, err
+}
+
+// CreateAlertRule 创建提醒规则
+func (c *Client) CreateAlertRule(ctx context.Context, body AlertRuleRequest) (*AlertRule, error) {
+	var out AlertRule
+	if err := c.do(ctx, "POST", "/alerts/rules", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAlertRule 删除提醒规则
+func (c *Client) DeleteAlertRule(ctx context.Context, id int64) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "DELETE", "/alerts/rules/"+pathParam(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAlertRule 修改提醒规则
+func (c *Client) UpdateAlertRule(ctx context.Context, id int64, body AlertRuleRequest) (*AlertRule, error) {
+	var out AlertRule
+	if err := c.do(ctx, "PUT", "/alerts/rules/"+pathParam(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuctions 获取拍卖
+func (c *Client) GetAuctions(ctx context.Context, params *GetAuctionsParams) ([]AuctionView, error) {
+	var out []AuctionView
+	err := c.do(ctx, "GET", "/auctions", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// CreateAuction 提交已签名的拍卖
+func (c *Client) CreateAuction(ctx context.Context, body AuctionRequest) (*AuctionView, error) {
+	var out AuctionView
+	if err := c.do(ctx, "POST", "/auctions", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildAuctionTypedData 构建待卖家签名的拍卖数据
+func (c *Client) BuildAuctionTypedData(ctx context.Context, body AuctionRequest) (*SignableTypedData, error) {
+	var out SignableTypedData
+	if err := c.do(ctx, "POST", "/auctions/typed-data", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuction 获取拍卖
+func (c *Client) GetAuction(ctx context.Context, id int64) (*AuctionView, error) {
+	var out AuctionView
+	if err := c.do(ctx, "GET", "/auctions/"+pathParam(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuctionBids 获取拍卖的出价
+func (c *Client) GetAuctionBids(ctx context.Context, id int64) ([]AuctionBid, error) {
+	var out []AuctionBid
+	err := c.do(ctx, "GET", "/auctions/"+pathParam(id)+"/bids", nil, nil, &out)
+	return out, err
+}
+
+// PlaceBid 提交已签名的拍卖出价
+func (c *Client) PlaceBid(ctx context.Context, id int64, body AuctionBidRequest) (*AuctionView, error) {
+	var out AuctionView
+	if err := c.do(ctx, "POST", "/auctions/"+pathParam(id)+"/bids", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildBidTypedData 构建待买家签名的拍卖出价数据
+func (c *Client) BuildBidTypedData(ctx context.Context, id int64, body AuctionBidRequest) (*SignableTypedData, error) {
+	var out SignableTypedData
+	if err := c.do(ctx, "POST", "/auctions/"+pathParam(id)+"/bids/typed-data", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuctionCancelTypedData 构建取消拍卖的签名数据
+func (c *Client) GetAuctionCancelTypedData(ctx context.Context, id int64) (*SignableTypedData, error) {
+	var out SignableTypedData
+	if err := c.do(ctx, "GET", "/auctions/"+pathParam(id)+"/cancel", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelAuction 使用卖家签名取消拍卖
+func (c *Client) CancelAuction(ctx context.Context, id int64, body SignatureRequest) (*AuctionView, error) {
+	var out AuctionView
+	if err := c.do(ctx, "POST", "/auctions/"+pathParam(id)+"/cancel", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Login 使用 SIWE 签名登录
+func (c *Client) Login(ctx context.Context, body LoginRequest) (*AuthSession, error) {
+	var out AuthSession
+	if err := c.do(ctx, "POST", "/auth/login", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Logout 退出当前会话
+func (c *Client) Logout(ctx context.Context) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "POST", "/auth/logout", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LogoutAll 退出当前地址的全部会话
+func (c *Client) LogoutAll(ctx context.Context) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "POST", "/auth/logout/all", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuthNonce 获取 SIWE 登录使用的 nonce
+func (c *Client) GetAuthNonce(ctx context.Context) (*AuthNonce, error) {
+	var out AuthNonce
+	if err := c.do(ctx, "GET", "/auth/nonce", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSession 获取当前会话
+func (c *Client) GetSession(ctx context.Context) (*Session, error) {
+	var out Session
+	if err := c.do(ctx, "GET", "/auth/session", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSessions 获取当前地址的全部有效会话
+func (c *Client) GetSessions(ctx context.Context) ([]Session, error) {
+	var out []Session
+	err := c.do(ctx, "GET", "/auth/sessions", nil, nil, &out)
+	return out, err
+}
+
+// GetDocs 接口文档页面
+func (c *Client) GetDocs(ctx context.Context) (string, error) {
+	return c.doText(ctx, "GET", "/docs", nil)
+}
+
+// GetFavorites 获取收藏的 NFT
+func (c *Client) GetFavorites(ctx context.Context) ([]NFTView, error) {
+	var out []NFTView
+	err := c.do(ctx, "GET", "/favorites", nil, nil, &out)
+	return out, err
+}
+
+// AddFavorite 收藏 NFT
+func (c *Client) AddFavorite(ctx context.Context, body FavoriteRequest) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "POST", "/favorites", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveFavorite 取消收藏 NFT
+func (c *Client) RemoveFavorite(ctx context.Context, contractAddress string, tokenID int64) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "DELETE", "/favorites/"+pathParam(contractAddress)+"/"+pathParam(tokenID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// QueryGraphQLGet 通过查询参数执行 GraphQL 查询
+func (c *Client) QueryGraphQLGet(ctx context.Context, params *QueryGraphQLGetParams) (*GraphQLResponse, error) {
+	var out GraphQLResponse
+	if err := c.do(ctx, "GET", "/graphql", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// QueryGraphQL 执行 GraphQL 查询
+func (c *Client) QueryGraphQL(ctx context.Context, body GraphQLRequest) (*GraphQLResponse, error) {
+	var out GraphQLResponse
+	if err := c.do(ctx, "POST", "/graphql", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGraphQLSchema 获取 GraphQL SDL
+func (c *Client) GetGraphQLSchema(ctx context.Context) (string, error) {
+	return c.doText(ctx, "GET", "/graphql/schema", nil)
+}
+
+// GetListings 获取链下签名挂单
+func (c *Client) GetListings(ctx context.Context, params *GetListingsParams) ([]ListingView, error) {
+	var out []ListingView
+	err := c.do(ctx, "GET", "/listings", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// SubmitListing 提交已签名的挂单
+func (c *Client) SubmitListing(ctx context.Context, body ListingRequest) (*ListingView, error) {
+	var out ListingView
+	if err := c.do(ctx, "POST", "/listings", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSignerNonce 获取卖家当前的挂单 nonce
+func (c *Client) GetSignerNonce(ctx context.Context, params *GetSignerNonceParams) (*SignerNonceInfo, error) {
+	var out SignerNonceInfo
+	if err := c.do(ctx, "GET", "/listings/nonce", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IncreaseNonce 使用卖家签名增加 nonce，使之前的挂单全部失效
+func (c *Client) IncreaseNonce(ctx context.Context, body IncreaseNonceRequest) (*SignerNonceInfo, error) {
+	var out SignerNonceInfo
+	if err := c.do(ctx, "POST", "/listings/nonce", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildListingTypedData 构建待卖家签名的挂单数据
+func (c *Client) BuildListingTypedData(ctx context.Context, body ListingRequest) (*SignableTypedData, error) {
+	var out SignableTypedData
+	if err := c.do(ctx, "POST", "/listings/typed-data", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetListing 获取链下签名挂单
+func (c *Client) GetListing(ctx context.Context, hash string) (*ListingView, error) {
+	var out ListingView
+	if err := c.do(ctx, "GET", "/listings/"+pathParam(hash), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCollections 获取全部 NFT 系列
+func (c *Client) GetCollections(ctx context.Context) ([]NFTCollection, error) {
+	var out []NFTCollection
+	err := c.do(ctx, "GET", "/nft", nil, nil, &out)
+	return out, err
+}
+
+// BatchGetNFTs 批量获取 NFT 及其属性
+func (c *Client) BatchGetNFTs(ctx context.Context, body NFTBatchRequest) (*BatchGetNFTsResponse, error) {
+	var out BatchGetNFTsResponse
+	if err := c.do(ctx, "POST", "/nft/batch", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCollection 获取 NFT 系列及其 NFT，支持按属性筛选
+func (c *Client) GetCollection(ctx context.Context, contractAddress string, params *GetCollectionParams) (*GetCollectionResponse, error) {
+	var out GetCollectionResponse
+	if err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress), encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPriceHistory 获取 NFT 系列的成交K线
+func (c *Client) GetPriceHistory(ctx context.Context, contractAddress string, params *GetPriceHistoryParams) (*GetPriceHistoryResponse, error) {
+	var out GetPriceHistoryResponse
+	if err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/history/prices", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCollectionOffers 获取 NFT 系列的系列出价
+func (c *Client) GetCollectionOffers(ctx context.Context, contractAddress string, params *GetCollectionOffersParams) ([]OfferView, error) {
+	var out []OfferView
+	err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/offers", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// GetCollectionStats 获取 NFT 系列的挂单和成交统计
+func (c *Client) GetCollectionStats(ctx context.Context, contractAddress string) (*CollectionStats, error) {
+	var out CollectionStats
+	if err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/stats", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCollectionTraits 获取 NFT 系列的属性统计
+func (c *Client) GetCollectionTraits(ctx context.Context, contractAddress string) ([]TraitFacet, error) {
+	var out []TraitFacet
+	err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/traits", nil, nil, &out)
+	return out, err
+}
+
+// GetNFT 获取 NFT 详情，登录时同时返回是否已收藏
+func (c *Client) GetNFT(ctx context.Context, contractAddress string, tokenID int64) (*GetNFTResponse, error) {
+	var out GetNFTResponse
+	if err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/"+pathParam(tokenID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNFTTransferHistory 获取 NFT 的转移记录
+func (c *Client) GetNFTTransferHistory(ctx context.Context, contractAddress string, tokenID int64) ([]NFTTransferEventView, error) {
+	var out []NFTTransferEventView
+	err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/"+pathParam(tokenID)+"/history", nil, nil, &out)
+	return out, err
+}
+
+// GetNFTOffers 获取针对 NFT 的出价
+func (c *Client) GetNFTOffers(ctx context.Context, contractAddress string, tokenID int64, params *GetNFTOffersParams) ([]OfferView, error) {
+	var out []OfferView
+	err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/"+pathParam(tokenID)+"/offers", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// GetNFTProvenance 获取 NFT 的价格和所有权溯源
+func (c *Client) GetNFTProvenance(ctx context.Context, contractAddress string, tokenID int64) (*Provenance, error) {
+	var out Provenance
+	if err := c.do(ctx, "GET", "/nft/"+pathParam(contractAddress)+"/"+pathParam(tokenID)+"/provenance", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNotifications 获取站内通知
+func (c *Client) GetNotifications(ctx context.Context, params *GetNotificationsParams) (*GetNotificationsResponse, error) {
+	var out GetNotificationsResponse
+	if err := c.do(ctx, "GET", "/notifications", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MarkNotificationsRead 标记通知为已读，ids 为空时标记全部
+func (c *Client) MarkNotificationsRead(ctx context.Context, body MarkReadRequest) (*MarkNotificationsReadResponse, error) {
+	var out MarkNotificationsReadResponse
+	if err := c.do(ctx, "POST", "/notifications/read", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBidderOffers 获取买家的出价
+func (c *Client) GetBidderOffers(ctx context.Context, params *GetBidderOffersParams) ([]OfferView, error) {
+	var out []OfferView
+	err := c.do(ctx, "GET", "/offers", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// SubmitOffer 提交已签名的出价
+func (c *Client) SubmitOffer(ctx context.Context, body OfferRequest) (*OfferView, error) {
+	var out OfferView
+	if err := c.do(ctx, "POST", "/offers", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBidderNonce 获取买家当前的出价 nonce
+func (c *Client) GetBidderNonce(ctx context.Context, params *GetBidderNonceParams) (*BidderNonceInfo, error) {
+	var out BidderNonceInfo
+	if err := c.do(ctx, "GET", "/offers/nonce", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IncreaseOfferNonce 使用买家签名增加 nonce，使之前的出价全部失效
+func (c *Client) IncreaseOfferNonce(ctx context.Context, body IncreaseOfferNonceRequest) (*BidderNonceInfo, error) {
+	var out BidderNonceInfo
+	if err := c.do(ctx, "POST", "/offers/nonce", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildOfferTypedData 构建待买家签名的出价数据
+func (c *Client) BuildOfferTypedData(ctx context.Context, body OfferRequest) (*SignableTypedData, error) {
+	var out SignableTypedData
+	if err := c.do(ctx, "POST", "/offers/typed-data", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOffer 获取出价
+func (c *Client) GetOffer(ctx context.Context, id int64) (*OfferView, error) {
+	var out OfferView
+	if err := c.do(ctx, "GET", "/offers/"+pathParam(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOfferCancelTypedData 构建取消出价的签名数据
+func (c *Client) GetOfferCancelTypedData(ctx context.Context, id int64) (*SignableTypedData, error) {
+	var out SignableTypedData
+	if err := c.do(ctx, "GET", "/offers/"+pathParam(id)+"/cancel", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelOffer 使用买家签名取消出价
+func (c *Client) CancelOffer(ctx context.Context, id int64, body SignatureRequest) (*OfferView, error) {
+	var out OfferView
+	if err := c.do(ctx, "POST", "/offers/"+pathParam(id)+"/cancel", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPISpec 获取 JSON 格式的 OpenAPI 文档
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	err := c.do(ctx, "GET", "/openapi.json", nil, nil, &out)
+	return out, err
+}
+
+// GetOpenAPISpecYAML 获取 YAML 格式的 OpenAPI 文档
+func (c *Client) GetOpenAPISpecYAML(ctx context.Context) (string, error) {
+	return c.doText(ctx, "GET", "/openapi.yaml", nil)
+}
+
+// GetOrderByNFT 获取 NFT 最新的链上订单
+func (c *Client) GetOrderByNFT(ctx context.Context, contractAddress string, tokenID int64) (*OrderView, error) {
+	var out OrderView
+	if err := c.do(ctx, "GET", "/order/"+pathParam(contractAddress)+"/"+pathParam(tokenID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOrders 获取全部链上订单，以及有效和已成交的链下签名挂单
+func (c *Client) GetOrders(ctx context.Context) ([]OrderView, error) {
+	var out []OrderView
+	err := c.do(ctx, "GET", "/orders", nil, nil, &out)
+	return out, err
+}
+
+// BatchGetOrders 批量获取 NFT 最新的链上订单
+func (c *Client) BatchGetOrders(ctx context.Context, body NFTBatchRequest) (*BatchGetOrdersResponse, error) {
+	var out BatchGetOrdersResponse
+	if err := c.do(ctx, "POST", "/orders/batch", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreflightListing 检查 createOrder 挂单前的授权和持有情况
+func (c *Client) PreflightListing(ctx context.Context, body ListingPreflightRequest) (*ListingPreflight, error) {
+	var out ListingPreflight
+	if err := c.do(ctx, "POST", "/orders/preflight", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOrderPermit 构建使用 permit 购买链上订单的签名数据
+func (c *Client) GetOrderPermit(ctx context.Context, id int64, params *GetOrderPermitParams) (*OrderPermit, error) {
+	var out OrderPermit
+	if err := c.do(ctx, "GET", "/orders/"+pathParam(id)+"/permit", encodeQuery(params), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyOrderPermit 校验 permit 签名并拆分为 v/r/s
+func (c *Client) VerifyOrderPermit(ctx context.Context, id int64, body PermitVerifyRequest) (*SplitSignature, error) {
+	var out SplitSignature
+	if err := c.do(ctx, "POST", "/orders/"+pathParam(id)+"/permit/verify", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SimulateBuy 模拟购买链上订单
+func (c *Client) SimulateBuy(ctx context.Context, id int64, body SimulateBuyRequest) (*BuySimulation, error) {
+	var out BuySimulation
+	if err := c.do(ctx, "POST", "/orders/"+pathParam(id)+"/simulate", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyProfile 获取当前登录地址的资料
+func (c *Client) GetMyProfile(ctx context.Context) (*Profile, error) {
+	var out Profile
+	if err := c.do(ctx, "GET", "/profile", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProfile 修改当前登录地址的资料
+func (c *Client) UpdateProfile(ctx context.Context, body ProfileRequest) (*Profile, error) {
+	var out Profile
+	if err := c.do(ctx, "PUT", "/profile", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProfile 按地址或用户名获取资料
+func (c *Client) GetProfile(ctx context.Context, address string) (*Profile, error) {
+	var out Profile
+	if err := c.do(ctx, "GET", "/profiles/"+pathParam(address), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Search 搜索 NFT 系列、NFT、地址和交易
+func (c *Client) Search(ctx context.Context, params *SearchParams) ([]SearchResult, error) {
+	var out []SearchResult
+	err := c.do(ctx, "GET", "/search", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// Autocomplete 搜索关键词补全
+func (c *Client) Autocomplete(ctx context.Context, params *AutocompleteParams) ([]string, error) {
+	var out []string
+	err := c.do(ctx, "GET", "/search/autocomplete", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// GetTokens 获取支付代币
+func (c *Client) GetTokens(ctx context.Context) ([]PaymentToken, error) {
+	var out []PaymentToken
+	err := c.do(ctx, "GET", "/tokens", nil, nil, &out)
+	return out, err
+}
+
+// GetToken 获取支付代币
+func (c *Client) GetToken(ctx context.Context, address string) (*PaymentToken, error) {
+	var out PaymentToken
+	if err := c.do(ctx, "GET", "/tokens/"+pathParam(address), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTokenPrice 获取支付代币的美元报价
+func (c *Client) GetTokenPrice(ctx context.Context, address string) (*PriceQuote, error) {
+	var out PriceQuote
+	if err := c.do(ctx, "GET", "/tokens/"+pathParam(address)+"/price", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWatchlist 获取关注的 NFT 系列
+func (c *Client) GetWatchlist(ctx context.Context) ([]NFTCollection, error) {
+	var out []NFTCollection
+	err := c.do(ctx, "GET", "/watchlist", nil, nil, &out)
+	return out, err
+}
+
+// AddWatch 关注 NFT 系列
+func (c *Client) AddWatch(ctx context.Context, body WatchRequest) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "POST", "/watchlist", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWatchlistFeed 获取关注系列的市场动态
+func (c *Client) GetWatchlistFeed(ctx context.Context, params *GetWatchlistFeedParams) ([]MarketActivityView, error) {
+	var out []MarketActivityView
+	err := c.do(ctx, "GET", "/watchlist/feed", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// RemoveWatch 取消关注 NFT 系列
+func (c *Client) RemoveWatch(ctx context.Context, contractAddress string) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "DELETE", "/watchlist/"+pathParam(contractAddress), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhooks 获取当前地址的 Webhook
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var out []Webhook
+	err := c.do(ctx, "GET", "/webhooks", nil, nil, &out)
+	return out, err
+}
+
+// CreateWebhook 创建 Webhook，签名密钥只在创建和轮换时返回
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookRequest) (*WebhookWithSecret, error) {
+	var out WebhookWithSecret
+	if err := c.do(ctx, "POST", "/webhooks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhook 删除 Webhook 及其投递记录
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) (*Message, error) {
+	var out Message
+	if err := c.do(ctx, "DELETE", "/webhooks/"+pathParam(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhook 获取 Webhook
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	var out Webhook
+	if err := c.do(ctx, "GET", "/webhooks/"+pathParam(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateWebhook 修改 Webhook
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, body WebhookRequest) (*Webhook, error) {
+	var out Webhook
+	if err := c.do(ctx, "PUT", "/webhooks/"+pathParam(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhookDeliveries 获取投递记录
+func (c *Client) GetWebhookDeliveries(ctx context.Context, id int64, params *GetWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	var out []WebhookDelivery
+	err := c.do(ctx, "GET", "/webhooks/"+pathParam(id)+"/deliveries", encodeQuery(params), nil, &out)
+	return out, err
+}
+
+// ReplayWebhookDelivery 重放一次投递
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, id int64, deliveryID int64) (*WebhookDelivery, error) {
+	var out WebhookDelivery
+	if err := c.do(ctx, "POST", "/webhooks/"+pathParam(id)+"/deliveries/"+pathParam(deliveryID)+"/replay", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RotateWebhookSecret 轮换签名密钥
+func (c *Client) RotateWebhookSecret(ctx context.Context, id int64) (*WebhookWithSecret, error) {
+	var out WebhookWithSecret
+	if err := c.do(ctx, "POST", "/webhooks/"+pathParam(id)+"/secret", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,464 @@
+// clientgen 根据 api/openapi/openapi.yaml 生成 client 包中的类型和接口方法
+package main
+
+import (
+	"backend/api/openapi"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 与 Go 命名习惯一致的缩写
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ids":  "IDs",
+	"nft":  "NFT",
+	"nfts": "NFTs",
+	"uri":  "URI",
+	"url":  "URL",
+	"usd":  "USD",
+}
+
+func main() {
+	output := flag.String("o", "client/client_gen.go", "输出文件")
+	flag.Parse()
+
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("加载接口文档失败: %v", err)
+	}
+
+	g := &generator{spec: spec, emitted: make(map[string]bool), requestTypes: make(map[string]bool)}
+	source, err := g.generate()
+	if err != nil {
+		log.Fatalf("生成客户端失败: %v", err)
+	}
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		log.Fatalf("写入 %s 失败: %v", *output, err)
+	}
+}
+
+type generator struct {
+	spec         *openapi.Spec
+	types        bytes.Buffer
+	methods      bytes.Buffer
+	emitted      map[string]bool
+	requestTypes map[string]bool // 作为请求体的组件，可选布尔字段使用指针以便发送 false
+	usesTime     bool
+}
+
+func (g *generator) generate() ([]byte, error) {
+	operations := g.spec.SortedOperations()
+
+	// 先标记请求体引用的组件
+	for _, op := range operations {
+		if op.RequestBody == nil {
+			continue
+		}
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			g.markRequest(media.Schema)
+		}
+	}
+
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.emitComponent(name); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, op := range operations {
+		if err := g.emitOperation(op); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by clientgen. DO NOT EDIT.\n\npackage client\n\nimport (\n\t\"context\"\n")
+	if g.usesTime {
+		out.WriteString("\t\"time\"\n")
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.types.Bytes())
+	out.Write(g.methods.Bytes())
+	return format.Source(out.Bytes())
+}
+
+func (g *generator) markRequest(schema *openapi.Schema) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		g.requestTypes[openapi.RefName(schema.Ref)] = true
+	}
+	for _, sub := range schema.AllOf {
+		g.markRequest(sub)
+	}
+}
+
+// 只包装另一个组件并允许 null 的 Schema，如 NullableOrderView
+func nullableWrapper(schema *openapi.Schema) *openapi.Schema {
+	if schema.Nullable && len(schema.AllOf) == 1 && len(schema.Properties) == 0 {
+		return schema.AllOf[0]
+	}
+	return nil
+}
+
+func isPrimitive(schema *openapi.Schema) bool {
+	switch schema.Type {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+	return false
+}
+
+func (g *generator) emitComponent(name string) error {
+	schema := g.spec.Components.Schemas[name]
+	if isPrimitive(schema) || nullableWrapper(schema) != nil {
+		return nil
+	}
+	return g.emitStruct(name, schema, g.requestTypes[name])
+}
+
+// 生成结构体，allOf 中的组件引用生成为嵌入字段
+func (g *generator) emitStruct(name string, schema *openapi.Schema, request bool) error {
+	if g.emitted[name] {
+		return nil
+	}
+	g.emitted[name] = true
+
+	var body bytes.Buffer
+	if schema.Description != "" {
+		fmt.Fprintf(&body, "%s\n", comment(name+" "+schema.Description))
+	}
+	fmt.Fprintf(&body, "type %s struct {\n", name)
+	required := make(map[string]bool)
+	for _, field := range schema.Required {
+		required[field] = true
+	}
+	for _, sub := range schema.AllOf {
+		if sub.Ref != "" {
+			fmt.Fprintf(&body, "\t%s\n", openapi.RefName(sub.Ref))
+			continue
+		}
+		for _, field := range sub.Required {
+			required[field] = true
+		}
+		if err := g.writeFields(&body, name, sub, required, request); err != nil {
+			return err
+		}
+	}
+	if err := g.writeFields(&body, name, schema, required, request); err != nil {
+		return err
+	}
+	body.WriteString("}\n\n")
+	g.types.Write(body.Bytes())
+	return nil
+}
+
+func (g *generator) writeFields(body *bytes.Buffer, parent string, schema *openapi.Schema, required map[string]bool, request bool) error {
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	for _, prop := range props {
+		fieldName := goName(prop)
+		fieldSchema := schema.Properties[prop]
+		typ, err := g.goType(fieldSchema, parent+fieldName)
+		if err != nil {
+			return err
+		}
+		tag := prop
+		if !required[prop] {
+			if request && typ == "bool" {
+				typ = "*bool"
+			}
+			tag += ",omitempty"
+		}
+		if fieldSchema.Description != "" {
+			fmt.Fprintf(body, "\t%s\n", comment(fieldSchema.Description))
+		}
+		fmt.Fprintf(body, "\t%s %s `json:%q`\n", fieldName, typ, tag)
+	}
+	return nil
+}
+
+// goType 返回 Schema 对应的 Go 类型，内联对象生成名为 name 的结构体
+func (g *generator) goType(schema *openapi.Schema, name string) (string, error) {
+	if schema == nil {
+		return "interface{}", nil
+	}
+	if schema.Ref != "" {
+		refName := openapi.RefName(schema.Ref)
+		target, err := g.spec.ResolveSchema(schema)
+		if err != nil {
+			return "", err
+		}
+		if isPrimitive(target) {
+			return g.goType(target, name)
+		}
+		if inner := nullableWrapper(target); inner != nil {
+			typ, err := g.goType(inner, name)
+			return "*" + typ, err
+		}
+		if target.Nullable {
+			return "*" + refName, nil
+		}
+		return refName, nil
+	}
+	if inner := nullableWrapper(schema); inner != nil {
+		typ, err := g.goType(inner, name)
+		return "*" + strings.TrimPrefix(typ, "*"), err
+	}
+
+	var typ string
+	switch schema.Type {
+	case "string":
+		typ = "string"
+		if schema.Format == "date-time" {
+			g.usesTime = true
+			typ = "time.Time"
+		}
+	case "integer":
+		typ = "int64"
+	case "number":
+		typ = "float64"
+	case "boolean":
+		typ = "bool"
+	case "array":
+		items, err := g.goType(schema.Items, name+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + items, nil
+	default:
+		if len(schema.Properties) > 0 || len(schema.AllOf) > 0 {
+			if err := g.emitStruct(name, schema, false); err != nil {
+				return "", err
+			}
+			if schema.Nullable {
+				return "*" + name, nil
+			}
+			return name, nil
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			value, err := g.goType(schema.AdditionalProperties.Schema, name+"Value")
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + value, nil
+		}
+		return "map[string]interface{}", nil
+	}
+	if schema.Nullable {
+		return "*" + typ, nil
+	}
+	return typ, nil
+}
+
+func (g *generator) emitOperation(op *openapi.Operation) error {
+	name := goName(op.OperationID)
+
+	response := successResponse(op)
+	if response == nil {
+		return fmt.Errorf("缺少成功响应")
+	}
+	contentType, media := responseMedia(response)
+	if contentType == "text/event-stream" {
+		// SSE 需要使用 EventSource 等流式客户端
+		return nil
+	}
+
+	args := []string{"ctx context.Context"}
+	var pathExpr []string
+	literal := ""
+	for _, segment := range strings.Split(op.Path, "/")[1:] {
+		if !strings.HasPrefix(segment, "{") {
+			literal += "/" + segment
+			continue
+		}
+		paramName := segment[1 : len(segment)-1]
+		param := findParam(op, "path", paramName)
+		if param == nil {
+			return fmt.Errorf("未定义路径参数 %s", paramName)
+		}
+		typ, err := g.goType(param.Schema, name+goName(paramName))
+		if err != nil {
+			return err
+		}
+		args = append(args, fmt.Sprintf("%s %s", paramName, typ))
+		pathExpr = append(pathExpr, fmt.Sprintf("%q", literal+"/"), fmt.Sprintf("pathParam(%s)", paramName))
+		literal = ""
+	}
+	if literal != "" {
+		pathExpr = append(pathExpr, fmt.Sprintf("%q", literal))
+	}
+
+	query := "nil"
+	if params, err := g.emitParams(op, name+"Params"); err != nil {
+		return err
+	} else if params != "" {
+		args = append(args, "params *"+params)
+		query = "encodeQuery(params)"
+	}
+
+	bodyExpr := "nil"
+	if op.RequestBody != nil {
+		media, ok := op.RequestBody.Content["application/json"]
+		if !ok {
+			return fmt.Errorf("只支持 JSON 请求体")
+		}
+		bodySchema := media.Schema
+		// 仅追加 required 的 allOf 使用被引用组件的类型
+		if len(bodySchema.AllOf) == 1 && len(bodySchema.Properties) == 0 {
+			bodySchema = bodySchema.AllOf[0]
+		}
+		typ, err := g.goType(bodySchema, name+"Request")
+		if err != nil {
+			return err
+		}
+		args = append(args, "body "+typ)
+		bodyExpr = "body"
+	}
+
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	fmt.Fprintf(&g.methods, "%s\n", comment(name+" "+summary))
+	path := strings.Join(pathExpr, " + ")
+
+	switch {
+	case media == nil:
+		fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) error {\n\treturn c.do(ctx, %q, %s, %s, %s, nil)\n}\n\n",
+			name, strings.Join(args, ", "), op.Method, path, query, bodyExpr)
+	case contentType != "application/json":
+		fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) (string, error) {\n\treturn c.doText(ctx, %q, %s, %s)\n}\n\n",
+			name, strings.Join(args, ", "), op.Method, path, query)
+	default:
+		typ, err := g.goType(media.Schema, name+"Response")
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || strings.HasPrefix(typ, "*") {
+			fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) (%s, error) {\n\tvar out %s\n\terr := c.do(ctx, %q, %s, %s, %s, &out)\n\treturn out, err\n}\n\n",
+				name, strings.Join(args, ", "), typ, typ, op.Method, path, query, bodyExpr)
+		} else {
+			fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) (*%s, error) {\n\tvar out %s\n\tif err := c.do(ctx, %q, %s, %s, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n",
+				name, strings.Join(args, ", "), typ, typ, op.Method, path, query, bodyExpr)
+		}
+	}
+	return nil
+}
+
+// 查询参数生成为结构体，零值字段不发送
+func (g *generator) emitParams(op *openapi.Operation, name string) (string, error) {
+	var body bytes.Buffer
+	count := 0
+	for _, param := range op.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		schema, err := g.spec.ResolveSchema(param.Schema)
+		if err != nil {
+			return "", err
+		}
+		typ, err := g.goType(schema, name+goName(param.Name))
+		if err != nil {
+			return "", err
+		}
+		if param.Description != "" {
+			fmt.Fprintf(&body, "\t%s\n", comment(param.Description))
+		}
+		fmt.Fprintf(&body, "\t%s %s `query:%q`\n", goName(param.Name), strings.TrimPrefix(typ, "*"), param.Name)
+		count++
+	}
+	if count == 0 {
+		return "", nil
+	}
+	fmt.Fprintf(&g.types, "// %s 是 %s 的查询参数\ntype %s struct {\n%s}\n\n", name, goName(op.OperationID), name, body.String())
+	return name, nil
+}
+
+// 状态码最小的 2xx 响应
+func successResponse(op *openapi.Operation) *openapi.Response {
+	statuses := make([]string, 0, len(op.Responses))
+	for status := range op.Responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+	sort.Strings(statuses)
+	return op.Responses[statuses[0]]
+}
+
+func responseMedia(response *openapi.Response) (string, *openapi.MediaType) {
+	for contentType, media := range response.Content {
+		media := media
+		return contentType, &media
+	}
+	return "", nil
+}
+
+func findParam(op *openapi.Operation, in, name string) *openapi.Parameter {
+	for _, param := range op.Parameters {
+		if param.In == in && param.Name == name {
+			return param
+		}
+	}
+	return nil
+}
+
+// goName 将 JSON 字段名转换为导出的 Go 标识符，已是大写开头的字段保持不变
+func goName(name string) string {
+	if name == "" {
+		return name
+	}
+	if unicode.IsUpper(rune(name[0])) {
+		return name
+	}
+
+	var words []string
+	start := 0
+	for i := 1; i <= len(name); i++ {
+		if i == len(name) || name[i] == '_' || unicode.IsUpper(rune(name[i])) {
+			if word := strings.Trim(name[start:i], "_"); word != "" {
+				words = append(words, word)
+			}
+			start = i
+		}
+	}
+
+	var b strings.Builder
+	for _, word := range words {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = "// " + line
+	}
+	return strings.Join(lines, "\n")
+}
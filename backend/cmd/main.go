@@ -2,12 +2,15 @@ package main
 
 import (
 	"backend/api/controller"
+	"backend/api/graphql"
+	"backend/api/openapi"
 	"backend/api/route"
 	"backend/repository"
 	"backend/usecase"
 	"encoding/json"
 	"io/ioutil"
 	"log"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/mysql"
@@ -35,30 +38,133 @@ func main() {
 	}
 	contractAddress := addressData.Address
 
+	// 读取价格源配置，文件不存在时不提供美元价格
+	var priceConfig usecase.PriceOracleConfig
+	priceJSON, err := ioutil.ReadFile("config/price-feeds.json")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Fatalf("无法读取价格源配置文件: %v", err)
+		}
+		log.Printf("未找到价格源配置文件，美元价格不可用")
+	} else if err := json.Unmarshal(priceJSON, &priceConfig); err != nil {
+		log.Fatalf("无法解析价格源配置JSON: %v", err)
+	}
+
+	// 读取登录配置，文件不存在时使用默认配置（只允许本地前端 localhost:8080 登录）
+	authConfig := usecase.AuthConfig{Domains: []string{"localhost:8080"}}
+	authJSON, err := ioutil.ReadFile("config/auth.json")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Fatalf("无法读取登录配置文件: %v", err)
+		}
+		log.Printf("未找到登录配置文件，使用默认配置")
+	} else if err := json.Unmarshal(authJSON, &authConfig); err != nil {
+		log.Fatalf("无法解析登录配置JSON: %v", err)
+	}
+
+	// 读取提醒配置，文件不存在时不支持邮件提醒
+	var alertConfig usecase.AlertConfig
+	alertJSON, err := ioutil.ReadFile("config/alerts.json")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Fatalf("无法读取提醒配置文件: %v", err)
+		}
+		log.Printf("未找到提醒配置文件，邮件提醒不可用")
+	} else if err := json.Unmarshal(alertJSON, &alertConfig); err != nil {
+		log.Fatalf("无法解析提醒配置JSON: %v", err)
+	}
+
 	ethClientURL := "wss://polygon-amoy.g.alchemy.com/v2/oUhC0fClZFJKJ09zzWsqj65EFq3X01y0" // 替换为您的以太坊节点URL
 
 	// 初始化仓储层
 	nftRepo := repository.NewNFTRepository(db)
 	marketRepo := repository.NewMarketRepository(db)
+	retryRepo := repository.NewRetryRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	historyRepo := repository.NewPriceHistoryRepository(db)
+	offerRepo := repository.NewOfferRepository(db)
+	listingRepo := repository.NewListingRepository(db)
+	auctionRepo := repository.NewAuctionRepository(db)
+	authRepo := repository.NewAuthRepository(db)
+	profileRepo := repository.NewProfileRepository(db)
+	watchlistRepo := repository.NewWatchlistRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	alertRepo := repository.NewAlertRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
 
 	// 初始化用例层
-	nftUC := usecase.NewNFTUseCase(nftRepo, ethClientURL)
+	searchUC := usecase.NewSearchUseCase(nftRepo, marketRepo)
+	priceOracle := usecase.NewPriceOracleFromConfig(priceConfig, ethClientURL)
+	tokenUC := usecase.NewTokenUseCase(tokenRepo, priceOracle, ethClientURL)
+	nftUC := usecase.NewNFTUseCase(nftRepo, retryRepo, searchUC, ethClientURL)
 	defer nftUC.Close() // 确保在程序退出时关闭 NFTUseCase
-	marketUC, err := usecase.NewMarketUseCase(marketRepo, nftRepo, nftUC, ethClientURL, contractAddress)
+	marketUC, err := usecase.NewMarketUseCase(marketRepo, nftRepo, historyRepo, nftUC, searchUC, tokenUC, ethClientURL, contractAddress)
 	if err != nil {
 		log.Fatalf("初始化MarketUseCase失败: %v", err)
 	}
 	defer marketUC.Close() // 确保在程序退出时关闭 MarketUseCase
+	offerUC := usecase.NewOfferUseCase(offerRepo, marketUC, tokenUC)
+	defer offerUC.Close()
+	listingUC := usecase.NewListingUseCase(listingRepo, marketUC, tokenUC)
+	defer listingUC.Close()
+	auctionUC := usecase.NewAuctionUseCase(auctionRepo, offerRepo, offerUC, marketUC, tokenUC)
+	defer auctionUC.Close()
+	authUC, err := usecase.NewAuthUseCase(authRepo, marketUC, authConfig, ethClientURL)
+	if err != nil {
+		log.Fatalf("初始化AuthUseCase失败: %v", err)
+	}
+	defer authUC.Close()
+	profileUC := usecase.NewProfileUseCase(profileRepo, nftRepo, tokenUC, nftUC, marketUC)
+	watchlistUC := usecase.NewWatchlistUseCase(watchlistRepo, nftRepo, tokenUC, profileUC, marketUC)
+	webhookUC := usecase.NewWebhookUseCase(webhookRepo, nftUC, marketUC)
+	defer webhookUC.Close()
+	alertUC := usecase.NewAlertUseCase(alertRepo, nftRepo, tokenUC, webhookUC, marketUC, alertConfig)
+	notificationUC := usecase.NewNotificationUseCase(notificationRepo, nftRepo, tokenUC, profileUC, nftUC, marketUC)
+	retryUC := usecase.NewRetryUseCase(retryRepo, nftUC)
+	defer retryUC.Close()
+
+	// 所有用例的相互引用设置完成后再加载数据、启动后台协程
+	if err := marketUC.Start(); err != nil {
+		log.Fatalf("启动MarketUseCase失败: %v", err)
+	}
+	offerUC.Start()
+	listingUC.Start()
+	auctionUC.Start()
+	webhookUC.Start()
+	retryUC.Start()
 
 	// 初始化控制器
-	nftController := controller.NewNFTController(nftUC)
+	nftController := controller.NewNFTController(nftUC, watchlistUC)
 	marketController := controller.NewMarketController(marketUC)
+	retryController := controller.NewRetryController(retryUC)
+	searchController := controller.NewSearchController(searchUC)
+	tokenController := controller.NewTokenController(tokenUC)
+	offerController := controller.NewOfferController(offerUC)
+	listingController := controller.NewListingController(listingUC)
+	auctionController := controller.NewAuctionController(auctionUC)
+	authController := controller.NewAuthController(authUC)
+	profileController := controller.NewProfileController(profileUC)
+	watchlistController := controller.NewWatchlistController(watchlistUC)
+	webhookController := controller.NewWebhookController(webhookUC)
+	alertController := controller.NewAlertController(alertUC)
+	notificationController := controller.NewNotificationController(notificationUC)
+	graphqlController := controller.NewGraphQLController(graphql.NewMarketSchema(nftUC, marketUC))
+
+	// 加载接口文档，用于请求校验和文档页面
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("加载接口文档失败: %v", err)
+	}
+	docsController, err := controller.NewDocsController(spec)
+	if err != nil {
+		log.Fatalf("初始化接口文档失败: %v", err)
+	}
 
 	// 初始化Gin路由
 	r := gin.Default()
 
 	// 设置路由
-	route.SetupRoutes(r, nftController, marketController)
+	route.SetupRoutes(r, nftController, marketController, retryController, searchController, tokenController, offerController, listingController, auctionController, authController, profileController, watchlistController, webhookController, alertController, notificationController, graphqlController, docsController, spec, authUC)
 
 	// 启动服务器
 	if err := r.Run("0.0.0.0:8081"); err != nil {
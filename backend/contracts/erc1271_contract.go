@@ -0,0 +1,58 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend/contracts/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// EIP-1271 isValidSignature 校验通过时返回的 magic value
+var erc1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+const erc1271ABIJSON = `[
+	{"type":"function","name":"isValidSignature","inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"outputs":[{"name":"magicValue","type":"bytes4"}],"stateMutability":"view"}
+]`
+
+// ERC1271Validator 通过 eth_call 校验合约钱包（如多签、智能账户）的签名
+type ERC1271Validator struct {
+	client *ethclient.Client
+	abi    abi.ABI
+}
+
+func NewERC1271Validator(ethClientURL string) (*ERC1271Validator, error) {
+	client, err := ethclient.Dial(ethClientURL)
+	if err != nil {
+		return nil, fmt.Errorf("连接以太坊客户端失败: %w", err)
+	}
+
+	erc1271ABI, err := abi.JSON(strings.NewReader(erc1271ABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("解析ERC1271 ABI失败: %w", err)
+	}
+
+	return &ERC1271Validator{client: client, abi: erc1271ABI}, nil
+}
+
+// 判断地址是否为合约账户
+func (v *ERC1271Validator) IsContract(account common.Address) (bool, error) {
+	code, err := v.client.CodeAt(context.Background(), account, nil)
+	if err != nil {
+		return false, fmt.Errorf("获取合约代码失败: %w", err)
+	}
+	return len(code) > 0, nil
+}
+
+// 调用 isValidSignature(hash, signature)，返回值为 magic value 时签名有效
+func (v *ERC1271Validator) IsValidSignature(account common.Address, hash common.Hash, signature []byte) (bool, error) {
+	result, err := utils.CallMethod(v.client, v.abi, account, "isValidSignature", hash, signature)
+	if err != nil {
+		return false, err
+	}
+	return result[0].([4]byte) == erc1271MagicValue, nil
+}
@@ -0,0 +1,153 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"backend/contracts/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC20 及 EIP-2612/EIP-5267 扩展中用到的方法
+const erc20ABIJSON = `[
+	{"type":"function","name":"name","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"symbol","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"allowance","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"nonces","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"version","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"DOMAIN_SEPARATOR","inputs":[],"outputs":[{"name":"","type":"bytes32"}],"stateMutability":"view"},
+	{"type":"function","name":"eip712Domain","inputs":[],"outputs":[{"name":"fields","type":"bytes1"},{"name":"name","type":"string"},{"name":"version","type":"string"},{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"},{"name":"salt","type":"bytes32"},{"name":"extensions","type":"uint256[]"}],"stateMutability":"view"}
+]`
+
+type ERC20Contract struct {
+	client  *ethclient.Client
+	address common.Address
+	abi     abi.ABI
+}
+
+// EIP712Domain 表示合约通过 eip712Domain() 返回的签名域
+type EIP712Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract common.Address
+}
+
+func NewERC20Contract(ethClientURL, contractAddress string) (*ERC20Contract, error) {
+	client, err := ethclient.Dial(ethClientURL)
+	if err != nil {
+		return nil, fmt.Errorf("连接以太坊客户端失败: %w", err)
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20ABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("解析ERC20 ABI失败: %w", err)
+	}
+
+	return &ERC20Contract{
+		client:  client,
+		address: common.HexToAddress(contractAddress),
+		abi:     erc20ABI,
+	}, nil
+}
+
+func (c *ERC20Contract) callMethod(method string, args ...interface{}) ([]interface{}, error) {
+	return utils.CallMethod(c.client, c.abi, c.address, method, args...)
+}
+
+func (c *ERC20Contract) Address() common.Address {
+	return c.address
+}
+
+// Close 关闭合约实例持有的以太坊客户端连接
+func (c *ERC20Contract) Close() {
+	c.client.Close()
+}
+
+func (c *ERC20Contract) Name() (string, error) {
+	result, err := c.callMethod("name")
+	if err != nil {
+		return "", err
+	}
+	return result[0].(string), nil
+}
+
+func (c *ERC20Contract) Symbol() (string, error) {
+	result, err := c.callMethod("symbol")
+	if err != nil {
+		return "", err
+	}
+	return result[0].(string), nil
+}
+
+func (c *ERC20Contract) Decimals() (uint8, error) {
+	result, err := c.callMethod("decimals")
+	if err != nil {
+		return 0, err
+	}
+	return result[0].(uint8), nil
+}
+
+func (c *ERC20Contract) BalanceOf(account common.Address) (*big.Int, error) {
+	result, err := c.callMethod("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+	return result[0].(*big.Int), nil
+}
+
+func (c *ERC20Contract) Allowance(owner, spender common.Address) (*big.Int, error) {
+	result, err := c.callMethod("allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	return result[0].(*big.Int), nil
+}
+
+func (c *ERC20Contract) Nonces(owner common.Address) (*big.Int, error) {
+	result, err := c.callMethod("nonces", owner)
+	if err != nil {
+		return nil, err
+	}
+	return result[0].(*big.Int), nil
+}
+
+func (c *ERC20Contract) Version() (string, error) {
+	result, err := c.callMethod("version")
+	if err != nil {
+		return "", err
+	}
+	return result[0].(string), nil
+}
+
+func (c *ERC20Contract) DomainSeparator() (common.Hash, error) {
+	result, err := c.callMethod("DOMAIN_SEPARATOR")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(result[0].([32]byte)), nil
+}
+
+func (c *ERC20Contract) EIP712Domain() (*EIP712Domain, error) {
+	result, err := c.callMethod("eip712Domain")
+	if err != nil {
+		return nil, err
+	}
+	return &EIP712Domain{
+		Name:              result[1].(string),
+		Version:           result[2].(string),
+		ChainID:           result[3].(*big.Int),
+		VerifyingContract: result[4].(common.Address),
+	}, nil
+}
+
+func (c *ERC20Contract) ChainID() (*big.Int, error) {
+	return c.client.ChainID(context.Background())
+}
@@ -75,6 +75,11 @@ func (c *NFTContract) callMethod(method string, args ...interface{}) ([]interfac
 	return utils.CallMethod(c.client, c.abi, c.address, method, args...)
 }
 
+// Close 关闭合约实例持有的以太坊客户端连接
+func (c *NFTContract) Close() {
+	c.client.Close()
+}
+
 func (c *NFTContract) Name() (string, error) {
 	result, err := c.callMethod("name")
 	if err != nil {
@@ -123,6 +128,27 @@ func (c *NFTContract) OwnerOf(tokenID uint) (string, error) {
 	return result[0].(common.Address).Hex(), nil
 }
 
+func (c *NFTContract) GetApproved(tokenID uint) (common.Address, error) {
+	result, err := c.callMethod("getApproved", big.NewInt(int64(tokenID)))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return result[0].(common.Address), nil
+}
+
+func (c *NFTContract) IsApprovedForAll(owner, operator common.Address) (bool, error) {
+	result, err := c.callMethod("isApprovedForAll", owner, operator)
+	if err != nil {
+		return false, err
+	}
+	return result[0].(bool), nil
+}
+
+// 编码合约调用数据，用于返回给前端发起交易
+func (c *NFTContract) PackCall(method string, args ...interface{}) ([]byte, error) {
+	return c.abi.Pack(method, args...)
+}
+
 func (c *NFTContract) GetNFTMetadata(tokenURI string) (*NFTMetadata, error) {
 	httpURI := utils.ConvertIPFSToHTTP(tokenURI)
 	resp, err := http.Get(httpURI)
@@ -197,29 +223,7 @@ func convertLogsToPointers(logs []types.Log) []*types.Log {
 }
 
 func (c *NFTContract) GetCreationBlockNumber() (uint64, error) {
-	// 这里我们使用一个较低的区块号作为起始点
-	// 实际应用中,你可能需要根据具体情况调整这个值
-	startBlock := uint64(12489691)
-	currentBlock, err := c.client.BlockNumber(context.Background())
-	if err != nil {
-		return 0, fmt.Errorf("获取当前区块号失败: %w", err)
-	}
-
-	for startBlock < currentBlock {
-		midBlock := (startBlock + currentBlock) / 2
-		code, err := c.client.CodeAt(context.Background(), c.address, big.NewInt(int64(midBlock)))
-		if err != nil {
-			return 0, fmt.Errorf("获取合约代码失败: %w", err)
-		}
-
-		if len(code) > 0 {
-			currentBlock = midBlock
-		} else {
-			startBlock = midBlock + 1
-		}
-	}
-
-	return startBlock, nil
+	return utils.GetCreationBlockNumber(c.client, c.address)
 }
 
 func (c *NFTContract) GetLatestBlockNumber() (uint64, error) {
@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"backend/contracts/utils"
 	"backend/domain"
 	"context"
 	"encoding/json"
@@ -8,6 +9,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -17,9 +19,11 @@ import (
 )
 
 type NFTMarketContract struct {
-	client  *ethclient.Client
-	address common.Address
-	abi     abi.ABI
+	client        *ethclient.Client
+	address       common.Address
+	abi           abi.ABI
+	creationBlock uint64 // 部署区块，首次查询后缓存
+	mutex         sync.Mutex
 }
 
 func NewNFTMarketContract(ethClientURL, contractAddress string) (*NFTMarketContract, error) {
@@ -52,6 +56,10 @@ func NewNFTMarketContract(ethClientURL, contractAddress string) (*NFTMarketContr
 	}, nil
 }
 
+func (c *NFTMarketContract) Address() common.Address {
+	return c.address
+}
+
 func (c *NFTMarketContract) GetOrders() ([]domain.Order, error) {
 	data, err := c.abi.Pack("getOrders")
 	if err != nil {
@@ -96,6 +104,108 @@ func (c *NFTMarketContract) GetOrders() ([]domain.Order, error) {
 	return domainOrders, nil
 }
 
+// 编码合约调用数据，用于返回给前端发起交易
+func (c *NFTMarketContract) PackCall(method string, args ...interface{}) ([]byte, error) {
+	return c.abi.Pack(method, args...)
+}
+
+func (c *NFTMarketContract) buyNFTCallMsg(from common.Address, index, deadline *big.Int, v uint8, r, s [32]byte) (ethereum.CallMsg, error) {
+	data, err := c.abi.Pack("buyNFT", index, deadline, v, r, s)
+	if err != nil {
+		return ethereum.CallMsg{}, fmt.Errorf("打包buyNFT函数调用失败: %w", err)
+	}
+	return ethereum.CallMsg{
+		From: from,
+		To:   &c.address,
+		Data: data,
+	}, nil
+}
+
+// 以买家身份在最新区块上模拟调用 buyNFT，返回的错误包含 revert 原因
+func (c *NFTMarketContract) CallBuyNFT(from common.Address, index, deadline *big.Int, v uint8, r, s [32]byte) error {
+	msg, err := c.buyNFTCallMsg(from, index, deadline, v, r, s)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.CallContract(context.Background(), msg, nil)
+	return err
+}
+
+func (c *NFTMarketContract) EstimateBuyNFTGas(from common.Address, index, deadline *big.Int, v uint8, r, s [32]byte) (uint64, error) {
+	msg, err := c.buyNFTCallMsg(from, index, deadline, v, r, s)
+	if err != nil {
+		return 0, err
+	}
+	return c.client.EstimateGas(context.Background(), msg)
+}
+
+func (c *NFTMarketContract) GetCreationBlockNumber() (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.creationBlock == 0 {
+		block, err := utils.GetCreationBlockNumber(c.client, c.address)
+		if err != nil {
+			return 0, err
+		}
+		c.creationBlock = block
+	}
+	return c.creationBlock, nil
+}
+
+func (c *NFTMarketContract) ChainID() (*big.Int, error) {
+	return c.client.ChainID(context.Background())
+}
+
+func (c *NFTMarketContract) GetLatestBlockNumber() (uint64, error) {
+	return c.client.BlockNumber(context.Background())
+}
+
+func (c *NFTMarketContract) GetBlockTimestamp(blockNumber uint64) (uint64, error) {
+	header, err := c.client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return 0, fmt.Errorf("获取区块信息失败: %w", err)
+	}
+	return header.Time, nil
+}
+
+// 获取区块范围内的 OrderFulfilled 事件
+func (c *NFTMarketContract) GetOrderFulfilledEvents(fromBlock, toBlock *big.Int) ([]types.Log, error) {
+	query := ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{c.address},
+		Topics: [][]common.Hash{{
+			c.abi.Events["OrderFulfilled"].ID,
+		}},
+	}
+
+	logs, err := c.client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("获取OrderFulfilled事件失败: %w", err)
+	}
+	return logs, nil
+}
+
+// 获取区块范围内的 OrderCreated 和 OrderCancelled 事件
+func (c *NFTMarketContract) GetOrderLifecycleEvents(fromBlock, toBlock *big.Int) ([]types.Log, error) {
+	query := ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{c.address},
+		Topics: [][]common.Hash{{
+			c.abi.Events["OrderCreated"].ID,
+			c.abi.Events["OrderCancelled"].ID,
+		}},
+	}
+
+	logs, err := c.client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("获取OrderCreated/OrderCancelled事件失败: %w", err)
+	}
+	return logs, nil
+}
+
 func (c *NFTMarketContract) WatchEvents(ctx context.Context, eventChan chan<- *types.Log) error {
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{c.address},
@@ -0,0 +1,84 @@
+package contracts
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"backend/contracts/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Chainlink AggregatorV3Interface 中用到的方法
+const aggregatorABIJSON = `[
+	{"type":"function","name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"},
+	{"type":"function","name":"description","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"latestRoundData","inputs":[],"outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}],"stateMutability":"view"}
+]`
+
+type PriceFeedContract struct {
+	client  *ethclient.Client
+	address common.Address
+	abi     abi.ABI
+}
+
+// RoundData 表示聚合器最新一轮的报价
+type RoundData struct {
+	RoundID   *big.Int
+	Answer    *big.Int
+	UpdatedAt time.Time
+}
+
+func NewPriceFeedContract(ethClientURL, contractAddress string) (*PriceFeedContract, error) {
+	client, err := ethclient.Dial(ethClientURL)
+	if err != nil {
+		return nil, fmt.Errorf("连接以太坊客户端失败: %w", err)
+	}
+
+	aggregatorABI, err := abi.JSON(strings.NewReader(aggregatorABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("解析Aggregator ABI失败: %w", err)
+	}
+
+	return &PriceFeedContract{
+		client:  client,
+		address: common.HexToAddress(contractAddress),
+		abi:     aggregatorABI,
+	}, nil
+}
+
+func (c *PriceFeedContract) callMethod(method string, args ...interface{}) ([]interface{}, error) {
+	return utils.CallMethod(c.client, c.abi, c.address, method, args...)
+}
+
+func (c *PriceFeedContract) Decimals() (uint8, error) {
+	result, err := c.callMethod("decimals")
+	if err != nil {
+		return 0, err
+	}
+	return result[0].(uint8), nil
+}
+
+func (c *PriceFeedContract) Description() (string, error) {
+	result, err := c.callMethod("description")
+	if err != nil {
+		return "", err
+	}
+	return result[0].(string), nil
+}
+
+func (c *PriceFeedContract) LatestRoundData() (*RoundData, error) {
+	result, err := c.callMethod("latestRoundData")
+	if err != nil {
+		return nil, err
+	}
+	return &RoundData{
+		RoundID:   result[0].(*big.Int),
+		Answer:    result[1].(*big.Int),
+		UpdatedAt: time.Unix(result[3].(*big.Int).Int64(), 0),
+	}, nil
+}
@@ -2,13 +2,24 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// 合约调用失败的两类原因：合约拒绝调用（revert 或返回值无法解析，如地址不是对应类型的合约），
+// 以及链上节点或网络出错。前者说明请求本身无效，后者应稍后重试
+var (
+	ErrCallRejected    = errors.New("合约拒绝调用")
+	ErrNodeUnavailable = errors.New("链上节点请求失败")
 )
 
 func CallMethod(client *ethclient.Client, contractABI abi.ABI, contractAddress common.Address, method string, args ...interface{}) ([]interface{}, error) {
@@ -29,10 +40,69 @@ func CallMethod(client *ethclient.Client, contractABI abi.ABI, contractAddress c
 
 	result, err := client.CallContract(context.Background(), msg, nil)
 	if err != nil {
-		return nil, fmt.Errorf("调用方法 %s 失败: %w", method, err)
+		if isExecutionError(err) {
+			return nil, fmt.Errorf("调用方法 %s 失败: %w: %w", method, ErrCallRejected, err)
+		}
+		return nil, fmt.Errorf("调用方法 %s 失败: %w: %w", method, ErrNodeUnavailable, err)
+	}
+
+	outputs, err := m.Outputs.Unpack(result)
+	if err != nil {
+		return nil, fmt.Errorf("解析方法 %s 返回值失败: %w: %w", method, ErrCallRejected, err)
+	}
+	return outputs, nil
+}
+
+// 判断 eth_call 的错误是否为合约执行失败，节点返回的其他错误（限流、超时、状态缺失等）不算
+func isExecutionError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "execution reverted") || strings.Contains(message, "invalid opcode")
+}
+
+// 二分查找合约的部署区块
+func GetCreationBlockNumber(client *ethclient.Client, contractAddress common.Address) (uint64, error) {
+	// 这里我们使用一个较低的区块号作为起始点
+	// 实际应用中,你可能需要根据具体情况调整这个值
+	startBlock := uint64(12489691)
+	currentBlock, err := client.BlockNumber(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("获取当前区块号失败: %w", err)
 	}
 
-	return m.Outputs.Unpack(result)
+	for startBlock < currentBlock {
+		midBlock := (startBlock + currentBlock) / 2
+		code, err := client.CodeAt(context.Background(), contractAddress, new(big.Int).SetUint64(midBlock))
+		if err != nil {
+			return 0, fmt.Errorf("获取合约代码失败: %w", err)
+		}
+
+		if len(code) > 0 {
+			currentBlock = midBlock
+		} else {
+			startBlock = midBlock + 1
+		}
+	}
+
+	return startBlock, nil
+}
+
+// 从 eth_call/eth_estimateGas 的错误中解析 revert 原因
+func RevertReason(err error) string {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(raw); unpackErr == nil {
+					return reason
+				}
+			}
+		}
+	}
+	return err.Error()
 }
 
 func ConvertIPFSToHTTP(uri string) string {
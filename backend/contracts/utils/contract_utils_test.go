@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testRPCError struct {
+	code    int
+	message string
+}
+
+func (e *testRPCError) Error() string  { return e.message }
+func (e *testRPCError) ErrorCode() int { return e.code }
+
+func TestIsExecutionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"revert 错误码", &testRPCError{code: 3, message: "execution reverted: ERC721: invalid token ID"}, true},
+		{"其他错误码的 revert 消息", &testRPCError{code: -32000, message: "execution reverted"}, true},
+		{"无效操作码", &testRPCError{code: -32000, message: "invalid opcode: INVALID"}, true},
+		{"包装后的 revert", fmt.Errorf("调用失败: %w", &testRPCError{code: 3, message: "execution reverted"}), true},
+		{"节点状态缺失", &testRPCError{code: -32000, message: "missing trie node"}, false},
+		{"限流", &testRPCError{code: -32005, message: "limit exceeded"}, false},
+		{"网络错误", errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"), false},
+		{"超时", context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExecutionError(tt.err); got != tt.want {
+				t.Fatalf("isExecutionError(%v) = %v，应为 %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package domain
+
+// AuctionView 表示拍卖的当前状态，由后端计算当前价格和领先者
+type AuctionView struct {
+	Auction
+	PaymentToken          *PaymentToken
+	CurrentPrice          string // 英式拍卖为最高出价（无出价时为保留价），荷兰式拍卖为当前时刻的价格
+	CurrentPriceFormatted string
+	CurrentPriceUSD       *float64
+	MinNextBid            string      // 下一次出价的最低价格，拍卖结束后为空
+	Leader                *AuctionBid // 当前领先或最终成交的出价
+	BidCount              int
+	SecondsRemaining      int64
+	SellerProfile         *ProfileSummary
+}
@@ -0,0 +1,89 @@
+package domain
+
+// ErrorKind 表示错误的类别，接口层据此决定 HTTP 状态码
+type ErrorKind int
+
+const (
+	ErrKindInternal     ErrorKind = iota // 服务器内部错误
+	ErrKindInvalid                       // 请求参数错误
+	ErrKindUnauthorized                  // 未登录或登录无效
+	ErrKindForbidden                     // 无权访问
+	ErrKindNotFound                      // 资源不存在
+	ErrKindConflict                      // 与当前状态冲突
+	ErrKindUpstream                      // 依赖的链上节点或外部服务出错
+)
+
+// Error 是可以直接返回给客户端的错误，Code 为稳定的机器可读错误码，Message 为中文说明。
+// 用 fmt.Errorf("%w: 详情", ErrX) 补充详情后仍可用 errors.Is/errors.As 识别
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Field   string // 参数错误时对应的参数名，可为空
+	Err     error  // 底层错误，只用于日志和 errors.Is
+}
+
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// 错误码相同即视为同一错误，使派生出的错误仍能匹配原始的哨兵错误
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && other.Code == e.Code
+}
+
+// Wrap 返回附带底层错误的副本
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// WithMessage 返回使用另一条说明的副本
+func (e *Error) WithMessage(message string) *Error {
+	wrapped := *e
+	wrapped.Message = message
+	return &wrapped
+}
+
+// InvalidParam 返回指定参数无效的错误
+func InvalidParam(field, message string) *Error {
+	err := ErrInvalidRequest.WithMessage(message)
+	err.Field = field
+	return err
+}
+
+// 通用错误
+var (
+	ErrInternal         = NewError(ErrKindInternal, "internal_error", "服务器内部错误")
+	ErrInvalidRequest   = NewError(ErrKindInvalid, "invalid_request", "无效的请求参数")
+	ErrValidationFailed = NewError(ErrKindInvalid, "validation_failed", "请求参数校验失败")
+	ErrChainUnavailable = NewError(ErrKindUpstream, "chain_unavailable", "链上节点请求失败")
+)
+
+// 资源不存在
+var (
+	ErrCollectionNotFound = NewError(ErrKindNotFound, "collection_not_found", "NFT系列未找到")
+	ErrNFTNotFound        = NewError(ErrKindNotFound, "nft_not_found", "NFT未找到")
+	ErrOrderNotFound      = NewError(ErrKindNotFound, "order_not_found", "订单未找到")
+	ErrListingNotFound    = NewError(ErrKindNotFound, "listing_not_found", "挂单未找到")
+	ErrOfferNotFound      = NewError(ErrKindNotFound, "offer_not_found", "出价未找到")
+	ErrAuctionNotFound    = NewError(ErrKindNotFound, "auction_not_found", "拍卖未找到")
+	ErrTokenNotFound      = NewError(ErrKindNotFound, "token_not_found", "支付代币未找到")
+	ErrProfileNotFound    = NewError(ErrKindNotFound, "profile_not_found", "资料未找到")
+	ErrJobNotFound        = NewError(ErrKindNotFound, "job_not_found", "任务未找到")
+	ErrWebhookNotFound    = NewError(ErrKindNotFound, "webhook_not_found", "Webhook未找到")
+	ErrDeliveryNotFound   = NewError(ErrKindNotFound, "delivery_not_found", "投递记录未找到")
+	ErrAlertRuleNotFound  = NewError(ErrKindNotFound, "alert_rule_not_found", "提醒规则未找到")
+	ErrFavoriteNotFound   = NewError(ErrKindNotFound, "favorite_not_found", "未收藏该NFT")
+	ErrWatchNotFound      = NewError(ErrKindNotFound, "watch_not_found", "未关注该NFT系列")
+)
@@ -32,6 +32,17 @@ type NFTAttribute struct {
 	Value     string
 }
 
+// NFTRarity 表示NFT在其系列中的稀有度
+type NFTRarity struct {
+	ID                 uint    `gorm:"primaryKey;autoIncrement"`
+	NFTID              uint    `gorm:"uniqueIndex"`
+	CollectionID       uint    `gorm:"index:idx_collection_rank,priority:1"`
+	RarityScore        float64 // 各属性 1/频率 之和
+	StatisticalRarity  float64 // 各属性频率之积
+	InformationContent float64 // 各属性 -log2(频率) 之和
+	Rank               uint    `gorm:"index:idx_collection_rank,priority:2"` // 按 RarityScore 排名, 1 为最稀有
+}
+
 // Order 表示订单
 type Order struct {
 	ID                 uint   `gorm:"primaryKey;autoIncrement"`
@@ -41,6 +52,20 @@ type Order struct {
 	Price              string
 	Seller             string `gorm:"index"`
 	Status             uint   // 0: 未售出, 1: 已售出, 2: 已取消
+	Invalid            bool   // 卖家已不再持有或已取消授权，订单当前无法成交
+	InvalidReason      string // not_owner: 卖家不再持有NFT, not_approved: 市场合约未获授权
+}
+
+// PaymentToken 表示订单使用的ERC-20支付代币
+type PaymentToken struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	Address        string `gorm:"uniqueIndex"`
+	Name           string
+	Symbol         string
+	Decimals       uint8
+	SupportsPermit bool // 是否支持 EIP-2612 permit
+	ListStatus     uint // 0: 未设置, 1: 白名单, 2: 黑名单
+	UpdatedAt      time.Time
 }
 
 // NFTTransferEvent 表示NFT的转移事件(包括mint和transfer)
@@ -55,3 +80,322 @@ type NFTTransferEvent struct {
 	BlockNumber     uint `gorm:"index"`
 	BlockTimestamp  time.Time
 }
+
+// Offer 表示买家通过 EIP-712 签名提交的链下出价
+type Offer struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Hash               string `gorm:"uniqueIndex"` // EIP-712 签名数据哈希
+	Bidder             string `gorm:"index"`
+	NFTContractAddress string `gorm:"index:idx_offer_nft,priority:1"`
+	TokenID            uint   `gorm:"index:idx_offer_nft,priority:2"` // 系列出价时为 0
+	CollectionWide     bool   // 是否为对整个系列任意NFT的出价
+	TokenAddress       string
+	Price              string
+	Nonce              string
+	Expiry             time.Time
+	Signature          string
+	Status             uint   `gorm:"index"` // 0: 有效, 1: 已取消, 2: 已过期
+	Invalid            bool   // 买家余额或授权额度不足，出价当前无法成交
+	InvalidReason      string // insufficient_balance: 余额不足, insufficient_allowance: 授权额度不足
+	CreatedAt          time.Time
+}
+
+// Listing 表示卖家通过 EIP-712 签名提交的链下挂单，无需发起 createOrder 交易
+type Listing struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Hash               string `gorm:"uniqueIndex"` // EIP-712 签名数据哈希，结算时用于标识挂单
+	Seller             string `gorm:"index"`
+	NFTContractAddress string `gorm:"index:idx_listing_nft,priority:1"`
+	TokenID            uint   `gorm:"index:idx_listing_nft,priority:2"`
+	TokenAddress       string
+	Price              string
+	Nonce              uint64 // 卖家签名时的 nonce，小于卖家当前 nonce 的挂单视为已取消
+	Salt               string
+	Expiry             time.Time
+	Signature          string
+	Status             uint   `gorm:"index"` // 0: 有效, 1: 已成交, 2: 已取消, 3: 已过期
+	Invalid            bool   // 卖家已不再持有或已取消授权，挂单当前无法成交
+	InvalidReason      string // not_owner: 卖家不再持有NFT, not_approved: 市场合约未获授权
+	Buyer              string // 成交后的买家
+	FillTransaction    string // 成交交易哈希
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// SignerNonce 表示账户当前的签名 nonce，提高 nonce 会使之前签名的挂单全部失效
+type SignerNonce struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Address   string `gorm:"uniqueIndex"`
+	Nonce     uint64
+	UpdatedAt time.Time
+}
+
+// BidderNonce 表示买家当前的出价 nonce，提高 nonce 会使之前签名的出价全部失效
+type BidderNonce struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Address   string `gorm:"uniqueIndex"`
+	Nonce     uint64
+	UpdatedAt time.Time
+}
+
+// Auction 表示由后端管理的英式或荷兰式拍卖
+type Auction struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Hash               string `gorm:"uniqueIndex"` // 卖家签名的 EIP-712 数据哈希
+	Type               string `gorm:"type:enum('english','dutch')"`
+	Seller             string `gorm:"index"`
+	NFTContractAddress string `gorm:"index:idx_auction_nft,priority:1"`
+	TokenID            uint   `gorm:"index:idx_auction_nft,priority:2"`
+	TokenAddress       string
+	ReservePrice       string // 英式拍卖的保留价
+	MinIncrement       string // 英式拍卖每次加价的最小幅度
+	StartPrice         string // 荷兰式拍卖的起始价
+	EndPrice           string // 荷兰式拍卖的结束价
+	StartTime          time.Time
+	EndTime            time.Time `gorm:"index"`
+	ExtensionSeconds   uint      // 英式拍卖结束前该时长内有出价时，结束时间顺延该时长
+	Salt               string
+	Signature          string
+	Status             uint `gorm:"index"` // 0: 进行中, 1: 已成交, 2: 已取消, 3: 流拍
+	WinningBidID       uint
+	WinningOfferID     uint // 成交后导出为出价的ID，卖家可据此成交
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// AuctionBid 表示拍卖的出价，签名格式与链下出价 Offer 相同
+type AuctionBid struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	AuctionID uint   `gorm:"index"`
+	Hash      string `gorm:"uniqueIndex"`
+	Bidder    string `gorm:"index"`
+	Price     string
+	Nonce     string
+	Expiry    time.Time
+	Signature string
+	CreatedAt time.Time
+}
+
+// Sale 表示一笔成交的订单
+type Sale struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	OrderID            uint   `gorm:"uniqueIndex"`
+	NFTContractAddress string `gorm:"index:idx_sale_nft,priority:1"`
+	TokenID            uint   `gorm:"index:idx_sale_nft,priority:2"`
+	TokenAddress       string
+	Price              string
+	Seller             string `gorm:"index"`
+	Buyer              string `gorm:"index"`
+	TransactionHash    string
+	BlockNumber        uint
+	BlockTimestamp     time.Time `gorm:"index"`
+}
+
+// OrderEvent 表示链上订单的挂单或撤单事件，用于生成NFT溯源记录，价格和卖家从对应订单读取
+type OrderEvent struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	OrderID            uint   `gorm:"uniqueIndex:idx_order_event,priority:1"`
+	EventType          string `gorm:"uniqueIndex:idx_order_event,priority:2"` // listed 或 delisted
+	NFTContractAddress string `gorm:"index:idx_order_event_nft,priority:1"`
+	TokenID            uint   `gorm:"index:idx_order_event_nft,priority:2"`
+	TransactionHash    string
+	BlockNumber        uint
+	BlockTimestamp     time.Time
+}
+
+// PriceCandle 表示NFT系列在某一支付代币、某一时间段内的成交K线，价格均为代币最小单位的整数
+type PriceCandle struct {
+	ID                 uint      `gorm:"primaryKey;autoIncrement"`
+	NFTContractAddress string    `gorm:"uniqueIndex:idx_candle_bucket,priority:1"`
+	TokenAddress       string    `gorm:"uniqueIndex:idx_candle_bucket,priority:2"`
+	Interval           string    `gorm:"type:enum('1h','1d','1w');uniqueIndex:idx_candle_bucket,priority:3"`
+	BucketStart        time.Time `gorm:"uniqueIndex:idx_candle_bucket,priority:4"`
+	Open               string
+	High               string
+	Low                string
+	Close              string
+	Volume             string
+	Count              uint
+	Average            string
+	OpenAt             time.Time // 开盘成交时间
+	CloseAt            time.Time // 收盘成交时间
+}
+
+// RetryJob 表示初始化失败后等待重试的任务
+// 重试任务状态
+const (
+	RetryJobStatusPending   = 0
+	RetryJobStatusSucceeded = 1
+	RetryJobStatusDead      = 2
+	RetryJobStatusRunning   = 3 // 已被某个执行者认领，防止同一任务被重复执行
+)
+
+type RetryJob struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	JobType         string `gorm:"type:enum('nft','collection');uniqueIndex:idx_job_target,priority:1"`
+	ContractAddress string `gorm:"uniqueIndex:idx_job_target,priority:2"`
+	TokenID         uint   `gorm:"uniqueIndex:idx_job_target,priority:3"`
+	Status          uint   `gorm:"index:idx_status_next_retry,priority:1"` // 0: 待重试, 1: 已完成, 2: 死信, 3: 执行中
+	Attempts        uint
+	NextRetryAt     time.Time `gorm:"index:idx_status_next_retry,priority:2"`
+	LastError       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// AuthNonce 表示 SIWE 登录使用的一次性 nonce
+type AuthNonce struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Nonce     string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Session 表示登录会话，对应 JWT 的 jti，撤销后令牌立即失效
+type Session struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	TokenID   string `gorm:"uniqueIndex"`
+	Address   string `gorm:"index"`
+	ChainID   uint64
+	UserAgent string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Profile 表示钱包地址的链下资料
+type Profile struct {
+	ID               uint    `gorm:"primaryKey;autoIncrement"`
+	Address          string  `gorm:"uniqueIndex"`
+	Username         *string `gorm:"uniqueIndex"` // 未设置时为 NULL，不参与唯一性检查
+	DisplayName      string
+	Bio              string
+	AvatarNFTAddress string // 头像使用的NFT，必须由该地址持有
+	AvatarTokenID    uint
+	AvatarImage      string
+	Twitter          string
+	Discord          string
+	Website          string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Favorite 表示用户收藏的NFT
+type Favorite struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Address            string `gorm:"uniqueIndex:idx_favorite,priority:1"`
+	NFTContractAddress string `gorm:"uniqueIndex:idx_favorite,priority:2;index:idx_favorite_nft,priority:1"`
+	TokenID            uint   `gorm:"uniqueIndex:idx_favorite,priority:3;index:idx_favorite_nft,priority:2"`
+	CreatedAt          time.Time
+}
+
+// CollectionWatch 表示用户关注的NFT系列
+type CollectionWatch struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	Address         string `gorm:"uniqueIndex:idx_collection_watch,priority:1"`
+	ContractAddress string `gorm:"uniqueIndex:idx_collection_watch,priority:2;index"`
+	CreatedAt       time.Time
+}
+
+// MarketActivity 表示由市场事件生成的动态：新挂单、降价和成交
+type MarketActivity struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Type               string `gorm:"type:enum('listing','price_drop','sale');uniqueIndex:idx_activity_reference,priority:1"`
+	Reference          string `gorm:"uniqueIndex:idx_activity_reference,priority:2"` // 链上订单为 order:<订单ID>，链下挂单为 listing:<哈希>
+	NFTContractAddress string `gorm:"index:idx_activity_collection,priority:1;index:idx_activity_nft,priority:1"`
+	TokenID            uint   `gorm:"index:idx_activity_nft,priority:2"`
+	TokenAddress       string
+	Price              string
+	PreviousPrice      string // 降价前的挂单价格，仅 price_drop 有值
+	Seller             string
+	Buyer              string // 仅 sale 有值
+	TransactionHash    string
+	CreatedAt          time.Time `gorm:"index:idx_activity_collection,priority:2"`
+}
+
+// Webhook 表示用户注册的事件推送地址，过滤条件为空时不过滤
+type Webhook struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	Owner      string `gorm:"index"`
+	URL        string
+	Secret     string `json:"-"` // 用于 HMAC 签名，只在创建和轮换时返回
+	Events     string // 逗号分隔的事件类型
+	Collection string // NFT合约地址
+	Address    string // 匹配卖家、买家、转出方或转入方
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WebhookDelivery 表示一次事件推送及其投递记录
+type WebhookDelivery struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	WebhookID      uint   `gorm:"index"`
+	EventID        string `gorm:"index"`
+	EventType      string
+	Payload        string
+	Status         uint `gorm:"index:idx_delivery_status_next,priority:1"` // 0: 待投递, 1: 已送达, 2: 已放弃
+	Attempts       uint
+	NextAttemptAt  time.Time `gorm:"index:idx_delivery_status_next,priority:2"`
+	ResponseStatus int
+	LastError      string
+	ReplayOf       uint // 重放的原投递ID
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// AlertRule 表示用户设置的提醒规则，价格阈值使用 TokenAddress 对应代币的最小单位
+type AlertRule struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	Owner           string `gorm:"index"`
+	Kind            string `gorm:"index"` // floor_below, trait_listing, nft_sold, offer_received
+	Collection      string // NFT合约地址，nft_sold 和 offer_received 为空时不过滤
+	TraitType       string
+	TraitValue      string
+	TokenAddress    string
+	Price           string
+	Channels        string // 逗号分隔的 inbox, email, webhook
+	Email           string
+	Active          bool
+	LastTriggeredAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Alert 表示触发的提醒，同一规则下 DedupKey 相同的提醒只记录一次
+type Alert struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Owner              string `gorm:"index"`
+	RuleID             uint   `gorm:"uniqueIndex:idx_alert_rule_dedup,priority:1"`
+	DedupKey           string `gorm:"uniqueIndex:idx_alert_rule_dedup,priority:2"`
+	Kind               string
+	Title              string
+	Message            string
+	NFTContractAddress string
+	TokenID            uint
+	TokenAddress       string
+	Price              string
+	Reference          string // 触发提醒的挂单、成交或出价
+	Throttled          bool   // 超过频率限制，未通过邮件和 Webhook 发送
+	ReadAt             *time.Time
+	CreatedAt          time.Time
+}
+
+// Notification 表示地址收到的站内通知，同一地址同类通知的 Reference 相同时只记录一次
+type Notification struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Address            string `gorm:"uniqueIndex:idx_notification_ref,priority:1;index:idx_notification_address,priority:1"`
+	Type               string `gorm:"uniqueIndex:idx_notification_ref,priority:2"`
+	Reference          string `gorm:"uniqueIndex:idx_notification_ref,priority:3"` // 订单、挂单、转移交易或出价
+	Title              string
+	NFTContractAddress string
+	TokenID            uint
+	TokenAddress       string
+	Price              string
+	Counterparty       string // 买家、卖家、转出方或出价人
+	TransactionHash    string
+	ReadAt             *time.Time
+	CreatedAt          time.Time `gorm:"index:idx_notification_address,priority:2"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NFTKey 表示 (合约地址, tokenID) 组合，用于批量查询
+type NFTKey struct {
+	ContractAddress string
+	TokenID         uint
+}
+
+// String 返回 "<小写合约地址>:<tokenID>"，作为批量查询结果的键
+func (k NFTKey) String() string {
+	return fmt.Sprintf("%s:%d", strings.ToLower(k.ContractAddress), k.TokenID)
+}
+
+// NFTKeyTuples 将 keys 转为 (contract_address, token_id) IN ? 使用的参数
+func NFTKeyTuples(keys []NFTKey) [][]interface{} {
+	tuples := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		tuples[i] = []interface{}{key.ContractAddress, key.TokenID}
+	}
+	return tuples
+}
@@ -0,0 +1,90 @@
+package domain
+
+// 订单来源
+const (
+	OrderSourceOnChain = "onchain" // 通过 createOrder 创建的链上订单
+	OrderSourceSigned  = "signed"  // 链下签名挂单
+)
+
+// OrderView 表示附带支付代币信息和格式化价格的订单
+type OrderView struct {
+	Order
+	Source         string
+	Listing        *Listing      // 链下签名挂单的详细信息，链上订单为 nil
+	PaymentToken   *PaymentToken // 代币信息尚未获取时为 nil
+	PriceFormatted string        // 按代币精度格式化后的价格，如 "1.5"
+	PriceUSD       *float64      // 按当前报价换算的美元价格，没有可用报价时为 nil
+	SellerProfile  *ProfileSummary
+}
+
+// TokenFloor 表示使用某一支付代币挂单的最低价格
+type TokenFloor struct {
+	PaymentToken   *PaymentToken
+	TokenAddress   string
+	Price          string
+	PriceFormatted string
+	PriceUSD       *float64
+	ListedCount    int
+}
+
+// CollectionStats 表示NFT系列的挂单和成交统计，美元金额只统计有可用报价的代币
+type CollectionStats struct {
+	ContractAddress string
+	ListedCount     int
+	FloorPriceUSD   *float64     // 所有支付代币中换算为美元后的最低挂单价
+	Floors          []TokenFloor // 各支付代币的最低挂单价
+	SalesCount      int
+	VolumeUSD       float64
+	UnpricedSales   int // 没有可用报价、未计入 VolumeUSD 的成交数
+}
+
+// PriceCandleView 表示附带支付代币信息和格式化价格的K线
+type PriceCandleView struct {
+	PriceCandle
+	PaymentToken     *PaymentToken
+	OpenFormatted    string
+	HighFormatted    string
+	LowFormatted     string
+	CloseFormatted   string
+	VolumeFormatted  string
+	AverageFormatted string
+}
+
+// ListingView 表示附带支付代币信息和格式化价格的链下挂单
+type ListingView struct {
+	Listing
+	PaymentToken   *PaymentToken
+	PriceFormatted string
+	PriceUSD       *float64
+	SellerProfile  *ProfileSummary
+	BuyerProfile   *ProfileSummary
+}
+
+// OfferView 表示附带支付代币信息和格式化价格的出价
+type OfferView struct {
+	Offer
+	PaymentToken   *PaymentToken
+	PriceFormatted string
+	PriceUSD       *float64
+}
+
+// MarketActivityView 表示附带NFT、支付代币信息和格式化价格的市场动态
+type MarketActivityView struct {
+	MarketActivity
+	NFT                    *NFT
+	PaymentToken           *PaymentToken
+	PriceFormatted         string
+	PreviousPriceFormatted string
+	PriceUSD               *float64
+	SellerProfile          *ProfileSummary
+	BuyerProfile           *ProfileSummary
+}
+
+// NotificationView 表示附带NFT、支付代币、格式化价格和对方资料的通知
+type NotificationView struct {
+	Notification
+	NFT                 *NFT
+	PaymentToken        *PaymentToken
+	PriceFormatted      string
+	CounterpartyProfile *ProfileSummary
+}
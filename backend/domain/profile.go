@@ -0,0 +1,22 @@
+package domain
+
+// ProfileSummary 表示随地址一起返回的资料摘要
+type ProfileSummary struct {
+	Address     string
+	Username    string
+	DisplayName string
+	AvatarImage string
+}
+
+// NFTView 表示附带持有者资料的NFT
+type NFTView struct {
+	NFT
+	OwnerProfile *ProfileSummary // 持有者未设置资料时为 nil
+}
+
+// NFTTransferEventView 表示附带转出方和转入方资料的转移事件
+type NFTTransferEventView struct {
+	NFTTransferEvent
+	FromProfile *ProfileSummary
+	ToProfile   *ProfileSummary
+}
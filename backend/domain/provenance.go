@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// 溯源事件类型
+const (
+	ProvenanceMint     = "mint"
+	ProvenanceTransfer = "transfer" // 未通过市场成交的私下转移
+	ProvenanceSale     = "sale"
+	ProvenanceListed   = "listed"
+	ProvenanceDelisted = "delisted"
+)
+
+// ProvenanceEvent 表示NFT溯源中的一条记录，价格相关字段只在挂单和成交时有值
+type ProvenanceEvent struct {
+	Type            string
+	From            string
+	To              string
+	OrderIndex      *uint // 链上订单索引
+	TokenAddress    string
+	PaymentToken    *PaymentToken
+	Price           string
+	PriceFormatted  string
+	TransactionHash string
+	BlockNumber     uint
+	Timestamp       time.Time
+	FromProfile     *ProfileSummary
+	ToProfile       *ProfileSummary
+}
+
+// Holding 表示一位持有者的一段持有期
+type Holding struct {
+	Owner            string
+	AcquiredVia      string // mint, sale 或 transfer
+	AcquiredAt       time.Time
+	AcquisitionPrice string // 通过市场买入时的价格
+	ReleasedVia      string // sale 或 transfer，仍持有时为空
+	ReleasedAt       *time.Time
+	DisposalPrice    string // 通过市场卖出时的价格
+	TokenAddress     string // 买入和卖出价格使用的支付代币
+	HoldingSeconds   int64  // 仍持有时计算到当前时间
+	// 买入和卖出都通过市场且使用同一支付代币时才有值
+	RealizedGain          string
+	RealizedGainFormatted string
+	OwnerProfile          *ProfileSummary
+}
+
+// Provenance 表示NFT的价格和所有权溯源
+type Provenance struct {
+	ContractAddress string
+	TokenID         uint
+	Events          []ProvenanceEvent
+	Holdings        []Holding
+}
@@ -0,0 +1,22 @@
+package domain
+
+// 搜索结果类型
+const (
+	SearchTypeCollection  = "collection"
+	SearchTypeNFT         = "nft"
+	SearchTypeAddress     = "address"
+	SearchTypeTransaction = "transaction"
+)
+
+// SearchResult 表示一条搜索结果
+type SearchResult struct {
+	Type            string
+	Title           string
+	Subtitle        string
+	Image           string
+	ContractAddress string
+	TokenID         uint
+	Address         string
+	TransactionHash string
+	Score           float64
+}
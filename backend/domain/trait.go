@@ -0,0 +1,52 @@
+package domain
+
+// NFT系列列表的排序方式
+const (
+	NFTSortTokenID    = "tokenId"
+	NFTSortRarity     = "rarity"      // 最稀有的在前
+	NFTSortRarityDesc = "rarity_desc" // 最常见的在前
+)
+
+// TraitCondition 表示对某一属性类型的取值筛选，同一属性类型的多个取值之间为"或"关系
+type TraitCondition struct {
+	TraitType string
+	Values    []string
+}
+
+// TraitRange 表示对数值型属性的区间筛选，Min/Max 为 nil 时表示不限
+type TraitRange struct {
+	TraitType string
+	Min       *float64
+	Max       *float64
+}
+
+// TraitFilter 表示NFT系列的属性筛选条件
+type TraitFilter struct {
+	Traits   []TraitCondition
+	Ranges   []TraitRange
+	MatchAny bool // false: 各条件同时满足, true: 满足任一条件
+}
+
+// IsEmpty 判断是否没有任何筛选条件
+func (f *TraitFilter) IsEmpty() bool {
+	return f == nil || (len(f.Traits) == 0 && len(f.Ranges) == 0)
+}
+
+// TraitValueCount 表示某个属性取值的统计
+type TraitValueCount struct {
+	TraitType   string
+	Value       string
+	Count       uint
+	ListedCount uint
+}
+
+// TraitFacet 表示某一属性类型下所有取值的统计，用于构建筛选侧栏
+type TraitFacet struct {
+	TraitType   string
+	Count       uint
+	ListedCount uint
+	Numeric     bool
+	Min         *float64
+	Max         *float64
+	Values      []TraitValueCount
+}
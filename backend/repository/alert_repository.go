@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"backend/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+func (r *AlertRepository) CreateRule(rule *domain.AlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *AlertRepository) SaveRule(rule *domain.AlertRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *AlertRepository) GetRuleByID(id uint) (*domain.AlertRule, error) {
+	var rule domain.AlertRule
+	err := r.db.First(&rule, id).Error
+	return &rule, err
+}
+
+func (r *AlertRepository) GetRulesByOwner(owner string) ([]domain.AlertRule, error) {
+	var rules []domain.AlertRule
+	err := r.db.Where("owner = ?", owner).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// 获取某类有效规则，collection 非空时只返回该系列和不限系列的规则
+func (r *AlertRepository) GetActiveRules(kind, collection string) ([]domain.AlertRule, error) {
+	var rules []domain.AlertRule
+	query := r.db.Where("kind = ? AND active = ?", kind, true)
+	if collection != "" {
+		query = query.Where("collection IN ?", []string{collection, ""})
+	}
+	err := query.Find(&rules).Error
+	return rules, err
+}
+
+func (r *AlertRepository) DeleteRule(id uint) error {
+	return r.db.Delete(&domain.AlertRule{}, id).Error
+}
+
+func (r *AlertRepository) TouchRule(id uint, triggeredAt time.Time) error {
+	return r.db.Model(&domain.AlertRule{}).Where("id = ?", id).Update("last_triggered_at", triggeredAt).Error
+}
+
+// 保存提醒，同一规则下重复的 DedupKey 忽略，返回是否新增
+func (r *AlertRepository) CreateAlert(alert *domain.Alert) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+// 统计用户在 since 之后未被限流的提醒数
+func (r *AlertRepository) CountSentAlerts(owner string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Alert{}).
+		Where("owner = ? AND created_at >= ? AND throttled = ?", owner, since, false).
+		Count(&count).Error
+	return count, err
+}
+
+// 按ID倒序分页获取提醒，beforeID 为 0 时从最新开始
+func (r *AlertRepository) GetAlerts(owner string, unreadOnly bool, beforeID uint, limit int) ([]domain.Alert, error) {
+	var alerts []domain.Alert
+	query := r.db.Where("owner = ?", owner)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *AlertRepository) CountUnread(owner string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Alert{}).Where("owner = ? AND read_at IS NULL", owner).Count(&count).Error
+	return count, err
+}
+
+// 标记提醒为已读，ids 为空时标记全部
+func (r *AlertRepository) MarkRead(owner string, ids []uint, readAt time.Time) (int64, error) {
+	query := r.db.Model(&domain.Alert{}).Where("owner = ? AND read_at IS NULL", owner)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
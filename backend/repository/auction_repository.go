@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"backend/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuctionRepository struct {
+	db *gorm.DB
+}
+
+func NewAuctionRepository(db *gorm.DB) *AuctionRepository {
+	return &AuctionRepository{db: db}
+}
+
+func (r *AuctionRepository) CreateAuction(auction *domain.Auction) error {
+	return r.db.Create(auction).Error
+}
+
+func (r *AuctionRepository) GetAuctionByID(id uint) (*domain.Auction, error) {
+	var auction domain.Auction
+	err := r.db.First(&auction, id).Error
+	return &auction, err
+}
+
+func (r *AuctionRepository) GetAuctionByHash(hash string) (*domain.Auction, error) {
+	var auction domain.Auction
+	err := r.db.Where("hash = ?", hash).First(&auction).Error
+	return &auction, err
+}
+
+// 获取拍卖列表，statuses 为空时不按状态过滤
+func (r *AuctionRepository) GetAuctions(statuses []uint) ([]domain.Auction, error) {
+	var auctions []domain.Auction
+	query := r.db.Model(&domain.Auction{})
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("end_time ASC").Find(&auctions).Error
+	return auctions, err
+}
+
+// 获取指定NFT进行中的拍卖
+func (r *AuctionRepository) GetActiveAuctionsByNFT(contractAddress string, tokenID uint) ([]domain.Auction, error) {
+	var auctions []domain.Auction
+	err := r.db.Where("nft_contract_address = ? AND token_id = ? AND status = ?", contractAddress, tokenID, 0).Find(&auctions).Error
+	return auctions, err
+}
+
+// 获取已到结束时间但仍为进行中的拍卖
+func (r *AuctionRepository) GetEndedAuctions(now time.Time) ([]domain.Auction, error) {
+	var auctions []domain.Auction
+	err := r.db.Where("status = ? AND end_time <= ?", 0, now).Find(&auctions).Error
+	return auctions, err
+}
+
+func (r *AuctionRepository) UpdateAuction(id uint, updates map[string]interface{}) error {
+	return r.db.Model(&domain.Auction{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *AuctionRepository) CreateBid(bid *domain.AuctionBid) error {
+	return r.db.Create(bid).Error
+}
+
+func (r *AuctionRepository) GetBidByID(id uint) (*domain.AuctionBid, error) {
+	var bid domain.AuctionBid
+	err := r.db.First(&bid, id).Error
+	return &bid, err
+}
+
+func (r *AuctionRepository) GetBidByHash(hash string) (*domain.AuctionBid, error) {
+	var bid domain.AuctionBid
+	err := r.db.Where("hash = ?", hash).First(&bid).Error
+	return &bid, err
+}
+
+// 获取拍卖的所有出价，按出价时间先后排序
+func (r *AuctionRepository) GetBidsByAuction(auctionID uint) ([]domain.AuctionBid, error) {
+	var bids []domain.AuctionBid
+	err := r.db.Where("auction_id = ?", auctionID).Order("id ASC").Find(&bids).Error
+	return bids, err
+}
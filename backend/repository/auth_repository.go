@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"backend/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuthRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthRepository(db *gorm.DB) *AuthRepository {
+	return &AuthRepository{db: db}
+}
+
+func (r *AuthRepository) CreateNonce(nonce *domain.AuthNonce) error {
+	return r.db.Create(nonce).Error
+}
+
+// 将未使用且未过期的 nonce 标记为已使用，返回 false 表示 nonce 不存在、已使用或已过期
+func (r *AuthRepository) ConsumeNonce(nonce string, now time.Time) (bool, error) {
+	result := r.db.Model(&domain.AuthNonce{}).
+		Where("nonce = ? AND used_at IS NULL AND expires_at > ?", nonce, now).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// 删除过期的 nonce
+func (r *AuthRepository) DeleteExpiredNonces(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&domain.AuthNonce{}).Error
+}
+
+func (r *AuthRepository) CreateSession(session *domain.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *AuthRepository) GetSessionByTokenID(tokenID string) (*domain.Session, error) {
+	var session domain.Session
+	err := r.db.Where("token_id = ?", tokenID).First(&session).Error
+	return &session, err
+}
+
+// 获取账户未撤销且未过期的会话
+func (r *AuthRepository) GetActiveSessions(address string, now time.Time) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.db.Where("address = ? AND revoked_at IS NULL AND expires_at > ?", address, now).
+		Order("id DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *AuthRepository) RevokeSession(tokenID string, now time.Time) error {
+	return r.db.Model(&domain.Session{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", now).Error
+}
+
+// 撤销账户的全部会话
+func (r *AuthRepository) RevokeSessionsByAddress(address string, now time.Time) error {
+	return r.db.Model(&domain.Session{}).
+		Where("address = ? AND revoked_at IS NULL", address).
+		Update("revoked_at", now).Error
+}
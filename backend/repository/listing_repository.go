@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"backend/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ListingRepository struct {
+	db *gorm.DB
+}
+
+func NewListingRepository(db *gorm.DB) *ListingRepository {
+	return &ListingRepository{db: db}
+}
+
+func (r *ListingRepository) CreateListing(listing *domain.Listing) error {
+	return r.db.Create(listing).Error
+}
+
+func (r *ListingRepository) GetListingByHash(hash string) (*domain.Listing, error) {
+	var listing domain.Listing
+	err := r.db.Where("hash = ?", hash).First(&listing).Error
+	return &listing, err
+}
+
+// 获取链下挂单，seller 为空时不按卖家过滤，statuses 为空时不按状态过滤
+func (r *ListingRepository) GetListings(seller string, statuses []uint) ([]domain.Listing, error) {
+	var listings []domain.Listing
+	query := r.db.Model(&domain.Listing{})
+	if seller != "" {
+		query = query.Where("seller = ?", seller)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("id DESC").Find(&listings).Error
+	return listings, err
+}
+
+func (r *ListingRepository) GetActiveListings() ([]domain.Listing, error) {
+	var listings []domain.Listing
+	err := r.db.Where("status = ?", 0).Find(&listings).Error
+	return listings, err
+}
+
+func (r *ListingRepository) GetActiveListingsByNFT(contractAddress string, tokenID uint) ([]domain.Listing, error) {
+	var listings []domain.Listing
+	err := r.db.Where("nft_contract_address = ? AND token_id = ? AND status = ?", contractAddress, tokenID, 0).Find(&listings).Error
+	return listings, err
+}
+
+func (r *ListingRepository) GetActiveListingsBySeller(contractAddress, seller string) ([]domain.Listing, error) {
+	var listings []domain.Listing
+	err := r.db.Where("nft_contract_address = ? AND seller = ? AND status = ?", contractAddress, seller, 0).Find(&listings).Error
+	return listings, err
+}
+
+func (r *ListingRepository) UpdateListingValidity(id uint, invalid bool, reason string) error {
+	return r.db.Model(&domain.Listing{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"invalid":        invalid,
+		"invalid_reason": reason,
+	}).Error
+}
+
+// 将有效挂单标记为已成交
+func (r *ListingRepository) MarkListingFilled(hash, buyer, transactionHash string) (int64, error) {
+	result := r.db.Model(&domain.Listing{}).Where("hash = ? AND status = ?", hash, 0).Updates(map[string]interface{}{
+		"status":           1,
+		"buyer":            buyer,
+		"fill_transaction": transactionHash,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// 取消卖家 nonce 小于指定值的有效挂单
+func (r *ListingRepository) CancelListingsBelowNonce(seller string, nonce uint64) (int64, error) {
+	result := r.db.Model(&domain.Listing{}).Where("seller = ? AND nonce < ? AND status = ?", seller, nonce, 0).Update("status", 2)
+	return result.RowsAffected, result.Error
+}
+
+// 将已过期的有效挂单标记为过期
+func (r *ListingRepository) ExpireListings(now time.Time) (int64, error) {
+	result := r.db.Model(&domain.Listing{}).Where("status = ? AND expiry <= ?", 0, now).Update("status", 3)
+	return result.RowsAffected, result.Error
+}
+
+// 获取账户当前的签名 nonce，未记录时为 0
+func (r *ListingRepository) GetSignerNonce(address string) (uint64, error) {
+	var nonce domain.SignerNonce
+	err := r.db.Where("address = ?", address).First(&nonce).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return nonce.Nonce, err
+}
+
+// 提高账户的签名 nonce，新值不大于当前值时不修改
+func (r *ListingRepository) IncreaseSignerNonce(address string, nonce uint64) (bool, error) {
+	increased := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current domain.SignerNonce
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("address = ?", address).First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			increased = nonce > 0
+			if !increased {
+				return nil
+			}
+			return tx.Create(&domain.SignerNonce{Address: address, Nonce: nonce}).Error
+		}
+		if err != nil {
+			return err
+		}
+		if nonce <= current.Nonce {
+			return nil
+		}
+		increased = true
+		return tx.Model(&current).Update("nonce", nonce).Error
+	})
+	return increased, err
+}
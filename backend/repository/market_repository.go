@@ -4,6 +4,7 @@ import (
 	"backend/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MarketRepository struct {
@@ -43,6 +44,107 @@ func (r *MarketRepository) UpdateOrderStatus(id uint, status uint) error {
 	return r.db.Model(&domain.Order{}).Where("id = ?", id).Update("status", status).Error
 }
 
+// 获取指定NFT的所有订单
+func (r *MarketRepository) GetOrdersByNFT(contractAddress string, tokenID uint) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Where("nft_contract_address = ? AND token_id = ?", contractAddress, tokenID).Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+// 获取指定NFT的所有未成交订单
+func (r *MarketRepository) GetActiveOrdersByNFT(contractAddress string, tokenID uint) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Where("nft_contract_address = ? AND token_id = ? AND status = ?", contractAddress, tokenID, 0).Find(&orders).Error
+	return orders, err
+}
+
+// 获取卖家在指定NFT合约下的所有未成交订单
+func (r *MarketRepository) GetActiveOrdersBySeller(contractAddress string, seller string) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Where("nft_contract_address = ? AND seller = ? AND status = ?", contractAddress, seller, 0).Find(&orders).Error
+	return orders, err
+}
+
+// 获取指定NFT合约下的所有订单
+func (r *MarketRepository) GetOrdersByCollection(contractAddress string) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Where("nft_contract_address = ?", contractAddress).Find(&orders).Error
+	return orders, err
+}
+
+func (r *MarketRepository) GetActiveOrders() ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Where("status = ?", 0).Find(&orders).Error
+	return orders, err
+}
+
+func (r *MarketRepository) UpdateOrderValidity(id uint, invalid bool, reason string) error {
+	return r.db.Model(&domain.Order{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"invalid":        invalid,
+		"invalid_reason": reason,
+	}).Error
+}
+
 func (r *MarketRepository) CreateNFTCollection(collection domain.NFTCollection) error {
 	return r.db.Create(&collection).Error
 }
+
+// 按 (合约地址, tokenID) 批量获取订单
+func (r *MarketRepository) GetOrdersByNFTKeys(keys []domain.NFTKey) ([]domain.Order, error) {
+	var orders []domain.Order
+	if len(keys) == 0 {
+		return orders, nil
+	}
+	err := r.db.Where("(nft_contract_address, token_id) IN ?", domain.NFTKeyTuples(keys)).Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+// 分页获取订单，各条件为空时不过滤
+func (r *MarketRepository) GetOrdersPage(contractAddress, seller string, statuses []uint, offset, limit int) ([]domain.Order, int64, error) {
+	query := r.db.Model(&domain.Order{})
+	if contractAddress != "" {
+		query = query.Where("nft_contract_address = ?", contractAddress)
+	}
+	if seller != "" {
+		query = query.Where("seller = ?", seller)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	// 计数和分页查询共用同一组条件
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var orders []domain.Order
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&orders).Error
+	return orders, total, err
+}
+
+// 保存订单的挂单或撤单事件，同一订单的同类事件重复保存时忽略
+func (r *MarketRepository) SaveOrderEvent(event *domain.OrderEvent) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
+
+// 获取指定NFT的所有挂单和撤单事件
+func (r *MarketRepository) GetOrderEventsByNFT(contractAddress string, tokenID uint) ([]domain.OrderEvent, error) {
+	var events []domain.OrderEvent
+	err := r.db.Where("nft_contract_address = ? AND token_id = ?", contractAddress, tokenID).
+		Order("block_number ASC, id ASC").Find(&events).Error
+	return events, err
+}
+
+// 在同一事务中清空并写入挂单和撤单事件，重建失败时保留原有记录
+func (r *MarketRepository) ReplaceOrderEvents(events []domain.OrderEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM order_events").Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(events, 100).Error
+	})
+}
@@ -4,8 +4,12 @@ import (
 	"backend/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// 判断属性值是否为数值的正则
+const numericValuePattern = "^-?[0-9]+(\\.[0-9]+)?$"
+
 type NFTRepository struct {
 	db *gorm.DB
 }
@@ -50,6 +54,106 @@ func (r *NFTRepository) GetNFTsByCollectionID(collectionID uint) ([]domain.NFT,
 	return nfts, err
 }
 
+// 按属性条件筛选NFT系列中的NFT
+func (r *NFTRepository) GetNFTsByCollectionIDFiltered(collectionID uint, filter *domain.TraitFilter, sort string) ([]domain.NFT, error) {
+	var nfts []domain.NFT
+	query := r.db.Where("nfts.collection_id = ?", collectionID)
+	if !filter.IsEmpty() {
+		query = query.Where(r.traitFilterCondition(filter))
+	}
+	switch sort {
+	case domain.NFTSortRarity:
+		query = query.Joins("LEFT JOIN nft_rarities r ON r.nft_id = nfts.id").
+			Order("r.`rank` IS NULL, r.`rank` ASC, nfts.token_id ASC")
+	case domain.NFTSortRarityDesc:
+		query = query.Joins("LEFT JOIN nft_rarities r ON r.nft_id = nfts.id").
+			Order("r.`rank` IS NULL, r.`rank` DESC, nfts.token_id ASC")
+	default:
+		query = query.Order("nfts.token_id ASC")
+	}
+	err := query.Find(&nfts).Error
+	return nfts, err
+}
+
+// 将属性筛选条件组合为 EXISTS 子查询
+func (r *NFTRepository) traitFilterCondition(filter *domain.TraitFilter) *gorm.DB {
+	cond := r.db.Session(&gorm.Session{NewDB: true})
+	add := func(query string, args ...interface{}) {
+		if filter.MatchAny {
+			cond = cond.Or(query, args...)
+		} else {
+			cond = cond.Where(query, args...)
+		}
+	}
+
+	for _, trait := range filter.Traits {
+		add("EXISTS (SELECT 1 FROM nft_attributes a WHERE a.nft_id = nfts.id AND a.trait_type = ? AND a.value IN ?)",
+			trait.TraitType, trait.Values)
+	}
+	for _, rng := range filter.Ranges {
+		query := "EXISTS (SELECT 1 FROM nft_attributes a WHERE a.nft_id = nfts.id AND a.trait_type = ? AND a.value REGEXP ?"
+		args := []interface{}{rng.TraitType, numericValuePattern}
+		if rng.Min != nil {
+			query += " AND CAST(a.value AS DECIMAL(65,18)) >= ?"
+			args = append(args, *rng.Min)
+		}
+		if rng.Max != nil {
+			query += " AND CAST(a.value AS DECIMAL(65,18)) <= ?"
+			args = append(args, *rng.Max)
+		}
+		add(query+")", args...)
+	}
+	return cond
+}
+
+// 统计NFT系列中每个属性取值的NFT数量及在售数量
+func (r *NFTRepository) GetTraitValueCounts(collectionID uint) ([]domain.TraitValueCount, error) {
+	var counts []domain.TraitValueCount
+	err := r.db.Table("nft_attributes AS a").
+		Select(`a.trait_type, a.value,
+			COUNT(DISTINCT n.id) AS count,
+			COUNT(DISTINCT CASE WHEN o.id IS NOT NULL THEN n.id END) AS listed_count`).
+		Joins("JOIN nfts n ON n.id = a.nft_id").
+		Joins("LEFT JOIN orders o ON o.nft_contract_address = n.contract_address AND o.token_id = n.token_id AND o.status = 0 AND o.invalid = 0").
+		Where("n.collection_id = ?", collectionID).
+		Group("a.trait_type, a.value").
+		Order("a.trait_type ASC, count DESC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// 获取NFT系列中所有NFT的属性
+func (r *NFTRepository) GetAttributesByCollectionID(collectionID uint) ([]domain.NFTAttribute, error) {
+	var attributes []domain.NFTAttribute
+	err := r.db.Joins("JOIN nfts n ON n.id = nft_attributes.nft_id").
+		Where("n.collection_id = ?", collectionID).
+		Find(&attributes).Error
+	return attributes, err
+}
+
+// 替换NFT系列的全部稀有度记录
+func (r *NFTRepository) ReplaceCollectionRarities(collectionID uint, rarities []domain.NFTRarity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collectionID).Delete(&domain.NFTRarity{}).Error; err != nil {
+			return err
+		}
+		if len(rarities) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rarities, 100).Error
+	})
+}
+
+func (r *NFTRepository) GetRarityByNFTID(nftID uint) (*domain.NFTRarity, error) {
+	var rarity domain.NFTRarity
+	err := r.db.Where("nft_id = ?", nftID).First(&rarity).Error
+	return &rarity, err
+}
+
+func (r *NFTRepository) ClearNFTRarities() error {
+	return r.db.Exec("TRUNCATE TABLE nft_rarities").Error
+}
+
 func (r *NFTRepository) SaveCollection(collection *domain.NFTCollection) error {
 	return r.db.Create(collection).Error
 }
@@ -58,8 +162,9 @@ func (r *NFTRepository) SaveNFT(nft *domain.NFT) error {
 	return r.db.Create(nft).Error
 }
 
+// 同一NFT的同名属性已存在时更新属性值，重试任务和元数据更新可以重复写入
 func (r *NFTRepository) SaveNFTAttribute(attribute *domain.NFTAttribute) error {
-	return r.db.Create(attribute).Error
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(attribute).Error
 }
 
 // 新增方法
@@ -116,6 +221,12 @@ func (r *NFTRepository) GetNFTTransferEvents(contractAddress string, tokenID uin
 	return events, err
 }
 
+func (r *NFTRepository) GetAllNFTTransferEvents() ([]domain.NFTTransferEvent, error) {
+	var events []domain.NFTTransferEvent
+	err := r.db.Order("block_number ASC").Find(&events).Error
+	return events, err
+}
+
 func (r *NFTRepository) GetLatestNFTTransferEvent(contractAddress string, tokenID uint) (*domain.NFTTransferEvent, error) {
 	var event domain.NFTTransferEvent
 	err := r.db.Where("contract_address = ? AND token_id = ?", contractAddress, tokenID).
@@ -127,3 +238,86 @@ func (r *NFTRepository) GetLatestNFTTransferEvent(contractAddress string, tokenI
 func (r *NFTRepository) ClearNFTTransferEvents() error {
 	return r.db.Exec("TRUNCATE TABLE nft_transfer_events").Error
 }
+
+// 统计地址持有的某系列NFT数量
+func (r *NFTRepository) CountNFTsByOwner(contractAddress, owner string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.NFT{}).Where("contract_address = ? AND owner = ?", contractAddress, owner).Count(&count).Error
+	return count, err
+}
+
+// 按 (合约地址, tokenID) 批量获取NFT
+func (r *NFTRepository) GetNFTsByKeys(keys []domain.NFTKey) ([]domain.NFT, error) {
+	var nfts []domain.NFT
+	if len(keys) == 0 {
+		return nfts, nil
+	}
+	err := r.db.Where("(contract_address, token_id) IN ?", domain.NFTKeyTuples(keys)).Find(&nfts).Error
+	return nfts, err
+}
+
+// 批量获取多个NFT的属性
+func (r *NFTRepository) GetAttributesByNFTIDs(nftIDs []uint) ([]domain.NFTAttribute, error) {
+	var attributes []domain.NFTAttribute
+	if len(nftIDs) == 0 {
+		return attributes, nil
+	}
+	err := r.db.Where("nft_id IN ?", nftIDs).Order("id ASC").Find(&attributes).Error
+	return attributes, err
+}
+
+// 批量获取多个NFT的转移记录
+func (r *NFTRepository) GetTransferEventsByKeys(keys []domain.NFTKey) ([]domain.NFTTransferEvent, error) {
+	var events []domain.NFTTransferEvent
+	if len(keys) == 0 {
+		return events, nil
+	}
+	err := r.db.Where("(contract_address, token_id) IN ?", domain.NFTKeyTuples(keys)).
+		Order("block_number ASC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *NFTRepository) GetCollectionsByAddresses(addresses []string) ([]domain.NFTCollection, error) {
+	var collections []domain.NFTCollection
+	if len(addresses) == 0 {
+		return collections, nil
+	}
+	err := r.db.Where("contract_address IN ?", addresses).Find(&collections).Error
+	return collections, err
+}
+
+// 分页获取NFT系列
+func (r *NFTRepository) GetCollectionsPage(offset, limit int) ([]domain.NFTCollection, int64, error) {
+	var collections []domain.NFTCollection
+	var total int64
+	if err := r.db.Model(&domain.NFTCollection{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := r.db.Order("id ASC").Offset(offset).Limit(limit).Find(&collections).Error
+	return collections, total, err
+}
+
+// 分页获取NFT，contractAddress 和 owner 为空时不过滤
+func (r *NFTRepository) GetNFTsPage(contractAddress, owner string, filter *domain.TraitFilter, offset, limit int) ([]domain.NFT, int64, error) {
+	query := r.db.Model(&domain.NFT{})
+	if contractAddress != "" {
+		query = query.Where("nfts.contract_address = ?", contractAddress)
+	}
+	if owner != "" {
+		query = query.Where("nfts.owner = ?", owner)
+	}
+	if !filter.IsEmpty() {
+		query = query.Where(r.traitFilterCondition(filter))
+	}
+
+	// 计数和分页查询共用同一组条件
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var nfts []domain.NFT
+	err := query.Order("nfts.contract_address ASC, nfts.token_id ASC").Offset(offset).Limit(limit).Find(&nfts).Error
+	return nfts, total, err
+}
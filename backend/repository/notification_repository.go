@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"backend/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// 保存通知，同一地址同类通知的 Reference 重复时忽略，返回是否新增
+func (r *NotificationRepository) CreateNotification(notification *domain.Notification) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	return result.RowsAffected > 0, result.Error
+}
+
+// 按ID倒序分页获取通知，beforeID 为 0 时从最新开始
+func (r *NotificationRepository) GetNotifications(address string, unreadOnly bool, beforeID uint, limit int) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	query := r.db.Where("address = ?", address)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *NotificationRepository) CountUnread(address string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).Where("address = ? AND read_at IS NULL", address).Count(&count).Error
+	return count, err
+}
+
+// 标记通知为已读，ids 为空时标记全部
+func (r *NotificationRepository) MarkRead(address string, ids []uint, readAt time.Time) (int64, error) {
+	query := r.db.Model(&domain.Notification{}).Where("address = ? AND read_at IS NULL", address)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"backend/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OfferRepository struct {
+	db *gorm.DB
+}
+
+func NewOfferRepository(db *gorm.DB) *OfferRepository {
+	return &OfferRepository{db: db}
+}
+
+func (r *OfferRepository) CreateOffer(offer *domain.Offer) error {
+	return r.db.Create(offer).Error
+}
+
+func (r *OfferRepository) GetOfferByID(id uint) (*domain.Offer, error) {
+	var offer domain.Offer
+	err := r.db.First(&offer, id).Error
+	return &offer, err
+}
+
+func (r *OfferRepository) GetOfferByHash(hash string) (*domain.Offer, error) {
+	var offer domain.Offer
+	err := r.db.Where("hash = ?", hash).First(&offer).Error
+	return &offer, err
+}
+
+// 获取对指定NFT有效的出价，包括对该NFT所在系列的出价
+func (r *OfferRepository) GetActiveOffersByNFT(contractAddress string, tokenID uint, includeInvalid bool) ([]domain.Offer, error) {
+	var offers []domain.Offer
+	query := r.db.Where("nft_contract_address = ? AND status = ? AND expiry > ?", contractAddress, 0, time.Now()).
+		Where("(collection_wide = ? AND token_id = ?) OR collection_wide = ?", false, tokenID, true)
+	if !includeInvalid {
+		query = query.Where("invalid = ?", false)
+	}
+	err := query.Order("id DESC").Find(&offers).Error
+	return offers, err
+}
+
+// 获取对整个系列的有效出价
+func (r *OfferRepository) GetActiveCollectionOffers(contractAddress string, includeInvalid bool) ([]domain.Offer, error) {
+	var offers []domain.Offer
+	query := r.db.Where("nft_contract_address = ? AND collection_wide = ? AND status = ? AND expiry > ?", contractAddress, true, 0, time.Now())
+	if !includeInvalid {
+		query = query.Where("invalid = ?", false)
+	}
+	err := query.Order("id DESC").Find(&offers).Error
+	return offers, err
+}
+
+func (r *OfferRepository) GetOffersByBidder(bidder string) ([]domain.Offer, error) {
+	var offers []domain.Offer
+	err := r.db.Where("bidder = ?", bidder).Order("id DESC").Find(&offers).Error
+	return offers, err
+}
+
+func (r *OfferRepository) GetActiveOffersByBidder(bidder string) ([]domain.Offer, error) {
+	var offers []domain.Offer
+	err := r.db.Where("bidder = ? AND status = ?", bidder, 0).Find(&offers).Error
+	return offers, err
+}
+
+func (r *OfferRepository) GetActiveOffers() ([]domain.Offer, error) {
+	var offers []domain.Offer
+	err := r.db.Where("status = ?", 0).Find(&offers).Error
+	return offers, err
+}
+
+func (r *OfferRepository) UpdateOfferStatus(id uint, status uint) error {
+	return r.db.Model(&domain.Offer{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// 将已过期的有效出价标记为过期
+func (r *OfferRepository) ExpireOffers(now time.Time) (int64, error) {
+	result := r.db.Model(&domain.Offer{}).Where("status = ? AND expiry <= ?", 0, now).Update("status", 2)
+	return result.RowsAffected, result.Error
+}
+
+func (r *OfferRepository) UpdateOfferValidity(id uint, invalid bool, reason string) error {
+	return r.db.Model(&domain.Offer{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"invalid":        invalid,
+		"invalid_reason": reason,
+	}).Error
+}
+
+// 获取买家当前的出价 nonce，未记录时为 0
+func (r *OfferRepository) GetBidderNonce(address string) (uint64, error) {
+	var nonce domain.BidderNonce
+	err := r.db.Where("address = ?", address).First(&nonce).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return nonce.Nonce, err
+}
+
+// 提高买家的出价 nonce，新值不大于当前值时不修改
+func (r *OfferRepository) IncreaseBidderNonce(address string, nonce uint64) (bool, error) {
+	increased := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current domain.BidderNonce
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("address = ?", address).First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			increased = nonce > 0
+			if !increased {
+				return nil
+			}
+			return tx.Create(&domain.BidderNonce{Address: address, Nonce: nonce}).Error
+		}
+		if err != nil {
+			return err
+		}
+		if nonce <= current.Nonce {
+			return nil
+		}
+		increased = true
+		return tx.Model(&current).Update("nonce", nonce).Error
+	})
+	return increased, err
+}
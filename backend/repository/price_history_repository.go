@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"backend/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// 在同一事务中保存成交记录并更新K线，buckets 为各周期成交所在K线的起始时间，apply 将成交计入K线。
+// 同一订单的成交已记录时不做修改并返回 false
+func (r *PriceHistoryRepository) RecordSale(sale *domain.Sale, buckets map[string]time.Time, apply func(candle *domain.PriceCandle)) (bool, error) {
+	recorded := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(sale)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		for interval, bucketStart := range buckets {
+			var candle domain.PriceCandle
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("nft_contract_address = ? AND token_address = ? AND `interval` = ? AND bucket_start = ?",
+					sale.NFTContractAddress, sale.TokenAddress, interval, bucketStart).First(&candle).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				candle = domain.PriceCandle{
+					NFTContractAddress: sale.NFTContractAddress,
+					TokenAddress:       sale.TokenAddress,
+					Interval:           interval,
+					BucketStart:        bucketStart,
+				}
+			} else if err != nil {
+				return err
+			}
+			apply(&candle)
+			if err := tx.Save(&candle).Error; err != nil {
+				return err
+			}
+		}
+		recorded = true
+		return nil
+	})
+	return recorded, err
+}
+
+func (r *PriceHistoryRepository) GetAllSales() ([]domain.Sale, error) {
+	var sales []domain.Sale
+	err := r.db.Order("block_timestamp ASC, id ASC").Find(&sales).Error
+	return sales, err
+}
+
+func (r *PriceHistoryRepository) GetSalesByNFT(contractAddress string, tokenID uint) ([]domain.Sale, error) {
+	var sales []domain.Sale
+	err := r.db.Where("nft_contract_address = ? AND token_id = ?", contractAddress, tokenID).
+		Order("block_timestamp ASC, id ASC").Find(&sales).Error
+	return sales, err
+}
+
+// 在同一事务中清空并写入成交记录，写入失败时保留原有记录
+func (r *PriceHistoryRepository) ReplaceSales(sales []domain.Sale) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM sales").Error; err != nil {
+			return err
+		}
+		if len(sales) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(sales, 100).Error
+	})
+}
+
+// 清空后批量写入K线
+func (r *PriceHistoryRepository) ReplaceCandles(candles []domain.PriceCandle) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM price_candles").Error; err != nil {
+			return err
+		}
+		if len(candles) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(candles, 100).Error
+	})
+}
+
+// 获取NFT系列的K线，tokenAddress 为空时返回所有支付代币
+func (r *PriceHistoryRepository) GetCandles(contractAddress, tokenAddress, interval string, from, to time.Time) ([]domain.PriceCandle, error) {
+	var candles []domain.PriceCandle
+	query := r.db.Where("nft_contract_address = ? AND `interval` = ?", contractAddress, interval)
+	if tokenAddress != "" {
+		query = query.Where("token_address = ?", tokenAddress)
+	}
+	if !from.IsZero() {
+		query = query.Where("bucket_start >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("bucket_start <= ?", to)
+	}
+	err := query.Order("bucket_start ASC, token_address ASC").Find(&candles).Error
+	return candles, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"backend/domain"
+
+	"gorm.io/gorm"
+)
+
+type ProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+func (r *ProfileRepository) GetProfileByAddress(address string) (*domain.Profile, error) {
+	var profile domain.Profile
+	err := r.db.Where("address = ?", address).First(&profile).Error
+	return &profile, err
+}
+
+// 用户名比较不区分大小写（由列的排序规则保证）
+func (r *ProfileRepository) GetProfileByUsername(username string) (*domain.Profile, error) {
+	var profile domain.Profile
+	err := r.db.Where("username = ?", username).First(&profile).Error
+	return &profile, err
+}
+
+func (r *ProfileRepository) GetProfilesByAddresses(addresses []string) ([]domain.Profile, error) {
+	var profiles []domain.Profile
+	err := r.db.Where("address IN ?", addresses).Find(&profiles).Error
+	return profiles, err
+}
+
+// 保存资料，ID 为 0 时创建
+func (r *ProfileRepository) SaveProfile(profile *domain.Profile) error {
+	return r.db.Save(profile).Error
+}
+
+// NFT转出后清除原持有者使用该NFT设置的头像
+func (r *ProfileRepository) ClearAvatar(nftAddress string, tokenID uint, newOwner string) error {
+	return r.db.Model(&domain.Profile{}).
+		Where("avatar_nft_address = ? AND avatar_token_id = ? AND address <> ?", nftAddress, tokenID, newOwner).
+		Updates(map[string]interface{}{"avatar_nft_address": "", "avatar_token_id": 0, "avatar_image": ""}).Error
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"backend/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RetryRepository struct {
+	db *gorm.DB
+}
+
+func NewRetryRepository(db *gorm.DB) *RetryRepository {
+	return &RetryRepository{db: db}
+}
+
+// 加入重试队列，已存在的待重试或死信任务保持原状，已完成的任务重新置为待重试
+func (r *RetryRepository) EnqueueJob(job *domain.RetryJob) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing domain.RetryJob
+		err := tx.Where("job_type = ? AND contract_address = ? AND token_id = ?", job.JobType, job.ContractAddress, job.TokenID).
+			First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			return tx.Create(job).Error
+		}
+		if err != nil {
+			return err
+		}
+		if existing.Status != domain.RetryJobStatusSucceeded {
+			*job = existing
+			return nil
+		}
+		return tx.Model(&existing).Updates(map[string]interface{}{
+			"status":        domain.RetryJobStatusPending,
+			"attempts":      0,
+			"next_retry_at": job.NextRetryAt,
+			"last_error":    job.LastError,
+		}).Error
+	})
+}
+
+func (r *RetryRepository) GetJobByID(id uint) (*domain.RetryJob, error) {
+	var job domain.RetryJob
+	err := r.db.First(&job, id).Error
+	return &job, err
+}
+
+// 获取已到重试时间的待重试任务
+func (r *RetryRepository) GetDueJobs(now time.Time, limit int) ([]domain.RetryJob, error) {
+	var jobs []domain.RetryJob
+	err := r.db.Where("status = ? AND next_retry_at <= ?", domain.RetryJobStatusPending, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// 获取指定状态的任务，按最近更新时间倒序
+func (r *RetryRepository) GetJobsByStatus(statuses []uint) ([]domain.RetryJob, error) {
+	var jobs []domain.RetryJob
+	err := r.db.Where("status IN ?", statuses).
+		Order("updated_at DESC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *RetryRepository) MarkJobSucceeded(id uint) error {
+	return r.db.Model(&domain.RetryJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     domain.RetryJobStatusSucceeded,
+		"last_error": "",
+	}).Error
+}
+
+func (r *RetryRepository) MarkJobFailed(id uint, attempts uint, nextRetryAt time.Time, lastError string, dead bool) error {
+	status := uint(domain.RetryJobStatusPending)
+	if dead {
+		status = domain.RetryJobStatusDead
+	}
+	return r.db.Model(&domain.RetryJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        status,
+		"attempts":      attempts,
+		"next_retry_at": nextRetryAt,
+		"last_error":    lastError,
+	}).Error
+}
+
+// 认领任务：仅当任务仍处于 from 中的状态时置为执行中，返回是否认领成功
+func (r *RetryRepository) ClaimJob(id uint, from []uint) (bool, error) {
+	result := r.db.Model(&domain.RetryJob{}).Where("id = ? AND status IN ?", id, from).
+		Update("status", domain.RetryJobStatusRunning)
+	return result.RowsAffected == 1, result.Error
+}
+
+// 将执行中的任务放回待重试，用于启动时恢复上次退出时未执行完的任务
+func (r *RetryRepository) ReleaseRunningJobs() error {
+	return r.db.Model(&domain.RetryJob{}).Where("status = ?", domain.RetryJobStatusRunning).
+		Update("status", domain.RetryJobStatusPending).Error
+}
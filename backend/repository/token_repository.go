@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"backend/domain"
+
+	"gorm.io/gorm"
+)
+
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) GetTokenByAddress(address string) (*domain.PaymentToken, error) {
+	var token domain.PaymentToken
+	err := r.db.Where("address = ?", address).First(&token).Error
+	return &token, err
+}
+
+func (r *TokenRepository) GetTokensByAddresses(addresses []string) ([]domain.PaymentToken, error) {
+	var tokens []domain.PaymentToken
+	err := r.db.Where("address IN ?", addresses).Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *TokenRepository) GetAllTokens() ([]domain.PaymentToken, error) {
+	var tokens []domain.PaymentToken
+	err := r.db.Order("id ASC").Find(&tokens).Error
+	return tokens, err
+}
+
+// 更新或插入代币元数据，不修改白名单/黑名单状态
+func (r *TokenRepository) UpsertToken(token *domain.PaymentToken) error {
+	return r.db.Where(domain.PaymentToken{Address: token.Address}).
+		Assign(map[string]interface{}{
+			"name":            token.Name,
+			"symbol":          token.Symbol,
+			"decimals":        token.Decimals,
+			"supports_permit": token.SupportsPermit,
+		}).
+		FirstOrCreate(token).Error
+}
+
+func (r *TokenRepository) UpdateTokenListStatus(address string, status uint) error {
+	return r.db.Model(&domain.PaymentToken{}).Where("address = ?", address).Update("list_status", status).Error
+}
+
+// 是否存在白名单代币
+func (r *TokenRepository) HasAllowedTokens() (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.PaymentToken{}).Where("list_status = ?", 1).Count(&count).Error
+	return count > 0, err
+}
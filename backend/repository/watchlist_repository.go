@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"backend/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WatchlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchlistRepository(db *gorm.DB) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+// 收藏NFT，重复收藏时忽略
+func (r *WatchlistRepository) AddFavorite(favorite *domain.Favorite) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(favorite).Error
+}
+
+func (r *WatchlistRepository) RemoveFavorite(address, nftAddress string, tokenID uint) (int64, error) {
+	result := r.db.Where("address = ? AND nft_contract_address = ? AND token_id = ?", address, nftAddress, tokenID).
+		Delete(&domain.Favorite{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *WatchlistRepository) GetFavoritesByAddress(address string) ([]domain.Favorite, error) {
+	var favorites []domain.Favorite
+	err := r.db.Where("address = ?", address).Order("id DESC").Find(&favorites).Error
+	return favorites, err
+}
+
+func (r *WatchlistRepository) CountFavorites(nftAddress string, tokenID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Favorite{}).
+		Where("nft_contract_address = ? AND token_id = ?", nftAddress, tokenID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *WatchlistRepository) IsFavorited(address, nftAddress string, tokenID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Favorite{}).
+		Where("address = ? AND nft_contract_address = ? AND token_id = ?", address, nftAddress, tokenID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// 关注NFT系列，重复关注时忽略
+func (r *WatchlistRepository) AddWatch(watch *domain.CollectionWatch) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(watch).Error
+}
+
+func (r *WatchlistRepository) RemoveWatch(address, contractAddress string) (int64, error) {
+	result := r.db.Where("address = ? AND contract_address = ?", address, contractAddress).
+		Delete(&domain.CollectionWatch{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *WatchlistRepository) GetWatchesByAddress(address string) ([]domain.CollectionWatch, error) {
+	var watches []domain.CollectionWatch
+	err := r.db.Where("address = ?", address).Order("id DESC").Find(&watches).Error
+	return watches, err
+}
+
+// 保存市场动态，同一事件重复保存时忽略
+func (r *WatchlistRepository) SaveActivity(activity *domain.MarketActivity) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(activity).Error
+}
+
+// 获取卖家此前使用同一支付代币挂单同一NFT的最近一条动态，用于判断降价
+func (r *WatchlistRepository) GetLatestListingActivity(nftAddress string, tokenID uint, tokenAddress, seller string) (*domain.MarketActivity, error) {
+	var activity domain.MarketActivity
+	err := r.db.Where("type IN ? AND nft_contract_address = ? AND token_id = ? AND token_address = ? AND seller = ?",
+		[]string{"listing", "price_drop"}, nftAddress, tokenID, tokenAddress, seller).
+		Order("id DESC").First(&activity).Error
+	return &activity, err
+}
+
+// 获取指定系列的市场动态，按 ID 倒序分页，beforeID 为 0 时从最新开始，types 为空时不按类型过滤
+func (r *WatchlistRepository) GetActivities(collections []string, types []string, beforeID uint, limit int) ([]domain.MarketActivity, error) {
+	var activities []domain.MarketActivity
+	query := r.db.Where("nft_contract_address IN ?", collections)
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&activities).Error
+	return activities, err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"backend/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateWebhook(webhook *domain.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *WebhookRepository) SaveWebhook(webhook *domain.Webhook) error {
+	return r.db.Save(webhook).Error
+}
+
+func (r *WebhookRepository) GetWebhookByID(id uint) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := r.db.First(&webhook, id).Error
+	return &webhook, err
+}
+
+func (r *WebhookRepository) GetWebhooksByOwner(owner string) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := r.db.Where("owner = ?", owner).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *WebhookRepository) GetActiveWebhooks() ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := r.db.Where("active = ?", true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// 删除推送地址及其投递记录
+func (r *WebhookRepository) DeleteWebhook(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Webhook{}, id).Error
+	})
+}
+
+func (r *WebhookRepository) CreateDeliveries(deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+func (r *WebhookRepository) GetDeliveryByID(id uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := r.db.First(&delivery, id).Error
+	return &delivery, err
+}
+
+// 获取推送地址的投递记录，statuses 为空时不按状态过滤
+func (r *WebhookRepository) GetDeliveries(webhookID uint, statuses []uint, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	query := r.db.Where("webhook_id = ?", webhookID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// 获取已到投递时间的待投递记录
+func (r *WebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", 0, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *WebhookRepository) UpdateDelivery(id uint, updates map[string]interface{}) error {
+	return r.db.Model(&domain.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error
+}
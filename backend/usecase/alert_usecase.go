@@ -0,0 +1,489 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 提醒规则类型
+const (
+	AlertKindFloorBelow    = "floor_below"
+	AlertKindTraitListing  = "trait_listing"
+	AlertKindNFTSold       = "nft_sold"
+	AlertKindOfferReceived = "offer_received"
+)
+
+// 提醒渠道，站内提醒总是记录
+const (
+	AlertChannelInbox   = "inbox"
+	AlertChannelEmail   = "email"
+	AlertChannelWebhook = "webhook"
+)
+
+const (
+	defaultMaxAlertsPerHour = 20
+	alertFloorCooldown      = time.Hour // 同一地板价规则在冷却期内只提醒一次
+	defaultAlertLimit       = 50
+	maxAlertLimit           = 200
+)
+
+var ErrInvalidAlertRule = domain.NewError(domain.ErrKindInvalid, "invalid_alert_rule", "无效的提醒规则")
+
+// AlertConfig 表示提醒的发送配置
+type AlertConfig struct {
+	SMTP             SMTPConfig `json:"smtp"`
+	MaxAlertsPerHour int        `json:"maxAlertsPerHour"` // 每个用户每小时通过邮件和 Webhook 发送的提醒上限
+}
+
+// SMTPConfig 表示发送提醒邮件的 SMTP 服务，Host 为空时不支持邮件提醒
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"` // 为空时不进行认证，便于连接本地的邮件捕获服务
+	Password string `json:"password"`
+	From     string `json:"from"`
+}
+
+// AlertRuleRequest 表示创建或更新提醒规则的参数
+type AlertRuleRequest struct {
+	Kind         string   `json:"kind" binding:"required"`
+	Collection   string   `json:"collection"`
+	TraitType    string   `json:"traitType"`
+	TraitValue   string   `json:"traitValue"`
+	TokenAddress string   `json:"tokenAddress"`
+	Price        string   `json:"price"`
+	Channels     []string `json:"channels"`
+	Email        string   `json:"email"`
+	Active       *bool    `json:"active"`
+}
+
+type AlertUseCase struct {
+	alertRepo *repository.AlertRepository
+	nftRepo   *repository.NFTRepository
+	tokenUC   *TokenUseCase
+	webhookUC *WebhookUseCase
+	config    AlertConfig
+}
+
+// 创建后挂到 marketUC 上，由市场事件触发提醒
+func NewAlertUseCase(alertRepo *repository.AlertRepository, nftRepo *repository.NFTRepository, tokenUC *TokenUseCase, webhookUC *WebhookUseCase, marketUC *MarketUseCase, config AlertConfig) *AlertUseCase {
+	if config.MaxAlertsPerHour <= 0 {
+		config.MaxAlertsPerHour = defaultMaxAlertsPerHour
+	}
+	uc := &AlertUseCase{
+		alertRepo: alertRepo,
+		nftRepo:   nftRepo,
+		tokenUC:   tokenUC,
+		webhookUC: webhookUC,
+		config:    config,
+	}
+	marketUC.alertUC = uc
+	return uc
+}
+
+// 校验并规范化提醒规则参数
+func (uc *AlertUseCase) applyRequest(rule *domain.AlertRule, req *AlertRuleRequest) error {
+	switch req.Kind {
+	case AlertKindFloorBelow:
+		if req.Collection == "" || req.Price == "" {
+			return fmt.Errorf("%w: 地板价提醒需要指定系列和价格", ErrInvalidAlertRule)
+		}
+	case AlertKindTraitListing:
+		if req.Collection == "" || req.TraitType == "" || req.TraitValue == "" {
+			return fmt.Errorf("%w: 属性挂单提醒需要指定系列和属性", ErrInvalidAlertRule)
+		}
+	case AlertKindNFTSold, AlertKindOfferReceived:
+	default:
+		return fmt.Errorf("%w: 未知的提醒类型 %s", ErrInvalidAlertRule, req.Kind)
+	}
+
+	if req.Collection != "" && !common.IsHexAddress(req.Collection) {
+		return fmt.Errorf("%w: 无效的合约地址", ErrInvalidAlertRule)
+	}
+	if req.Price != "" {
+		if price, ok := new(big.Int).SetString(req.Price, 10); !ok || price.Sign() < 0 {
+			return fmt.Errorf("%w: 价格必须是代币最小单位的非负整数", ErrInvalidAlertRule)
+		}
+		if !common.IsHexAddress(req.TokenAddress) {
+			return fmt.Errorf("%w: 指定价格时需要指定支付代币", ErrInvalidAlertRule)
+		}
+	} else if req.TokenAddress != "" && !common.IsHexAddress(req.TokenAddress) {
+		return fmt.Errorf("%w: 无效的代币地址", ErrInvalidAlertRule)
+	}
+
+	channels := make([]string, 0, len(req.Channels))
+	for _, channel := range req.Channels {
+		channel = strings.ToLower(strings.TrimSpace(channel))
+		switch channel {
+		case AlertChannelInbox, AlertChannelWebhook:
+		case AlertChannelEmail:
+			if uc.config.SMTP.Host == "" {
+				return fmt.Errorf("%w: 未配置邮件服务", ErrInvalidAlertRule)
+			}
+			if _, err := mail.ParseAddress(req.Email); err != nil {
+				return fmt.Errorf("%w: 无效的邮箱地址", ErrInvalidAlertRule)
+			}
+		default:
+			return fmt.Errorf("%w: 未知的提醒渠道 %s", ErrInvalidAlertRule, channel)
+		}
+		if !containsFold(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	rule.Kind = req.Kind
+	rule.Collection = ""
+	if req.Collection != "" {
+		rule.Collection = common.HexToAddress(req.Collection).Hex()
+	}
+	rule.TraitType = strings.TrimSpace(req.TraitType)
+	rule.TraitValue = strings.TrimSpace(req.TraitValue)
+	rule.TokenAddress = ""
+	if req.TokenAddress != "" {
+		rule.TokenAddress = common.HexToAddress(req.TokenAddress).Hex()
+	}
+	rule.Price = req.Price
+	rule.Channels = strings.Join(channels, ",")
+	rule.Email = strings.TrimSpace(req.Email)
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return nil
+}
+
+func (uc *AlertUseCase) CreateRule(owner string, req *AlertRuleRequest) (*domain.AlertRule, error) {
+	rule := &domain.AlertRule{
+		Owner:  common.HexToAddress(owner).Hex(),
+		Active: true,
+	}
+	if err := uc.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := uc.alertRepo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (uc *AlertUseCase) GetRules(owner string) ([]domain.AlertRule, error) {
+	return uc.alertRepo.GetRulesByOwner(common.HexToAddress(owner).Hex())
+}
+
+// 获取属于 owner 的规则，不属于时按不存在处理
+func (uc *AlertUseCase) getRule(owner string, id uint) (*domain.AlertRule, error) {
+	rule, err := uc.alertRepo.GetRuleByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrAlertRuleNotFound)
+	}
+	if !strings.EqualFold(rule.Owner, owner) {
+		return nil, domain.ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+func (uc *AlertUseCase) UpdateRule(owner string, id uint, req *AlertRuleRequest) (*domain.AlertRule, error) {
+	rule, err := uc.getRule(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := uc.alertRepo.SaveRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (uc *AlertUseCase) DeleteRule(owner string, id uint) error {
+	if _, err := uc.getRule(owner, id); err != nil {
+		return err
+	}
+	return uc.alertRepo.DeleteRule(id)
+}
+
+// 分页获取提醒和未读数量
+func (uc *AlertUseCase) GetAlerts(owner string, unreadOnly bool, beforeID uint, limit int) ([]domain.Alert, int64, error) {
+	if limit <= 0 {
+		limit = defaultAlertLimit
+	}
+	if limit > maxAlertLimit {
+		limit = maxAlertLimit
+	}
+	owner = common.HexToAddress(owner).Hex()
+	alerts, err := uc.alertRepo.GetAlerts(owner, unreadOnly, beforeID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := uc.alertRepo.CountUnread(owner)
+	if err != nil {
+		return nil, 0, err
+	}
+	return alerts, unread, nil
+}
+
+// 标记提醒为已读，ids 为空时标记全部
+func (uc *AlertUseCase) MarkRead(owner string, ids []uint) (int64, error) {
+	return uc.alertRepo.MarkRead(common.HexToAddress(owner).Hex(), ids, time.Now())
+}
+
+// 新挂单时检查地板价和属性挂单提醒，uc 为 nil 时不做任何事
+func (uc *AlertUseCase) evaluateListing(reference, nftAddress string, tokenID uint, tokenAddress, price, seller string) {
+	if uc == nil {
+		return
+	}
+
+	floorRules, err := uc.alertRepo.GetActiveRules(AlertKindFloorBelow, nftAddress)
+	if err != nil {
+		log.Printf("获取提醒规则失败: %v", err)
+	}
+	now := time.Now()
+	for i := range floorRules {
+		rule := &floorRules[i]
+		if !strings.EqualFold(rule.TokenAddress, tokenAddress) || comparePrice(price, rule.Price) >= 0 {
+			continue
+		}
+		uc.trigger(rule, &domain.Alert{
+			DedupKey:           fmt.Sprintf("floor:%d", now.Truncate(alertFloorCooldown).Unix()),
+			Title:              fmt.Sprintf("%s 地板价低于 %s", uc.collectionName(nftAddress), uc.formatPrice(tokenAddress, rule.Price)),
+			Message:            fmt.Sprintf("#%d 以 %s 挂单", tokenID, uc.formatPrice(tokenAddress, price)),
+			NFTContractAddress: nftAddress,
+			TokenID:            tokenID,
+			TokenAddress:       tokenAddress,
+			Price:              price,
+			Reference:          reference,
+		})
+	}
+
+	traitRules, err := uc.alertRepo.GetActiveRules(AlertKindTraitListing, nftAddress)
+	if err != nil {
+		log.Printf("获取提醒规则失败: %v", err)
+	}
+	if len(traitRules) == 0 {
+		return
+	}
+	nft, err := uc.nftRepo.GetByTokenID(nftAddress, tokenID)
+	if err != nil {
+		return
+	}
+	attributes, err := uc.nftRepo.GetAttributes(nft.ID)
+	if err != nil {
+		log.Printf("获取NFT属性失败: %v", err)
+		return
+	}
+	for i := range traitRules {
+		rule := &traitRules[i]
+		if strings.EqualFold(rule.Owner, seller) || !hasTrait(attributes, rule.TraitType, rule.TraitValue) {
+			continue
+		}
+		if rule.Price != "" && (!strings.EqualFold(rule.TokenAddress, tokenAddress) || comparePrice(price, rule.Price) >= 0) {
+			continue
+		}
+		uc.trigger(rule, &domain.Alert{
+			DedupKey:           reference,
+			Title:              fmt.Sprintf("%s 有 %s: %s 的新挂单", uc.collectionName(nftAddress), rule.TraitType, rule.TraitValue),
+			Message:            fmt.Sprintf("%s #%d 以 %s 挂单", nft.Name, tokenID, uc.formatPrice(tokenAddress, price)),
+			NFTContractAddress: nftAddress,
+			TokenID:            tokenID,
+			TokenAddress:       tokenAddress,
+			Price:              price,
+			Reference:          reference,
+		})
+	}
+}
+
+// 成交时提醒卖家，uc 为 nil 时不做任何事
+func (uc *AlertUseCase) evaluateSale(reference, nftAddress string, tokenID uint, tokenAddress, price, seller, buyer string) {
+	if uc == nil {
+		return
+	}
+	rules, err := uc.alertRepo.GetActiveRules(AlertKindNFTSold, nftAddress)
+	if err != nil {
+		log.Printf("获取提醒规则失败: %v", err)
+		return
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if !strings.EqualFold(rule.Owner, seller) {
+			continue
+		}
+		uc.trigger(rule, &domain.Alert{
+			DedupKey:           reference,
+			Title:              fmt.Sprintf("你的 %s #%d 已售出", uc.collectionName(nftAddress), tokenID),
+			Message:            fmt.Sprintf("买家 %s 以 %s 购买", buyer, uc.formatPrice(tokenAddress, price)),
+			NFTContractAddress: nftAddress,
+			TokenID:            tokenID,
+			TokenAddress:       tokenAddress,
+			Price:              price,
+			Reference:          reference,
+		})
+	}
+}
+
+// 收到出价时提醒持有者，系列出价提醒持有该系列任意NFT的用户，uc 为 nil 时不做任何事
+func (uc *AlertUseCase) evaluateOffer(offer *domain.Offer) {
+	if uc == nil {
+		return
+	}
+	rules, err := uc.alertRepo.GetActiveRules(AlertKindOfferReceived, offer.NFTContractAddress)
+	if err != nil {
+		log.Printf("获取提醒规则失败: %v", err)
+		return
+	}
+
+	owner := ""
+	if !offer.CollectionWide {
+		nft, err := uc.nftRepo.GetByTokenID(offer.NFTContractAddress, offer.TokenID)
+		if err != nil {
+			return
+		}
+		owner = nft.Owner
+	}
+
+	reference := fmt.Sprintf("offer:%s", offer.Hash)
+	for i := range rules {
+		rule := &rules[i]
+		if strings.EqualFold(rule.Owner, offer.Bidder) {
+			continue
+		}
+		if rule.Price != "" && (!strings.EqualFold(rule.TokenAddress, offer.TokenAddress) || comparePrice(offer.Price, rule.Price) <= 0) {
+			continue
+		}
+
+		title := fmt.Sprintf("你的 %s #%d 收到出价", uc.collectionName(offer.NFTContractAddress), offer.TokenID)
+		if offer.CollectionWide {
+			count, err := uc.nftRepo.CountNFTsByOwner(offer.NFTContractAddress, rule.Owner)
+			if err != nil || count == 0 {
+				continue
+			}
+			title = fmt.Sprintf("你持有的 %s 收到系列出价", uc.collectionName(offer.NFTContractAddress))
+		} else if !strings.EqualFold(rule.Owner, owner) {
+			continue
+		}
+
+		uc.trigger(rule, &domain.Alert{
+			DedupKey:           reference,
+			Title:              title,
+			Message:            fmt.Sprintf("%s 出价 %s", offer.Bidder, uc.formatPrice(offer.TokenAddress, offer.Price)),
+			NFTContractAddress: offer.NFTContractAddress,
+			TokenID:            offer.TokenID,
+			TokenAddress:       offer.TokenAddress,
+			Price:              offer.Price,
+			Reference:          reference,
+		})
+	}
+}
+
+// 记录提醒并按规则的渠道发送，重复的提醒直接忽略，超过频率限制时只记录站内提醒
+func (uc *AlertUseCase) trigger(rule *domain.AlertRule, alert *domain.Alert) {
+	now := time.Now()
+	alert.Owner = rule.Owner
+	alert.RuleID = rule.ID
+	alert.Kind = rule.Kind
+	alert.CreatedAt = now
+
+	channels := strings.Split(rule.Channels, ",")
+	external := containsFold(channels, AlertChannelEmail) || containsFold(channels, AlertChannelWebhook)
+	if external {
+		sent, err := uc.alertRepo.CountSentAlerts(rule.Owner, now.Add(-time.Hour))
+		if err != nil {
+			log.Printf("统计已发送提醒失败: %v", err)
+		}
+		alert.Throttled = sent >= int64(uc.config.MaxAlertsPerHour)
+	}
+
+	created, err := uc.alertRepo.CreateAlert(alert)
+	if err != nil {
+		log.Printf("保存提醒失败: %v", err)
+		return
+	}
+	if !created {
+		return
+	}
+	if err := uc.alertRepo.TouchRule(rule.ID, now); err != nil {
+		log.Printf("更新提醒规则失败 (ID: %d): %v", rule.ID, err)
+	}
+	if !external || alert.Throttled {
+		return
+	}
+
+	if containsFold(channels, AlertChannelEmail) {
+		go func(to string) {
+			if err := uc.sendEmail(to, alert.Title, alert.Message); err != nil {
+				log.Printf("发送提醒邮件失败 (提醒ID: %d): %v", alert.ID, err)
+			}
+		}(rule.Email)
+	}
+	if containsFold(channels, AlertChannelWebhook) {
+		uc.webhookUC.dispatch(webhookEvent{
+			Type:       WebhookEventAlertTriggered,
+			Owner:      rule.Owner,
+			Collection: alert.NFTContractAddress,
+			Addresses:  []string{rule.Owner},
+			Data:       alert,
+		})
+	}
+}
+
+func (uc *AlertUseCase) sendEmail(to, subject, body string) error {
+	smtpConfig := uc.config.SMTP
+	var auth smtp.Auth
+	if smtpConfig.Username != "" {
+		auth = smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, smtpConfig.Host)
+	}
+	message := strings.Join([]string{
+		"From: " + smtpConfig.From,
+		"To: " + to,
+		"Subject: " + mime.BEncoding.Encode("UTF-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+	addr := fmt.Sprintf("%s:%d", smtpConfig.Host, smtpConfig.Port)
+	return smtp.SendMail(addr, auth, smtpConfig.From, []string{to}, []byte(message))
+}
+
+func (uc *AlertUseCase) collectionName(contractAddress string) string {
+	if collection, err := uc.nftRepo.GetCollectionByAddress(contractAddress); err == nil && collection.Name != "" {
+		return collection.Name
+	}
+	return contractAddress
+}
+
+func (uc *AlertUseCase) formatPrice(tokenAddress, price string) string {
+	if token, exists := uc.tokenUC.lookupTokens([]string{tokenAddress})[strings.ToLower(tokenAddress)]; exists {
+		return FormatTokenAmount(price, token.Decimals) + " " + token.Symbol
+	}
+	return price
+}
+
+func hasTrait(attributes []domain.NFTAttribute, traitType, value string) bool {
+	for _, attribute := range attributes {
+		if strings.EqualFold(attribute.TraitType, traitType) && strings.EqualFold(attribute.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// 比较两个十进制整数价格，无法解析时视为不满足条件
+func comparePrice(a, b string) int {
+	x, ok1 := new(big.Int).SetString(a, 10)
+	y, ok2 := new(big.Int).SetString(b, 10)
+	if !ok1 || !ok2 {
+		return 0
+	}
+	return x.Cmp(y)
+}
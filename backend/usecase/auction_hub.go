@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"sync"
+
+	"backend/domain"
+)
+
+// auctionHub 将拍卖状态的变化推送给订阅的客户端
+type auctionHub struct {
+	subscribers map[uint]map[chan domain.AuctionView]struct{}
+	mutex       sync.Mutex
+}
+
+func newAuctionHub() *auctionHub {
+	return &auctionHub{subscribers: make(map[uint]map[chan domain.AuctionView]struct{})}
+}
+
+func (h *auctionHub) subscribe(auctionID uint) (chan domain.AuctionView, func()) {
+	ch := make(chan domain.AuctionView, 8)
+
+	h.mutex.Lock()
+	if h.subscribers[auctionID] == nil {
+		h.subscribers[auctionID] = make(map[chan domain.AuctionView]struct{})
+	}
+	h.subscribers[auctionID][ch] = struct{}{}
+	h.mutex.Unlock()
+
+	unsubscribe := func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		if _, exists := h.subscribers[auctionID][ch]; exists {
+			delete(h.subscribers[auctionID], ch)
+			if len(h.subscribers[auctionID]) == 0 {
+				delete(h.subscribers, auctionID)
+			}
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// 推送拍卖状态，订阅者处理不及时时丢弃本次推送
+func (h *auctionHub) publish(view domain.AuctionView) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for ch := range h.subscribers[view.ID] {
+		select {
+		case ch <- view:
+		default:
+		}
+	}
+}
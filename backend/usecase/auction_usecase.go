@@ -0,0 +1,778 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"gorm.io/gorm"
+)
+
+// 拍卖类型
+const (
+	AuctionTypeEnglish = "english"
+	AuctionTypeDutch   = "dutch"
+)
+
+// 拍卖状态
+const (
+	AuctionStatusActive    = 0
+	AuctionStatusSettled   = 1
+	AuctionStatusCancelled = 2
+	AuctionStatusNoWinner  = 3
+)
+
+const (
+	// 检查到期拍卖的间隔
+	auctionCloseCheckInterval = 5 * time.Second
+	// 出价签名在拍卖结束后至少需要保持有效的时长，供卖家成交
+	auctionSettlementWindow = 24 * time.Hour
+)
+
+var (
+	ErrInvalidAuction   = domain.NewError(domain.ErrKindInvalid, "invalid_auction", "无效的拍卖")
+	ErrAuctionExists    = domain.NewError(domain.ErrKindConflict, "auction_exists", "拍卖已存在")
+	ErrAuctionNotActive = domain.NewError(domain.ErrKindConflict, "auction_not_active", "拍卖已结束")
+	ErrInvalidBid       = domain.NewError(domain.ErrKindInvalid, "invalid_bid", "无效的拍卖出价")
+)
+
+var auctionFields = []apitypes.Type{
+	{Name: "seller", Type: "address"},
+	{Name: "nft", Type: "address"},
+	{Name: "tokenId", Type: "uint256"},
+	{Name: "paymentToken", Type: "address"},
+	{Name: "auctionType", Type: "string"},
+	{Name: "reservePrice", Type: "uint256"},
+	{Name: "minIncrement", Type: "uint256"},
+	{Name: "startPrice", Type: "uint256"},
+	{Name: "endPrice", Type: "uint256"},
+	{Name: "startTime", Type: "uint256"},
+	{Name: "endTime", Type: "uint256"},
+	{Name: "extension", Type: "uint256"},
+	{Name: "salt", Type: "uint256"},
+}
+
+var cancelAuctionFields = []apitypes.Type{
+	{Name: "auctionHash", Type: "bytes32"},
+}
+
+// AuctionRequest 表示卖家创建拍卖的参数，未使用的价格字段填 "0"
+type AuctionRequest struct {
+	Seller           string
+	NFTAddress       string
+	TokenID          uint
+	TokenAddress     string
+	Type             string
+	ReservePrice     string
+	MinIncrement     string
+	StartPrice       string
+	EndPrice         string
+	StartTime        uint64
+	EndTime          uint64
+	ExtensionSeconds uint
+	Salt             string
+	Signature        string
+}
+
+// AuctionBidRequest 表示拍卖出价参数，签名数据与链下出价相同
+type AuctionBidRequest struct {
+	Bidder    string
+	Price     string
+	Nonce     string
+	Expiry    uint64
+	Signature string
+}
+
+// AuctionTypedData 表示待签名的拍卖相关数据
+type AuctionTypedData struct {
+	Hash      string
+	TypedData TypedDataJSON // 可直接用于 eth_signTypedData_v4
+}
+
+type AuctionUseCase struct {
+	auctionRepo *repository.AuctionRepository
+	offerRepo   *repository.OfferRepository
+	offerUC     *OfferUseCase
+	marketUC    *MarketUseCase
+	tokenUC     *TokenUseCase
+	hub         *auctionHub
+	mutex       sync.Mutex // 串行处理出价和结束拍卖
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewAuctionUseCase(auctionRepo *repository.AuctionRepository, offerRepo *repository.OfferRepository, offerUC *OfferUseCase, marketUC *MarketUseCase, tokenUC *TokenUseCase) *AuctionUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	uc := &AuctionUseCase{
+		auctionRepo: auctionRepo,
+		offerRepo:   offerRepo,
+		offerUC:     offerUC,
+		marketUC:    marketUC,
+		tokenUC:     tokenUC,
+		hub:         newAuctionHub(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	return uc
+}
+
+// Start 启动结束到期拍卖的协程，需在所有用例构造完成后调用
+func (uc *AuctionUseCase) Start() {
+	go uc.startAuctionCloser()
+}
+
+func (uc *AuctionUseCase) Close() {
+	uc.cancel()
+}
+
+func parseAmount(value string, allowZero bool) (*big.Int, bool) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 || (!allowZero && amount.Sign() == 0) {
+		return nil, false
+	}
+	return amount, true
+}
+
+// 校验拍卖参数并构建卖家签名数据
+func (uc *AuctionUseCase) auctionTypedData(req AuctionRequest) (apitypes.TypedData, error) {
+	if !common.IsHexAddress(req.Seller) || !common.IsHexAddress(req.NFTAddress) || !common.IsHexAddress(req.TokenAddress) {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 地址格式错误", ErrInvalidAuction)
+	}
+	if req.EndTime <= req.StartTime || req.EndTime <= uint64(time.Now().Unix()) {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 结束时间必须晚于开始时间和当前时间", ErrInvalidAuction)
+	}
+	salt, ok := parseAmount(req.Salt, true)
+	if !ok {
+		return apitypes.TypedData{}, fmt.Errorf("%w: salt必须为非负整数", ErrInvalidAuction)
+	}
+
+	reserve, minIncrement, startPrice, endPrice := big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	switch req.Type {
+	case AuctionTypeEnglish:
+		if reserve, ok = parseAmount(req.ReservePrice, false); !ok {
+			return apitypes.TypedData{}, fmt.Errorf("%w: 保留价必须为大于0的整数", ErrInvalidAuction)
+		}
+		if minIncrement, ok = parseAmount(req.MinIncrement, false); !ok {
+			return apitypes.TypedData{}, fmt.Errorf("%w: 最小加价幅度必须为大于0的整数", ErrInvalidAuction)
+		}
+	case AuctionTypeDutch:
+		if startPrice, ok = parseAmount(req.StartPrice, false); !ok {
+			return apitypes.TypedData{}, fmt.Errorf("%w: 起始价必须为大于0的整数", ErrInvalidAuction)
+		}
+		if endPrice, ok = parseAmount(req.EndPrice, false); !ok || endPrice.Cmp(startPrice) >= 0 {
+			return apitypes.TypedData{}, fmt.Errorf("%w: 结束价必须为大于0且低于起始价的整数", ErrInvalidAuction)
+		}
+		if req.ExtensionSeconds != 0 {
+			return apitypes.TypedData{}, fmt.Errorf("%w: 荷兰式拍卖不支持延时", ErrInvalidAuction)
+		}
+	default:
+		return apitypes.TypedData{}, fmt.Errorf("%w: 拍卖类型必须为 english 或 dutch", ErrInvalidAuction)
+	}
+
+	return uc.marketUC.marketTypedData("Auction", auctionFields, apitypes.TypedDataMessage{
+		"seller":       common.HexToAddress(req.Seller).Hex(),
+		"nft":          common.HexToAddress(req.NFTAddress).Hex(),
+		"tokenId":      new(big.Int).SetUint64(uint64(req.TokenID)).String(),
+		"paymentToken": common.HexToAddress(req.TokenAddress).Hex(),
+		"auctionType":  req.Type,
+		"reservePrice": reserve.String(),
+		"minIncrement": minIncrement.String(),
+		"startPrice":   startPrice.String(),
+		"endPrice":     endPrice.String(),
+		"startTime":    new(big.Int).SetUint64(req.StartTime).String(),
+		"endTime":      new(big.Int).SetUint64(req.EndTime).String(),
+		"extension":    new(big.Int).SetUint64(uint64(req.ExtensionSeconds)).String(),
+		"salt":         salt.String(),
+	}), nil
+}
+
+// 构建待卖家签名的拍卖数据
+func (uc *AuctionUseCase) BuildAuctionTypedData(req AuctionRequest) (*AuctionTypedData, error) {
+	typedData, err := uc.auctionTypedData(req)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &AuctionTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)}, nil
+}
+
+// 校验卖家签名、持有和授权情况后创建拍卖
+func (uc *AuctionUseCase) CreateAuction(req AuctionRequest) (*domain.AuctionView, error) {
+	typedData, err := uc.auctionTypedData(req)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := verifyTypedDataSigner(typedData, req.Signature, req.Seller)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.auctionRepo.GetAuctionByHash(hash.Hex()); err == nil {
+		return nil, ErrAuctionExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	nftAddress := common.HexToAddress(req.NFTAddress).Hex()
+	active, err := uc.auctionRepo.GetActiveAuctionsByNFT(nftAddress, req.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("获取NFT进行中的拍卖失败: %w", err)
+	}
+	if len(active) > 0 {
+		return nil, fmt.Errorf("%w: 该NFT已有进行中的拍卖 (拍卖ID: %d)", ErrInvalidAuction, active[0].ID)
+	}
+
+	paymentToken, err := uc.tokenUC.EnsureToken(req.TokenAddress)
+	if err != nil {
+		return nil, chainCallError(err, fmt.Errorf("%w: 支付代币不是有效的ERC-20合约", ErrInvalidAuction))
+	}
+	accepted, err := uc.tokenUC.IsTokenAccepted(paymentToken)
+	if err != nil {
+		return nil, fmt.Errorf("检查代币名单失败: %w", err)
+	}
+	if !accepted {
+		return nil, fmt.Errorf("%w: 市场不接受该支付代币", ErrInvalidAuction)
+	}
+
+	seller := common.HexToAddress(req.Seller).Hex()
+	_, reason, err := uc.marketUC.checkOrderValidity(&domain.Order{NFTContractAddress: nftAddress, TokenID: req.TokenID, Seller: seller})
+	if err != nil {
+		return nil, chainCallError(err, fmt.Errorf("%w: NFT不存在或合约不支持ERC-721", ErrInvalidAuction))
+	}
+	switch reason {
+	case OrderInvalidReasonNotOwner:
+		return nil, fmt.Errorf("%w: 卖家未持有该NFT", ErrInvalidAuction)
+	case OrderInvalidReasonNotApproved:
+		return nil, fmt.Errorf("%w: 市场合约未获授权", ErrInvalidAuction)
+	}
+
+	signature, err := normalizeSignature(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	auction := &domain.Auction{
+		Hash:               hash.Hex(),
+		Type:               req.Type,
+		Seller:             seller,
+		NFTContractAddress: nftAddress,
+		TokenID:            req.TokenID,
+		TokenAddress:       common.HexToAddress(req.TokenAddress).Hex(),
+		ReservePrice:       typedData.Message["reservePrice"].(string),
+		MinIncrement:       typedData.Message["minIncrement"].(string),
+		StartPrice:         typedData.Message["startPrice"].(string),
+		EndPrice:           typedData.Message["endPrice"].(string),
+		StartTime:          time.Unix(int64(req.StartTime), 0),
+		EndTime:            time.Unix(int64(req.EndTime), 0),
+		ExtensionSeconds:   req.ExtensionSeconds,
+		Salt:               typedData.Message["salt"].(string),
+		Signature:          signature,
+		Status:             AuctionStatusActive,
+	}
+	if err := uc.auctionRepo.CreateAuction(auction); err != nil {
+		return nil, fmt.Errorf("保存拍卖失败: %w", err)
+	}
+	return uc.describeAuction(auction)
+}
+
+// 荷兰式拍卖在指定时刻的价格，从起始价线性下降到结束价
+func dutchPrice(auction *domain.Auction, at time.Time) *big.Int {
+	startPrice, _ := new(big.Int).SetString(auction.StartPrice, 10)
+	endPrice, _ := new(big.Int).SetString(auction.EndPrice, 10)
+	if startPrice == nil || endPrice == nil {
+		return big.NewInt(0)
+	}
+	if !at.After(auction.StartTime) {
+		return startPrice
+	}
+	if !at.Before(auction.EndTime) {
+		return endPrice
+	}
+
+	duration := big.NewInt(int64(auction.EndTime.Sub(auction.StartTime) / time.Second))
+	elapsed := big.NewInt(int64(at.Sub(auction.StartTime) / time.Second))
+	if duration.Sign() == 0 {
+		return endPrice
+	}
+	drop := new(big.Int).Sub(startPrice, endPrice)
+	drop.Mul(drop, elapsed).Div(drop, duration)
+	return new(big.Int).Sub(startPrice, drop)
+}
+
+// 英式拍卖当前的最高出价
+func highestBid(bids []domain.AuctionBid) *domain.AuctionBid {
+	var leader *domain.AuctionBid
+	var leaderPrice *big.Int
+	for i := range bids {
+		price, ok := new(big.Int).SetString(bids[i].Price, 10)
+		if !ok {
+			continue
+		}
+		if leader == nil || price.Cmp(leaderPrice) > 0 {
+			leader, leaderPrice = &bids[i], price
+		}
+	}
+	return leader
+}
+
+// 按出价从高到低排列英式拍卖的出价，价格相同时先出价者在前，无效价格的出价被忽略
+func rankBids(bids []domain.AuctionBid) []domain.AuctionBid {
+	type pricedBid struct {
+		bid   domain.AuctionBid
+		price *big.Int
+	}
+	priced := make([]pricedBid, 0, len(bids))
+	for _, bid := range bids {
+		if price, ok := new(big.Int).SetString(bid.Price, 10); ok {
+			priced = append(priced, pricedBid{bid: bid, price: price})
+		}
+	}
+	sort.SliceStable(priced, func(i, j int) bool {
+		return priced[i].price.Cmp(priced[j].price) > 0
+	})
+
+	ranked := make([]domain.AuctionBid, len(priced))
+	for i := range priced {
+		ranked[i] = priced[i].bid
+	}
+	return ranked
+}
+
+// 结束时仍可成交的最高出价：跳过nonce已失效、余额或授权不足的出价，都不可成交时返回nil
+func (uc *AuctionUseCase) winningBid(auction *domain.Auction, bids []domain.AuctionBid) (*domain.AuctionBid, error) {
+	for _, bid := range rankBids(bids) {
+		if _, valid, err := uc.offerUC.checkBidderNonce(bid.Bidder, bid.Nonce); err != nil {
+			return nil, err
+		} else if !valid {
+			continue
+		}
+		invalid, _, err := uc.offerUC.checkOfferFunds(&domain.Offer{Bidder: bid.Bidder, TokenAddress: auction.TokenAddress, Price: bid.Price})
+		if err != nil {
+			return nil, fmt.Errorf("检查买家资金失败: %w", err)
+		}
+		if !invalid {
+			return &bid, nil
+		}
+	}
+	return nil, nil
+}
+
+// 下一次出价的最低价格：无出价时为保留价，否则为最高出价加最小加价幅度
+func minNextBid(auction *domain.Auction, leader *domain.AuctionBid, now time.Time) *big.Int {
+	if auction.Type == AuctionTypeDutch {
+		return dutchPrice(auction, now)
+	}
+	if leader == nil {
+		reserve, _ := new(big.Int).SetString(auction.ReservePrice, 10)
+		return reserve
+	}
+	price, _ := new(big.Int).SetString(leader.Price, 10)
+	increment, _ := new(big.Int).SetString(auction.MinIncrement, 10)
+	return new(big.Int).Add(price, increment)
+}
+
+// 计算拍卖的当前价格和领先者
+func (uc *AuctionUseCase) describeAuction(auction *domain.Auction) (*domain.AuctionView, error) {
+	bids, err := uc.auctionRepo.GetBidsByAuction(auction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取拍卖出价失败: %w", err)
+	}
+
+	now := time.Now()
+	view := &domain.AuctionView{Auction: *auction, BidCount: len(bids)}
+	view.SellerProfile = uc.tokenUC.profileUC.Summaries(auction.Seller)[strings.ToLower(auction.Seller)]
+
+	if auction.WinningBidID != 0 {
+		for i := range bids {
+			if bids[i].ID == auction.WinningBidID {
+				view.Leader = &bids[i]
+			}
+		}
+	} else if auction.Type == AuctionTypeEnglish {
+		view.Leader = highestBid(bids)
+	}
+
+	var current *big.Int
+	switch {
+	case view.Leader != nil:
+		current, _ = new(big.Int).SetString(view.Leader.Price, 10)
+	case auction.Type == AuctionTypeDutch:
+		current = dutchPrice(auction, now)
+	default:
+		current, _ = new(big.Int).SetString(auction.ReservePrice, 10)
+	}
+	if current == nil {
+		current = big.NewInt(0)
+	}
+	view.CurrentPrice = current.String()
+	view.CurrentPriceFormatted = current.String()
+
+	if auction.Status == AuctionStatusActive {
+		if next := minNextBid(auction, view.Leader, now); next != nil {
+			view.MinNextBid = next.String()
+		}
+		if remaining := auction.EndTime.Sub(now); remaining > 0 {
+			view.SecondsRemaining = int64(remaining / time.Second)
+		}
+	}
+
+	if token, exists := uc.tokenUC.lookupTokens([]string{auction.TokenAddress})[strings.ToLower(auction.TokenAddress)]; exists {
+		view.PaymentToken = token
+		view.CurrentPriceFormatted = FormatTokenAmount(view.CurrentPrice, token.Decimals)
+		view.CurrentPriceUSD = uc.tokenUC.oracle.ToUSD(auction.TokenAddress, view.CurrentPrice, token.Decimals)
+	}
+	return view, nil
+}
+
+func (uc *AuctionUseCase) GetAuction(id uint) (*domain.AuctionView, error) {
+	auction, err := uc.auctionRepo.GetAuctionByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrAuctionNotFound)
+	}
+	return uc.describeAuction(auction)
+}
+
+func (uc *AuctionUseCase) GetAuctions(statuses []uint) ([]domain.AuctionView, error) {
+	auctions, err := uc.auctionRepo.GetAuctions(statuses)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]domain.AuctionView, 0, len(auctions))
+	for i := range auctions {
+		view, err := uc.describeAuction(&auctions[i])
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *view)
+	}
+	return views, nil
+}
+
+func (uc *AuctionUseCase) GetAuctionBids(id uint) ([]domain.AuctionBid, error) {
+	if _, err := uc.auctionRepo.GetAuctionByID(id); err != nil {
+		return nil, notFoundAs(err, domain.ErrAuctionNotFound)
+	}
+	return uc.auctionRepo.GetBidsByAuction(id)
+}
+
+// 拍卖出价使用链下出价的签名格式，成交后可直接作为出价由卖家成交
+func (uc *AuctionUseCase) bidOfferRequest(auction *domain.Auction, req AuctionBidRequest) OfferRequest {
+	return OfferRequest{
+		Bidder:       req.Bidder,
+		NFTAddress:   auction.NFTContractAddress,
+		TokenID:      auction.TokenID,
+		TokenAddress: auction.TokenAddress,
+		Price:        req.Price,
+		Nonce:        req.Nonce,
+		Expiry:       req.Expiry,
+		Signature:    req.Signature,
+	}
+}
+
+// 构建待买家签名的拍卖出价数据
+func (uc *AuctionUseCase) BuildBidTypedData(id uint, req AuctionBidRequest) (*AuctionTypedData, error) {
+	auction, err := uc.auctionRepo.GetAuctionByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrAuctionNotFound)
+	}
+	typedData, err := uc.offerUC.BuildOfferTypedData(uc.bidOfferRequest(auction, req))
+	if err != nil {
+		return nil, err
+	}
+	return &AuctionTypedData{Hash: typedData.Hash, TypedData: typedData.TypedData}, nil
+}
+
+// 校验并记录拍卖出价，英式拍卖在结束前出价时顺延结束时间，荷兰式拍卖出价不低于当前价格时立即成交
+func (uc *AuctionUseCase) PlaceBid(id uint, req AuctionBidRequest) (*domain.AuctionView, error) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	auction, err := uc.auctionRepo.GetAuctionByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrAuctionNotFound)
+	}
+	now := time.Now()
+	if auction.Status != AuctionStatusActive || !now.Before(auction.EndTime) {
+		return nil, ErrAuctionNotActive
+	}
+	if now.Before(auction.StartTime) {
+		return nil, fmt.Errorf("%w: 拍卖尚未开始", ErrInvalidBid)
+	}
+	if strings.EqualFold(req.Bidder, auction.Seller) {
+		return nil, fmt.Errorf("%w: 卖家不能参与自己的拍卖", ErrInvalidBid)
+	}
+	if req.Expiry < uint64(auction.EndTime.Add(auctionSettlementWindow).Unix()) {
+		return nil, fmt.Errorf("%w: 出价签名的有效期必须至少持续到拍卖结束后 %s", ErrInvalidBid, auctionSettlementWindow)
+	}
+
+	offerReq := uc.bidOfferRequest(auction, req)
+	typedData, err := uc.offerUC.offerTypedData(offerReq)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := verifyTypedDataSigner(typedData, req.Signature, req.Bidder)
+	if err != nil {
+		return nil, err
+	}
+	if current, valid, err := uc.offerUC.checkBidderNonce(req.Bidder, typedData.Message["nonce"].(string)); err != nil {
+		return nil, err
+	} else if !valid {
+		return nil, fmt.Errorf("%w: nonce已失效，当前nonce为 %d", ErrInvalidBid, current)
+	}
+	if _, err := uc.auctionRepo.GetBidByHash(hash.Hex()); err == nil {
+		return nil, fmt.Errorf("%w: 重复的出价", ErrInvalidBid)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	bids, err := uc.auctionRepo.GetBidsByAuction(auction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取拍卖出价失败: %w", err)
+	}
+	price, _ := new(big.Int).SetString(typedData.Message["price"].(string), 10)
+	minimum := minNextBid(auction, highestBid(bids), now)
+	if price.Cmp(minimum) < 0 {
+		return nil, fmt.Errorf("%w: 出价不能低于 %s", ErrInvalidBid, minimum)
+	}
+
+	bid := &domain.AuctionBid{
+		AuctionID: auction.ID,
+		Hash:      hash.Hex(),
+		Bidder:    common.HexToAddress(req.Bidder).Hex(),
+		Price:     price.String(),
+		Nonce:     typedData.Message["nonce"].(string),
+		Expiry:    time.Unix(int64(req.Expiry), 0),
+	}
+	if bid.Signature, err = normalizeSignature(req.Signature); err != nil {
+		return nil, err
+	}
+	_, reason, err := uc.offerUC.checkOfferFunds(&domain.Offer{Bidder: bid.Bidder, TokenAddress: auction.TokenAddress, Price: bid.Price})
+	if err != nil {
+		return nil, chainCallError(fmt.Errorf("检查买家资金失败: %w", err), fmt.Errorf("%w: 无法读取买家的代币余额", ErrInvalidBid))
+	}
+	switch reason {
+	case OfferInvalidReasonBalance:
+		return nil, fmt.Errorf("%w: 买家余额不足", ErrInvalidBid)
+	case OfferInvalidReasonAllowance:
+		return nil, fmt.Errorf("%w: 买家未授权市场合约使用足够的代币", ErrInvalidBid)
+	}
+
+	if err := uc.auctionRepo.CreateBid(bid); err != nil {
+		return nil, fmt.Errorf("保存拍卖出价失败: %w", err)
+	}
+
+	switch auction.Type {
+	case AuctionTypeDutch:
+		// 荷兰式拍卖第一个有效出价即成交
+		if err := uc.settleAuction(auction, bid); err != nil {
+			return nil, err
+		}
+	case AuctionTypeEnglish:
+		// 防狙击：结束前 ExtensionSeconds 内的出价将结束时间顺延
+		extension := time.Duration(auction.ExtensionSeconds) * time.Second
+		if extension > 0 && auction.EndTime.Sub(now) < extension {
+			auction.EndTime = now.Add(extension)
+			if err := uc.auctionRepo.UpdateAuction(auction.ID, map[string]interface{}{"end_time": auction.EndTime}); err != nil {
+				return nil, fmt.Errorf("延长拍卖结束时间失败: %w", err)
+			}
+		}
+	}
+
+	return uc.publishAuction(auction)
+}
+
+// 将中标出价导出为链下出价，卖家可直接成交
+func (uc *AuctionUseCase) settleAuction(auction *domain.Auction, bid *domain.AuctionBid) error {
+	offer, err := uc.offerRepo.GetOfferByHash(bid.Hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		offer = &domain.Offer{
+			Hash:               bid.Hash,
+			Bidder:             bid.Bidder,
+			NFTContractAddress: auction.NFTContractAddress,
+			TokenID:            auction.TokenID,
+			TokenAddress:       auction.TokenAddress,
+			Price:              bid.Price,
+			Nonce:              bid.Nonce,
+			Expiry:             bid.Expiry,
+			Signature:          bid.Signature,
+			Status:             OfferStatusActive,
+		}
+		err = uc.offerRepo.CreateOffer(offer)
+	}
+	if err != nil {
+		return fmt.Errorf("导出中标出价失败: %w", err)
+	}
+
+	auction.Status = AuctionStatusSettled
+	auction.WinningBidID = bid.ID
+	auction.WinningOfferID = offer.ID
+	return uc.auctionRepo.UpdateAuction(auction.ID, map[string]interface{}{
+		"status":           auction.Status,
+		"winning_bid_id":   auction.WinningBidID,
+		"winning_offer_id": auction.WinningOfferID,
+	})
+}
+
+// 结束拍卖：英式拍卖由仍可成交的最高出价中标，没有可成交的出价时流拍
+func (uc *AuctionUseCase) closeAuction(id uint) error {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	// 到期列表在加锁前读取，期间拍卖可能已被出价成交或延长结束时间，需要重新读取
+	auction, err := uc.auctionRepo.GetAuctionByID(id)
+	if err != nil {
+		return fmt.Errorf("获取拍卖失败: %w", err)
+	}
+	if auction.Status != AuctionStatusActive || time.Now().Before(auction.EndTime) {
+		return nil
+	}
+
+	var winner *domain.AuctionBid
+	if auction.Type == AuctionTypeEnglish {
+		bids, err := uc.auctionRepo.GetBidsByAuction(auction.ID)
+		if err != nil {
+			return fmt.Errorf("获取拍卖出价失败: %w", err)
+		}
+		if winner, err = uc.winningBid(auction, bids); err != nil {
+			return err
+		}
+	}
+
+	if winner != nil {
+		if err := uc.settleAuction(auction, winner); err != nil {
+			return err
+		}
+	} else {
+		auction.Status = AuctionStatusNoWinner
+		if err := uc.auctionRepo.UpdateAuction(auction.ID, map[string]interface{}{"status": auction.Status}); err != nil {
+			return err
+		}
+	}
+
+	_, err = uc.publishAuction(auction)
+	return err
+}
+
+func (uc *AuctionUseCase) startAuctionCloser() {
+	ticker := time.NewTicker(auctionCloseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			auctions, err := uc.auctionRepo.GetEndedAuctions(time.Now())
+			if err != nil {
+				log.Printf("获取到期拍卖失败: %v", err)
+				continue
+			}
+			for i := range auctions {
+				if err := uc.closeAuction(auctions[i].ID); err != nil {
+					log.Printf("结束拍卖失败 (拍卖ID: %d): %v", auctions[i].ID, err)
+				}
+			}
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+func (uc *AuctionUseCase) cancelTypedData(auction *domain.Auction) apitypes.TypedData {
+	return uc.marketUC.marketTypedData("CancelAuction", cancelAuctionFields, apitypes.TypedDataMessage{
+		"auctionHash": auction.Hash,
+	})
+}
+
+// 构建取消拍卖需要卖家签名的数据
+func (uc *AuctionUseCase) BuildCancelTypedData(id uint) (*AuctionTypedData, error) {
+	auction, err := uc.auctionRepo.GetAuctionByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrAuctionNotFound)
+	}
+	typedData := uc.cancelTypedData(auction)
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &AuctionTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)}, nil
+}
+
+// 校验卖家签名后取消拍卖，已有出价的拍卖不能取消
+func (uc *AuctionUseCase) CancelAuction(id uint, signature string) (*domain.AuctionView, error) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	auction, err := uc.auctionRepo.GetAuctionByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrAuctionNotFound)
+	}
+	if auction.Status != AuctionStatusActive {
+		return nil, ErrAuctionNotActive
+	}
+	if _, err := verifyTypedDataSigner(uc.cancelTypedData(auction), signature, auction.Seller); err != nil {
+		return nil, err
+	}
+	bids, err := uc.auctionRepo.GetBidsByAuction(auction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取拍卖出价失败: %w", err)
+	}
+	if len(bids) > 0 {
+		return nil, fmt.Errorf("%w: 已有出价的拍卖不能取消", ErrInvalidAuction)
+	}
+
+	auction.Status = AuctionStatusCancelled
+	if err := uc.auctionRepo.UpdateAuction(auction.ID, map[string]interface{}{"status": auction.Status}); err != nil {
+		return nil, fmt.Errorf("取消拍卖失败: %w", err)
+	}
+	return uc.publishAuction(auction)
+}
+
+// 计算拍卖最新状态并推送给订阅者
+func (uc *AuctionUseCase) publishAuction(auction *domain.Auction) (*domain.AuctionView, error) {
+	view, err := uc.describeAuction(auction)
+	if err != nil {
+		return nil, err
+	}
+	uc.hub.publish(*view)
+	return view, nil
+}
+
+// 订阅拍卖状态变化，返回的函数用于取消订阅
+func (uc *AuctionUseCase) Subscribe(id uint) (<-chan domain.AuctionView, func()) {
+	return uc.hub.subscribe(id)
+}
+
+// 解析以逗号分隔的拍卖状态名称
+func ParseAuctionStatuses(raw string) ([]uint, error) {
+	names := map[string]uint{
+		"active":    AuctionStatusActive,
+		"settled":   AuctionStatusSettled,
+		"cancelled": AuctionStatusCancelled,
+		"no_winner": AuctionStatusNoWinner,
+	}
+	statuses := make([]uint, 0)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		status, exists := names[name]
+		if !exists {
+			return nil, fmt.Errorf("%w: 未知的拍卖状态 %s", ErrInvalidAuction, name)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"backend/domain"
+)
+
+func TestDutchPrice(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	auction := &domain.Auction{
+		StartPrice: "1000",
+		EndPrice:   "100",
+		StartTime:  start,
+		EndTime:    start.Add(100 * time.Second),
+	}
+
+	tests := []struct {
+		name    string
+		auction *domain.Auction
+		at      time.Time
+		want    string
+	}{
+		{"开始前为起始价", auction, start.Add(-time.Hour), "1000"},
+		{"开始时为起始价", auction, start, "1000"},
+		{"线性下降", auction, start.Add(25 * time.Second), "775"},
+		{"一半时间", auction, start.Add(50 * time.Second), "550"},
+		{"不足一秒按整秒计算", auction, start.Add(10*time.Second + 900*time.Millisecond), "910"},
+		{"结束时为结束价", auction, start.Add(100 * time.Second), "100"},
+		{"结束后为结束价", auction, start.Add(time.Hour), "100"},
+		{
+			"除不尽时向下取整降幅",
+			&domain.Auction{StartPrice: "10", EndPrice: "0", StartTime: start, EndTime: start.Add(3 * time.Second)},
+			start.Add(time.Second),
+			"7",
+		},
+		{
+			"超过 uint64 范围",
+			&domain.Auction{StartPrice: "200000000000000000000", EndPrice: "100000000000000000000", StartTime: start, EndTime: start.Add(4 * time.Second)},
+			start.Add(time.Second),
+			"175000000000000000000",
+		},
+		{
+			"时长不足一秒时为结束价",
+			&domain.Auction{StartPrice: "1000", EndPrice: "100", StartTime: start, EndTime: start.Add(500 * time.Millisecond)},
+			start.Add(100 * time.Millisecond),
+			"100",
+		},
+		{
+			"价格无法解析",
+			&domain.Auction{StartPrice: "abc", EndPrice: "100", StartTime: start, EndTime: start.Add(time.Minute)},
+			start.Add(time.Second),
+			"0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dutchPrice(tt.auction, tt.at); got.String() != tt.want {
+				t.Fatalf("dutchPrice = %s，应为 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankBids(t *testing.T) {
+	bids := []domain.AuctionBid{
+		{ID: 1, Price: "100"},
+		{ID: 2, Price: "300"},
+		{ID: 3, Price: "invalid"},
+		{ID: 4, Price: "300"},
+		{ID: 5, Price: "200"},
+	}
+
+	ranked := rankBids(bids)
+	want := []uint{2, 4, 5, 1}
+	if len(ranked) != len(want) {
+		t.Fatalf("排序后的出价数量为 %d，应为 %d", len(ranked), len(want))
+	}
+	for i, id := range want {
+		if ranked[i].ID != id {
+			t.Fatalf("第 %d 个出价的ID为 %d，应为 %d", i, ranked[i].ID, id)
+		}
+	}
+	if leader := highestBid(bids); leader == nil || leader.ID != ranked[0].ID {
+		t.Fatalf("排序后的第一个出价应与 highestBid 一致，实际为 %v", leader)
+	}
+}
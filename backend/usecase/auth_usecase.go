@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/contracts"
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultNonceTTL   = 10 * time.Minute
+	defaultSessionTTL = 7 * 24 * time.Hour
+	// 允许 Issued At 超前服务器时间的误差
+	siweClockSkew = 5 * time.Minute
+	// 清理过期 nonce 的间隔
+	nonceCleanupInterval = time.Hour
+)
+
+var (
+	ErrUnauthorized = domain.NewError(domain.ErrKindUnauthorized, "unauthorized", "未登录或登录已过期")
+	// 登录签名校验失败返回 401，与下单等接口的 ErrInvalidSignature 区分
+	ErrInvalidLoginSignature = domain.NewError(domain.ErrKindUnauthorized, "invalid_login_signature", "签名无效")
+	ErrForbidden             = domain.NewError(domain.ErrKindForbidden, "forbidden", "无权访问")
+)
+
+// AuthConfig 表示登录配置
+type AuthConfig struct {
+	Domains           []string `json:"domains"`           // 允许的 SIWE 域名（含端口，如 localhost:8080），不能为空
+	JWTSecret         string   `json:"jwtSecret"`         // 为空时启动时随机生成，重启后已签发的令牌失效
+	SessionTTLSeconds int      `json:"sessionTTLSeconds"` // 会话有效期
+	NonceTTLSeconds   int      `json:"nonceTTLSeconds"`   // nonce 有效期
+	AdminAddresses    []string `json:"adminAddresses"`    // 可以访问管理接口的钱包地址，为空时所有人都无权访问
+}
+
+// AuthNonceInfo 表示签发给客户端的登录 nonce
+type AuthNonceInfo struct {
+	Nonce     string
+	ExpiresAt time.Time
+	ChainID   uint64
+}
+
+// AuthSession 表示登录成功后签发的令牌
+type AuthSession struct {
+	Token     string
+	Address   string
+	ChainID   uint64
+	ExpiresAt time.Time
+}
+
+// jwtClaims 是会话令牌中的声明
+type jwtClaims struct {
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub"`
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ChainID   uint64 `json:"chainId"`
+}
+
+type AuthUseCase struct {
+	authRepo   *repository.AuthRepository
+	marketUC   *MarketUseCase
+	validator  *contracts.ERC1271Validator
+	domains    []string
+	admins     map[common.Address]bool
+	secret     []byte
+	sessionTTL time.Duration
+	nonceTTL   time.Duration
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func NewAuthUseCase(authRepo *repository.AuthRepository, marketUC *MarketUseCase, config AuthConfig, ethClientURL string) (*AuthUseCase, error) {
+	// 不校验域名时其他网站骗取的签名也能登录，未配置时拒绝启动
+	if len(config.Domains) == 0 {
+		return nil, errors.New("未配置允许登录的域名（config/auth.json 的 domains）")
+	}
+
+	validator, err := contracts.NewERC1271Validator(ethClientURL)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := []byte(config.JWTSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("生成JWT密钥失败: %w", err)
+		}
+		log.Printf("未配置JWT密钥，使用随机密钥，重启后需要重新登录")
+	}
+
+	admins := make(map[common.Address]bool, len(config.AdminAddresses))
+	for _, address := range config.AdminAddresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("无效的管理员地址: %s", address)
+		}
+		admins[common.HexToAddress(address)] = true
+	}
+	if len(admins) == 0 {
+		log.Printf("未配置管理员地址，管理接口不可用")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	uc := &AuthUseCase{
+		authRepo:   authRepo,
+		marketUC:   marketUC,
+		validator:  validator,
+		domains:    config.Domains,
+		admins:     admins,
+		secret:     secret,
+		sessionTTL: defaultSessionTTL,
+		nonceTTL:   defaultNonceTTL,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	if config.SessionTTLSeconds > 0 {
+		uc.sessionTTL = time.Duration(config.SessionTTLSeconds) * time.Second
+	}
+	if config.NonceTTLSeconds > 0 {
+		uc.nonceTTL = time.Duration(config.NonceTTLSeconds) * time.Second
+	}
+
+	// 启动清理过期 nonce 的协程
+	go uc.startNonceCleaner()
+
+	return uc, nil
+}
+
+func (uc *AuthUseCase) Close() {
+	uc.cancel()
+}
+
+// 判断地址是否在管理员列表中
+func (uc *AuthUseCase) IsAdmin(address string) bool {
+	return common.IsHexAddress(address) && uc.admins[common.HexToAddress(address)]
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// URI 必须是 http 或 https 地址，且与消息中的域名（和 scheme）属于同一来源
+func checkSIWEURI(msg *SIWEMessage) error {
+	target, err := url.Parse(msg.URI)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return fmt.Errorf("%w: URI 必须是http或https地址", ErrInvalidSIWEMessage)
+	}
+	if !strings.EqualFold(target.Host, msg.Domain) || (msg.Scheme != "" && !strings.EqualFold(target.Scheme, msg.Scheme)) {
+		return fmt.Errorf("%w: URI 与域名 %s 不一致", ErrInvalidSIWEMessage, msg.Domain)
+	}
+	return nil
+}
+
+// 签发一次性的登录 nonce
+func (uc *AuthUseCase) IssueNonce() (*AuthNonceInfo, error) {
+	nonce, err := randomHex(16)
+	if err != nil {
+		return nil, fmt.Errorf("生成nonce失败: %w", err)
+	}
+	record := &domain.AuthNonce{Nonce: nonce, ExpiresAt: time.Now().Add(uc.nonceTTL)}
+	if err := uc.authRepo.CreateNonce(record); err != nil {
+		return nil, fmt.Errorf("保存nonce失败: %w", err)
+	}
+	return &AuthNonceInfo{Nonce: nonce, ExpiresAt: record.ExpiresAt, ChainID: uc.marketUC.chainID.Uint64()}, nil
+}
+
+// 校验 SIWE 消息和签名，成功后创建会话并签发令牌
+func (uc *AuthUseCase) Login(message, signature, userAgent string) (*AuthSession, error) {
+	msg, err := ParseSIWEMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !containsFold(uc.domains, msg.Domain) {
+		return nil, fmt.Errorf("%w: 不受信任的域名 %s", ErrInvalidSIWEMessage, msg.Domain)
+	}
+	if err := checkSIWEURI(msg); err != nil {
+		return nil, err
+	}
+	if msg.ChainID != uc.marketUC.chainID.Uint64() {
+		return nil, fmt.Errorf("%w: Chain ID 与市场所在链不一致", ErrInvalidSIWEMessage)
+	}
+	if msg.IssuedAt.After(now.Add(siweClockSkew)) {
+		return nil, fmt.Errorf("%w: Issued At 晚于当前时间", ErrInvalidSIWEMessage)
+	}
+	if msg.ExpirationTime != nil && !msg.ExpirationTime.After(now) {
+		return nil, fmt.Errorf("%w: 消息已过期", ErrInvalidSIWEMessage)
+	}
+	if msg.NotBefore != nil && msg.NotBefore.After(now) {
+		return nil, fmt.Errorf("%w: 消息尚未生效", ErrInvalidSIWEMessage)
+	}
+
+	if err := uc.verifySIWESignature(message, signature, common.HexToAddress(msg.Address)); err != nil {
+		return nil, err
+	}
+
+	// 签名通过后再消费 nonce，避免无效请求耗尽 nonce
+	consumed, err := uc.authRepo.ConsumeNonce(msg.Nonce, now)
+	if err != nil {
+		return nil, fmt.Errorf("校验nonce失败: %w", err)
+	}
+	if !consumed {
+		return nil, fmt.Errorf("%w: nonce 无效、已使用或已过期", ErrInvalidSIWEMessage)
+	}
+
+	expiresAt := now.Add(uc.sessionTTL)
+	if msg.ExpirationTime != nil && msg.ExpirationTime.Before(expiresAt) {
+		expiresAt = *msg.ExpirationTime
+	}
+	tokenID, err := randomHex(16)
+	if err != nil {
+		return nil, fmt.Errorf("生成会话ID失败: %w", err)
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	session := &domain.Session{
+		TokenID:   tokenID,
+		Address:   msg.Address,
+		ChainID:   msg.ChainID,
+		UserAgent: userAgent,
+		ExpiresAt: expiresAt,
+	}
+	if err := uc.authRepo.CreateSession(session); err != nil {
+		return nil, fmt.Errorf("保存会话失败: %w", err)
+	}
+
+	token, err := uc.signToken(jwtClaims{
+		Issuer:    msg.Domain,
+		Subject:   session.Address,
+		ID:        session.TokenID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		ChainID:   session.ChainID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AuthSession{Token: token, Address: session.Address, ChainID: session.ChainID, ExpiresAt: expiresAt}, nil
+}
+
+// 校验 EIP-191 签名，签名者不一致时对合约钱包使用 EIP-1271 校验
+func (uc *AuthUseCase) verifySIWESignature(message, signature string, address common.Address) error {
+	sigBytes, err := hexutil.Decode(signature)
+	if err != nil || len(sigBytes) == 0 {
+		return fmt.Errorf("%w: 签名格式错误", ErrInvalidLoginSignature)
+	}
+	hash := accounts.TextHash([]byte(message))
+
+	if sig, err := parseSignature(signature); err == nil {
+		if pubKey, err := crypto.SigToPub(hash, sig); err == nil && crypto.PubkeyToAddress(*pubKey) == address {
+			return nil
+		}
+	}
+
+	isContract, err := uc.validator.IsContract(address)
+	if err != nil {
+		return fmt.Errorf("检查账户类型失败: %w", err)
+	}
+	if !isContract {
+		return fmt.Errorf("%w: 签名者与登录地址不一致", ErrInvalidLoginSignature)
+	}
+	valid, err := uc.validator.IsValidSignature(address, common.BytesToHash(hash), sigBytes)
+	if err != nil || !valid {
+		return fmt.Errorf("%w: 合约钱包未认可该签名", ErrInvalidLoginSignature)
+	}
+	return nil
+}
+
+// 校验令牌并返回对应的会话，已撤销或已过期的会话视为未登录
+func (uc *AuthUseCase) Authenticate(token string) (*domain.Session, error) {
+	claims, err := uc.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	session, err := uc.authRepo.GetSessionByTokenID(claims.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("获取会话失败: %w", err)
+	}
+	if session.RevokedAt != nil || !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrUnauthorized
+	}
+	return session, nil
+}
+
+// 撤销会话
+func (uc *AuthUseCase) Logout(session *domain.Session) error {
+	return uc.authRepo.RevokeSession(session.TokenID, time.Now())
+}
+
+// 撤销账户的全部会话
+func (uc *AuthUseCase) LogoutAll(address string) error {
+	return uc.authRepo.RevokeSessionsByAddress(address, time.Now())
+}
+
+func (uc *AuthUseCase) GetActiveSessions(address string) ([]domain.Session, error) {
+	return uc.authRepo.GetActiveSessions(address, time.Now())
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// 签发 HS256 JWT
+func (uc *AuthUseCase) signToken(claims jwtClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("编码令牌失败: %w", err)
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(uc.tokenSignature(signingInput)), nil
+}
+
+func (uc *AuthUseCase) tokenSignature(signingInput string) []byte {
+	mac := hmac.New(sha256.New, uc.secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// 校验 JWT 的签名和有效期
+func (uc *AuthUseCase) parseToken(token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrUnauthorized
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, uc.tokenSignature(parts[0]+"."+parts[1])) {
+		return nil, ErrUnauthorized
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" {
+		return nil, ErrUnauthorized
+	}
+	if claims.ExpiresAt <= time.Now().Unix() {
+		return nil, ErrUnauthorized
+	}
+	return &claims, nil
+}
+
+func (uc *AuthUseCase) startNonceCleaner() {
+	ticker := time.NewTicker(nonceCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uc.authRepo.DeleteExpiredNonces(time.Now()); err != nil {
+				log.Printf("清理过期nonce失败: %v", err)
+			}
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
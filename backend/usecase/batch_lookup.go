@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxBatchKeys    = 500 // 批量查询接口单次最多查询的NFT数量
+)
+
+var ErrInvalidBatch = domain.NewError(domain.ErrKindInvalid, "invalid_batch", "无效的批量查询")
+
+// 校验批量查询的NFT列表
+func validateBatchKeys(keys []domain.NFTKey) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: 查询列表为空", ErrInvalidBatch)
+	}
+	if len(keys) > maxBatchKeys {
+		return fmt.Errorf("%w: 单次最多查询 %d 个NFT", ErrInvalidBatch, maxBatchKeys)
+	}
+	for _, key := range keys {
+		if !common.IsHexAddress(key.ContractAddress) {
+			return fmt.Errorf("%w: 无效的合约地址 %s", ErrInvalidBatch, key.ContractAddress)
+		}
+	}
+	return nil
+}
+
+// 规范化分页参数
+func normalizePage(offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return offset, limit
+}
+
+// 规范化合约地址并去重
+func normalizeNFTKeys(keys []domain.NFTKey) []domain.NFTKey {
+	result := make([]domain.NFTKey, 0, len(keys))
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		key.ContractAddress = common.HexToAddress(key.ContractAddress).Hex()
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// 批量获取NFT，结果以 NFTKey.String() 为键，不存在的NFT不出现在结果中
+func (uc *NFTUseCase) GetNFTsByKeys(keys []domain.NFTKey) (map[string]*domain.NFTView, error) {
+	nfts, err := uc.nftRepo.GetNFTsByKeys(normalizeNFTKeys(keys))
+	if err != nil {
+		return nil, err
+	}
+	views := uc.profileUC.DescribeNFTs(nfts)
+	result := make(map[string]*domain.NFTView, len(views))
+	for i := range views {
+		result[domain.NFTKey{ContractAddress: views[i].ContractAddress, TokenID: views[i].TokenID}.String()] = &views[i]
+	}
+	return result, nil
+}
+
+// 批量获取NFT属性，结果以 NFT ID 为键
+func (uc *NFTUseCase) GetAttributesByNFTIDs(nftIDs []uint) (map[uint][]domain.NFTAttribute, error) {
+	attributes, err := uc.nftRepo.GetAttributesByNFTIDs(nftIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]domain.NFTAttribute)
+	for _, attribute := range attributes {
+		result[attribute.NFTID] = append(result[attribute.NFTID], attribute)
+	}
+	return result, nil
+}
+
+// 批量获取NFT转移记录，结果以 NFTKey.String() 为键
+func (uc *NFTUseCase) GetTransfersByKeys(keys []domain.NFTKey) (map[string][]domain.NFTTransferEventView, error) {
+	events, err := uc.nftRepo.GetTransferEventsByKeys(normalizeNFTKeys(keys))
+	if err != nil {
+		return nil, err
+	}
+	views := uc.profileUC.DescribeTransferEvents(events)
+	result := make(map[string][]domain.NFTTransferEventView)
+	for _, view := range views {
+		key := domain.NFTKey{ContractAddress: view.ContractAddress, TokenID: view.TokenID}.String()
+		result[key] = append(result[key], view)
+	}
+	return result, nil
+}
+
+// 批量获取NFT系列，结果以小写合约地址为键
+func (uc *NFTUseCase) GetCollectionsByAddresses(addresses []string) (map[string]*domain.NFTCollection, error) {
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = common.HexToAddress(address).Hex()
+	}
+	collections, err := uc.nftRepo.GetCollectionsByAddresses(normalized)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*domain.NFTCollection, len(collections))
+	for i := range collections {
+		result[strings.ToLower(collections[i].ContractAddress)] = &collections[i]
+	}
+	return result, nil
+}
+
+// 批量获取NFT及其属性，结果包含每个查询的 NFTKey.String()，不存在的NFT对应 nil
+func (uc *NFTUseCase) BatchGetNFTs(keys []domain.NFTKey) (map[string]*domain.NFTView, map[string][]domain.NFTAttribute, error) {
+	if err := validateBatchKeys(keys); err != nil {
+		return nil, nil, err
+	}
+	nfts, err := uc.GetNFTsByKeys(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]uint, 0, len(nfts))
+	for _, nft := range nfts {
+		ids = append(ids, nft.ID)
+	}
+	attributes, err := uc.GetAttributesByNFTIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make(map[string]*domain.NFTView, len(keys))
+	resultAttributes := make(map[string][]domain.NFTAttribute, len(nfts))
+	for _, key := range keys {
+		nft := nfts[key.String()]
+		result[key.String()] = nft
+		if nft != nil {
+			resultAttributes[key.String()] = attributes[nft.ID]
+			if resultAttributes[key.String()] == nil {
+				resultAttributes[key.String()] = []domain.NFTAttribute{}
+			}
+		}
+	}
+	return result, resultAttributes, nil
+}
+
+// 分页获取NFT系列
+func (uc *NFTUseCase) GetCollectionsPage(offset, limit int) ([]domain.NFTCollection, int64, error) {
+	offset, limit = normalizePage(offset, limit)
+	return uc.nftRepo.GetCollectionsPage(offset, limit)
+}
+
+// 分页获取NFT，contractAddress 和 owner 为空时不过滤
+func (uc *NFTUseCase) GetNFTsPage(contractAddress, owner string, filter *domain.TraitFilter, offset, limit int) ([]domain.NFTView, int64, error) {
+	offset, limit = normalizePage(offset, limit)
+	if contractAddress != "" {
+		contractAddress = common.HexToAddress(contractAddress).Hex()
+	}
+	if owner != "" {
+		owner = common.HexToAddress(owner).Hex()
+	}
+	nfts, total, err := uc.nftRepo.GetNFTsPage(contractAddress, owner, filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return uc.profileUC.DescribeNFTs(nfts), total, nil
+}
+
+// 批量获取每个NFT最新的链上订单，结果以 NFTKey.String() 为键，与 GetOrderByNFT 的取法一致
+func (uc *MarketUseCase) GetOrdersByNFTKeys(keys []domain.NFTKey) (map[string]*domain.OrderView, error) {
+	orders, err := uc.repo.GetOrdersByNFTKeys(normalizeNFTKeys(keys))
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]int)
+	for i, order := range orders {
+		latest[domain.NFTKey{ContractAddress: order.NFTContractAddress, TokenID: order.TokenID}.String()] = i
+	}
+	selected := make([]domain.Order, 0, len(latest))
+	for _, i := range latest {
+		selected = append(selected, orders[i])
+	}
+
+	views := uc.tokenUC.DescribeOrders(selected)
+	result := make(map[string]*domain.OrderView, len(views))
+	for i := range views {
+		result[domain.NFTKey{ContractAddress: views[i].NFTContractAddress, TokenID: views[i].TokenID}.String()] = &views[i]
+	}
+	return result, nil
+}
+
+// 批量获取NFT最新的链上订单，结果包含每个查询的 NFTKey.String()，没有订单的NFT对应 nil
+func (uc *MarketUseCase) BatchGetOrders(keys []domain.NFTKey) (map[string]*domain.OrderView, error) {
+	if err := validateBatchKeys(keys); err != nil {
+		return nil, err
+	}
+	orders, err := uc.GetOrdersByNFTKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*domain.OrderView, len(keys))
+	for _, key := range keys {
+		result[key.String()] = orders[key.String()]
+	}
+	return result, nil
+}
+
+// 分页获取链上订单，各条件为空时不过滤
+func (uc *MarketUseCase) GetOrdersPage(contractAddress, seller string, statuses []uint, offset, limit int) ([]domain.OrderView, int64, error) {
+	offset, limit = normalizePage(offset, limit)
+	if contractAddress != "" {
+		contractAddress = common.HexToAddress(contractAddress).Hex()
+	}
+	if seller != "" {
+		seller = common.HexToAddress(seller).Hex()
+	}
+	orders, total, err := uc.repo.GetOrdersPage(contractAddress, seller, statuses, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return uc.tokenUC.DescribeOrders(orders), total, nil
+}
+
+// 按链上订单索引获取订单详情
+func (uc *MarketUseCase) GetOrderViewByID(id uint) (*domain.OrderView, error) {
+	order, err := uc.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.tokenUC.DescribeOrder(order), nil
+}
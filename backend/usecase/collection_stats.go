@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"math/big"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 统计NFT系列的挂单和成交情况，不同支付代币的价格统一换算为美元比较
+func (uc *MarketUseCase) GetCollectionStats(contractAddress string) (*domain.CollectionStats, error) {
+	orders, err := uc.repo.GetOrdersByCollection(common.HexToAddress(contractAddress).Hex())
+	if err != nil {
+		return nil, err
+	}
+	views := uc.tokenUC.DescribeOrders(orders)
+
+	stats := &domain.CollectionStats{
+		ContractAddress: common.HexToAddress(contractAddress).Hex(),
+		Floors:          []domain.TokenFloor{},
+	}
+	floors := make(map[string]*domain.TokenFloor)
+	floorPrices := make(map[string]*big.Int)
+	tokenOrder := make([]string, 0)
+
+	for _, view := range views {
+		switch {
+		case view.Status == 1:
+			stats.SalesCount++
+			if view.PriceUSD != nil {
+				stats.VolumeUSD += *view.PriceUSD
+			} else {
+				stats.UnpricedSales++
+			}
+		case view.Status == 0 && !view.Invalid:
+			stats.ListedCount++
+			price, ok := new(big.Int).SetString(view.Price, 10)
+			if !ok {
+				continue
+			}
+
+			key := strings.ToLower(view.TokenAddress)
+			floor, exists := floors[key]
+			if !exists {
+				floor = &domain.TokenFloor{PaymentToken: view.PaymentToken, TokenAddress: view.TokenAddress}
+				floors[key] = floor
+				tokenOrder = append(tokenOrder, key)
+			}
+			floor.ListedCount++
+			if current, exists := floorPrices[key]; !exists || price.Cmp(current) < 0 {
+				floorPrices[key] = price
+				floor.Price = view.Price
+				floor.PriceFormatted = view.PriceFormatted
+				floor.PriceUSD = view.PriceUSD
+			}
+		}
+	}
+
+	for _, key := range tokenOrder {
+		floor := floors[key]
+		stats.Floors = append(stats.Floors, *floor)
+		if floor.PriceUSD != nil && (stats.FloorPriceUSD == nil || *floor.PriceUSD < *stats.FloorPriceUSD) {
+			stats.FloorPriceUSD = floor.PriceUSD
+		}
+	}
+
+	return stats, nil
+}
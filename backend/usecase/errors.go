@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"errors"
+
+	"backend/contracts/utils"
+	"backend/domain"
+
+	"gorm.io/gorm"
+)
+
+// 将记录不存在转换为对应的领域错误，其他错误（如数据库不可用）原样返回
+func notFoundAs(err error, target *domain.Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return target.Wrap(err)
+	}
+	return err
+}
+
+// 按链上调用失败的原因转换错误：合约拒绝调用时返回 rejected，节点或网络出错时返回 ErrChainUnavailable，
+// 其他错误（如数据库不可用）原样返回
+func chainCallError(err error, rejected error) error {
+	switch {
+	case errors.Is(err, utils.ErrCallRejected):
+		return rejected
+	case errors.Is(err, utils.ErrNodeUnavailable):
+		return domain.ErrChainUnavailable.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"backend/contracts"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// 挂单预检的检查项
+const (
+	ListCheckPrice          = "price"
+	ListCheckSellerOwnsNFT  = "seller_owns_nft"
+	ListCheckMarketApproved = "market_approved"
+	ListCheckPaymentToken   = "payment_token"
+	ListCheckTokenAccepted  = "token_accepted"
+	ListCheckPermitSupport  = "permit_support"
+	ListCheckNotListed      = "not_listed"
+)
+
+// ListingRequest 表示待预检的挂单参数
+type ListingRequest struct {
+	Seller       string
+	NFTAddress   string
+	TokenID      uint
+	TokenAddress string
+	Price        string
+}
+
+// RequiredTransaction 表示挂单前仍需卖家发起的交易
+type RequiredTransaction struct {
+	To          string
+	Method      string
+	Args        []string
+	Data        string
+	Description string
+}
+
+// ListingPreflight 表示 createOrder 的预检结果
+type ListingPreflight struct {
+	PreflightChecks
+	CanList              bool
+	RequiredTransactions []RequiredTransaction
+}
+
+// 检查挂单是否会被 createOrder 拒绝，并给出仍需发起的交易（授权和 createOrder 本身）。
+// 预检无需登录，因此只读：不登记代币，也不缓存市场未知地址的合约实例
+func (uc *MarketUseCase) PreflightListing(req ListingRequest) (*ListingPreflight, error) {
+	preflight := &ListingPreflight{PreflightChecks: newPreflightChecks(), RequiredTransactions: []RequiredTransaction{}}
+	seller := common.HexToAddress(req.Seller)
+	market := uc.contract.Address()
+
+	// 价格
+	price, ok := new(big.Int).SetString(req.Price, 10)
+	priceValid := ok && price.Sign() > 0
+	if priceValid {
+		preflight.addCheck(ListCheckPrice, true, "价格有效")
+	} else {
+		preflight.addCheck(ListCheckPrice, false, "价格必须为大于0的整数")
+	}
+
+	nftContract, releaseNFT, err := uc.nftUC.peekNFTContract(req.NFTAddress)
+	if err != nil {
+		return nil, fmt.Errorf("获取NFT合约实例失败: %w", err)
+	}
+	defer releaseNFT()
+
+	// 所有权
+	owner, ownerErr := nftContract.OwnerOf(req.TokenID)
+	if ownerErr != nil {
+		preflight.addCheck(ListCheckSellerOwnsNFT, false, "NFT不存在或合约不支持ERC-721")
+	} else if common.HexToAddress(owner) != seller {
+		preflight.addCheck(ListCheckSellerOwnsNFT, false, fmt.Sprintf("NFT当前持有者为 %s", owner))
+	} else {
+		preflight.addCheck(ListCheckSellerOwnsNFT, true, "卖家持有该NFT")
+	}
+
+	// 授权：NFT不存在时 getApproved 会回滚，直接判定授权检查失败
+	if ownerErr != nil {
+		preflight.addCheck(ListCheckMarketApproved, false, "NFT不存在，无法检查授权")
+	} else if err := uc.checkMarketApproval(preflight, nftContract, req, seller); err != nil {
+		return nil, err
+	}
+
+	// 支付代币
+	token, releaseToken, err := uc.tokenUC.peekTokenContract(req.TokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("获取代币合约实例失败: %w", err)
+	}
+	defer releaseToken()
+	if paymentToken, err := uc.tokenUC.peekToken(token); err != nil {
+		if !errors.Is(err, ErrTokenUnreadable) {
+			return nil, fmt.Errorf("获取代币信息失败: %w", err)
+		}
+		preflight.addCheck(ListCheckPaymentToken, false, "支付代币不是有效的ERC-20合约")
+	} else {
+		preflight.addCheck(ListCheckPaymentToken, true, "支付代币为ERC-20合约")
+		accepted, err := uc.tokenUC.IsTokenAccepted(paymentToken)
+		if err != nil {
+			return nil, fmt.Errorf("检查代币名单失败: %w", err)
+		}
+		if accepted {
+			preflight.addCheck(ListCheckTokenAccepted, true, "市场接受该支付代币")
+		} else {
+			preflight.addCheck(ListCheckTokenAccepted, false, "市场不接受该支付代币")
+		}
+	}
+	// buyNFT 依赖 permit，不支持 EIP-2612 的代币挂单后无法被购买
+	if _, err := token.Nonces(seller); err != nil {
+		preflight.addCheck(ListCheckPermitSupport, false, "支付代币不支持EIP-2612 permit，订单将无法被购买")
+	} else if _, err := uc.permitDomain(token); err != nil {
+		preflight.addCheck(ListCheckPermitSupport, false, err.Error())
+	} else {
+		preflight.addCheck(ListCheckPermitSupport, true, "支付代币支持EIP-2612 permit")
+	}
+
+	// 重复挂单
+	orders, err := uc.repo.GetActiveOrdersByNFT(req.NFTAddress, req.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("获取NFT未成交订单失败: %w", err)
+	}
+	duplicate := false
+	for _, order := range orders {
+		if common.HexToAddress(order.Seller) == seller && !order.Invalid {
+			preflight.addCheck(ListCheckNotListed, false, fmt.Sprintf("该NFT已有未成交的挂单 (订单ID: %d)", order.ID-1))
+			duplicate = true
+			break
+		}
+	}
+	if !duplicate {
+		preflight.addCheck(ListCheckNotListed, true, "该NFT没有未成交的挂单")
+	}
+
+	// 最后一步为 createOrder 本身
+	if priceValid {
+		tokenID := new(big.Int).SetUint64(uint64(req.TokenID))
+		data, err := uc.contract.PackCall("createOrder", common.HexToAddress(req.NFTAddress), tokenID, common.HexToAddress(req.TokenAddress), price)
+		if err != nil {
+			return nil, fmt.Errorf("编码createOrder调用失败: %w", err)
+		}
+		preflight.RequiredTransactions = append(preflight.RequiredTransactions, RequiredTransaction{
+			To:          market.Hex(),
+			Method:      "createOrder",
+			Args:        []string{common.HexToAddress(req.NFTAddress).Hex(), tokenID.String(), common.HexToAddress(req.TokenAddress).Hex(), price.String()},
+			Data:        hexutil.Encode(data),
+			Description: "创建挂单",
+		})
+	}
+
+	// 授权缺失可通过上面的交易补齐，不影响能否挂单
+	preflight.CanList = true
+	for _, failure := range preflight.Failures {
+		if failure.Name != ListCheckMarketApproved {
+			preflight.CanList = false
+			break
+		}
+	}
+
+	return preflight, nil
+}
+
+// 检查市场合约是否已获该NFT的授权，未授权时追加 approve 交易
+func (uc *MarketUseCase) checkMarketApproval(preflight *ListingPreflight, nftContract *contracts.NFTContract, req ListingRequest, seller common.Address) error {
+	market := uc.contract.Address()
+	approved, err := nftContract.GetApproved(req.TokenID)
+	if err != nil {
+		return fmt.Errorf("获取NFT授权失败: %w", err)
+	}
+	approvedForAll, err := nftContract.IsApprovedForAll(seller, market)
+	if err != nil {
+		return fmt.Errorf("获取NFT全部授权失败: %w", err)
+	}
+	if approved == market || approvedForAll {
+		preflight.addCheck(ListCheckMarketApproved, true, "市场合约已获授权")
+		return nil
+	}
+	preflight.addCheck(ListCheckMarketApproved, false, "市场合约未获授权")
+	tokenID := new(big.Int).SetUint64(uint64(req.TokenID))
+	data, err := nftContract.PackCall("approve", market, tokenID)
+	if err != nil {
+		return fmt.Errorf("编码approve调用失败: %w", err)
+	}
+	preflight.RequiredTransactions = append(preflight.RequiredTransactions, RequiredTransaction{
+		To:          common.HexToAddress(req.NFTAddress).Hex(),
+		Method:      "approve",
+		Args:        []string{market.Hex(), tokenID.String()},
+		Data:        hexutil.Encode(data),
+		Description: "授权市场合约转移该NFT（也可使用 setApprovalForAll 授权全部NFT）",
+	})
+	return nil
+}
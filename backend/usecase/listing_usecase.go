@@ -0,0 +1,498 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"gorm.io/gorm"
+)
+
+// 链下挂单状态
+const (
+	ListingStatusActive    = 0
+	ListingStatusFilled    = 1
+	ListingStatusCancelled = 2
+	ListingStatusExpired   = 3
+)
+
+// 定期检查挂单过期的间隔，卖家持有和授权情况按 orderValidityCheckInterval 全量检查
+const listingExpiryCheckInterval = time.Minute
+
+var (
+	ErrInvalidListing = domain.NewError(domain.ErrKindInvalid, "invalid_listing", "无效的挂单")
+	ErrListingExists  = domain.NewError(domain.ErrKindConflict, "listing_exists", "挂单已存在")
+)
+
+var listingFields = []apitypes.Type{
+	{Name: "seller", Type: "address"},
+	{Name: "nft", Type: "address"},
+	{Name: "tokenId", Type: "uint256"},
+	{Name: "paymentToken", Type: "address"},
+	{Name: "price", Type: "uint256"},
+	{Name: "nonce", Type: "uint256"},
+	{Name: "salt", Type: "uint256"},
+	{Name: "expiry", Type: "uint256"},
+}
+
+var increaseNonceFields = []apitypes.Type{
+	{Name: "seller", Type: "address"},
+	{Name: "nonce", Type: "uint256"},
+}
+
+// SignedListingRequest 表示卖家提交的链下挂单参数
+type SignedListingRequest struct {
+	Seller       string
+	NFTAddress   string
+	TokenID      uint
+	TokenAddress string
+	Price        string
+	Nonce        uint64
+	Salt         string
+	Expiry       uint64
+	Signature    string
+}
+
+// ListingTypedData 表示待卖家签名的挂单或 nonce 数据
+type ListingTypedData struct {
+	Hash      string
+	TypedData TypedDataJSON // 可直接用于 eth_signTypedData_v4
+}
+
+// SignerNonceInfo 表示卖家当前的 nonce，以及将其加一（取消全部已签名挂单）需要签名的数据
+type SignerNonceInfo struct {
+	Seller        string
+	Nonce         uint64
+	IncreaseNonce ListingTypedData
+}
+
+// ListingFill 表示结算合约成交链下挂单的事件
+type ListingFill struct {
+	ListingHash     string
+	Buyer           string
+	TransactionHash string
+	BlockNumber     uint64
+}
+
+// ListingSettlement 是链上结算合约的适配接口，结算合约部署后实现该接口并通过 AttachSettlement 接入
+type ListingSettlement interface {
+	WatchFills(ctx context.Context, fills chan<- ListingFill) error
+}
+
+type ListingUseCase struct {
+	listingRepo *repository.ListingRepository
+	marketUC    *MarketUseCase
+	tokenUC     *TokenUseCase
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewListingUseCase(listingRepo *repository.ListingRepository, marketUC *MarketUseCase, tokenUC *TokenUseCase) *ListingUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	uc := &ListingUseCase{
+		listingRepo: listingRepo,
+		marketUC:    marketUC,
+		tokenUC:     tokenUC,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	marketUC.listingUC = uc
+
+	return uc
+}
+
+// Start 启动挂单检查协程，需在所有用例构造完成后调用
+func (uc *ListingUseCase) Start() {
+	go uc.startListingChecker()
+}
+
+func (uc *ListingUseCase) Close() {
+	uc.cancel()
+}
+
+// 校验挂单参数并构建签名数据
+func (uc *ListingUseCase) listingTypedData(req SignedListingRequest) (apitypes.TypedData, error) {
+	if !common.IsHexAddress(req.Seller) || !common.IsHexAddress(req.NFTAddress) || !common.IsHexAddress(req.TokenAddress) {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 地址格式错误", ErrInvalidListing)
+	}
+	price, ok := new(big.Int).SetString(req.Price, 10)
+	if !ok || price.Sign() <= 0 {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 价格必须为大于0的整数", ErrInvalidListing)
+	}
+	salt, ok := new(big.Int).SetString(req.Salt, 10)
+	if !ok || salt.Sign() < 0 {
+		return apitypes.TypedData{}, fmt.Errorf("%w: salt必须为非负整数", ErrInvalidListing)
+	}
+	if req.Expiry <= uint64(time.Now().Unix()) {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 过期时间必须晚于当前时间", ErrInvalidListing)
+	}
+
+	return uc.marketUC.marketTypedData("Listing", listingFields, apitypes.TypedDataMessage{
+		"seller":       common.HexToAddress(req.Seller).Hex(),
+		"nft":          common.HexToAddress(req.NFTAddress).Hex(),
+		"tokenId":      new(big.Int).SetUint64(uint64(req.TokenID)).String(),
+		"paymentToken": common.HexToAddress(req.TokenAddress).Hex(),
+		"price":        price.String(),
+		"nonce":        new(big.Int).SetUint64(req.Nonce).String(),
+		"salt":         salt.String(),
+		"expiry":       new(big.Int).SetUint64(req.Expiry).String(),
+	}), nil
+}
+
+// 构建待签名的挂单数据
+func (uc *ListingUseCase) BuildListingTypedData(req SignedListingRequest) (*ListingTypedData, error) {
+	typedData, err := uc.listingTypedData(req)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &ListingTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)}, nil
+}
+
+// 校验签名、nonce、支付代币以及卖家的持有和授权情况后保存挂单
+func (uc *ListingUseCase) SubmitListing(req SignedListingRequest) (*domain.ListingView, error) {
+	typedData, err := uc.listingTypedData(req)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := verifyTypedDataSigner(typedData, req.Signature, req.Seller)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.listingRepo.GetListingByHash(hash.Hex()); err == nil {
+		return nil, ErrListingExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	seller := common.HexToAddress(req.Seller).Hex()
+	nonce, err := uc.listingRepo.GetSignerNonce(seller)
+	if err != nil {
+		return nil, fmt.Errorf("获取卖家nonce失败: %w", err)
+	}
+	if req.Nonce < nonce {
+		return nil, fmt.Errorf("%w: nonce已失效，当前nonce为 %d", ErrInvalidListing, nonce)
+	}
+
+	paymentToken, err := uc.tokenUC.EnsureToken(req.TokenAddress)
+	if err != nil {
+		return nil, chainCallError(err, fmt.Errorf("%w: 支付代币不是有效的ERC-20合约", ErrInvalidListing))
+	}
+	accepted, err := uc.tokenUC.IsTokenAccepted(paymentToken)
+	if err != nil {
+		return nil, fmt.Errorf("检查代币名单失败: %w", err)
+	}
+	if !accepted {
+		return nil, fmt.Errorf("%w: 市场不接受该支付代币", ErrInvalidListing)
+	}
+
+	signature, err := normalizeSignature(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	listing := &domain.Listing{
+		Hash:               hash.Hex(),
+		Seller:             seller,
+		NFTContractAddress: common.HexToAddress(req.NFTAddress).Hex(),
+		TokenID:            req.TokenID,
+		TokenAddress:       common.HexToAddress(req.TokenAddress).Hex(),
+		Price:              typedData.Message["price"].(string),
+		Nonce:              req.Nonce,
+		Salt:               typedData.Message["salt"].(string),
+		Expiry:             time.Unix(int64(req.Expiry), 0),
+		Signature:          signature,
+		Status:             ListingStatusActive,
+	}
+
+	_, reason, err := uc.checkListingValidity(listing)
+	if err != nil {
+		return nil, chainCallError(err, fmt.Errorf("%w: NFT不存在或合约不支持ERC-721", ErrInvalidListing))
+	}
+	switch reason {
+	case OrderInvalidReasonNotOwner:
+		return nil, fmt.Errorf("%w: 卖家未持有该NFT", ErrInvalidListing)
+	case OrderInvalidReasonNotApproved:
+		return nil, fmt.Errorf("%w: 市场合约未获授权", ErrInvalidListing)
+	}
+
+	if err := uc.listingRepo.CreateListing(listing); err != nil {
+		return nil, fmt.Errorf("保存挂单失败: %w", err)
+	}
+	if uc.marketUC.watchlistUC != nil {
+		uc.marketUC.watchlistUC.recordListing(listingActivityReference(listing.Hash), listing.NFTContractAddress, listing.TokenID, listing.TokenAddress, listing.Price, listing.Seller)
+	}
+	uc.marketUC.alertUC.evaluateListing(listingActivityReference(listing.Hash), listing.NFTContractAddress, listing.TokenID, listing.TokenAddress, listing.Price, listing.Seller)
+	return uc.tokenUC.DescribeListing(listing), nil
+}
+
+// 与链上订单相同，检查卖家是否仍持有NFT且市场合约仍有转移权限
+func (uc *ListingUseCase) checkListingValidity(listing *domain.Listing) (bool, string, error) {
+	return uc.marketUC.checkOrderValidity(&domain.Order{
+		NFTContractAddress: listing.NFTContractAddress,
+		TokenID:            listing.TokenID,
+		Seller:             listing.Seller,
+	})
+}
+
+func (uc *ListingUseCase) GetListing(hash string) (*domain.ListingView, error) {
+	listing, err := uc.listingRepo.GetListingByHash(hash)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrListingNotFound)
+	}
+	return uc.tokenUC.DescribeListing(listing), nil
+}
+
+func (uc *ListingUseCase) GetListings(seller string, statuses []uint) ([]domain.ListingView, error) {
+	if seller != "" {
+		seller = common.HexToAddress(seller).Hex()
+	}
+	listings, err := uc.listingRepo.GetListings(seller, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return uc.tokenUC.DescribeListings(listings), nil
+}
+
+// 将有效和已成交的链下挂单转换为订单，用于和链上订单一起返回
+func (uc *ListingUseCase) listingOrderViews() ([]domain.OrderView, error) {
+	listings, err := uc.GetListings("", []uint{ListingStatusActive, ListingStatusFilled})
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]domain.OrderView, len(listings))
+	for i := range listings {
+		listing := &listings[i].Listing
+		status := uint(0)
+		if listing.Status == ListingStatusFilled {
+			status = 1
+		}
+		views[i] = domain.OrderView{
+			Order: domain.Order{
+				NFTContractAddress: listing.NFTContractAddress,
+				TokenID:            listing.TokenID,
+				TokenAddress:       listing.TokenAddress,
+				Price:              listing.Price,
+				Seller:             listing.Seller,
+				Status:             status,
+				Invalid:            listing.Invalid,
+				InvalidReason:      listing.InvalidReason,
+			},
+			Source:         domain.OrderSourceSigned,
+			Listing:        listing,
+			PaymentToken:   listings[i].PaymentToken,
+			PriceFormatted: listings[i].PriceFormatted,
+			PriceUSD:       listings[i].PriceUSD,
+			SellerProfile:  listings[i].SellerProfile,
+		}
+	}
+	return views, nil
+}
+
+func (uc *ListingUseCase) increaseNonceTypedData(seller string, nonce uint64) apitypes.TypedData {
+	return uc.marketUC.marketTypedData("IncreaseNonce", increaseNonceFields, apitypes.TypedDataMessage{
+		"seller": common.HexToAddress(seller).Hex(),
+		"nonce":  new(big.Int).SetUint64(nonce).String(),
+	})
+}
+
+// 获取卖家当前的 nonce
+func (uc *ListingUseCase) GetSignerNonce(seller string) (*SignerNonceInfo, error) {
+	seller = common.HexToAddress(seller).Hex()
+	nonce, err := uc.listingRepo.GetSignerNonce(seller)
+	if err != nil {
+		return nil, err
+	}
+
+	typedData := uc.increaseNonceTypedData(seller, nonce+1)
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &SignerNonceInfo{
+		Seller:        seller,
+		Nonce:         nonce,
+		IncreaseNonce: ListingTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)},
+	}, nil
+}
+
+// 校验卖家签名后提高其 nonce，取消之前签名的全部挂单
+func (uc *ListingUseCase) IncreaseNonceWithSignature(seller string, nonce uint64, signature string) (*SignerNonceInfo, error) {
+	if _, err := verifyTypedDataSigner(uc.increaseNonceTypedData(seller, nonce), signature, seller); err != nil {
+		return nil, err
+	}
+	if err := uc.IncreaseNonce(seller, nonce); err != nil {
+		return nil, err
+	}
+	return uc.GetSignerNonce(seller)
+}
+
+// 提高卖家的 nonce 并取消 nonce 更小的挂单，结算合约的 nonce 事件也通过此方法同步
+func (uc *ListingUseCase) IncreaseNonce(seller string, nonce uint64) error {
+	seller = common.HexToAddress(seller).Hex()
+	increased, err := uc.listingRepo.IncreaseSignerNonce(seller, nonce)
+	if err != nil {
+		return fmt.Errorf("更新卖家nonce失败: %w", err)
+	}
+	if !increased {
+		return fmt.Errorf("%w: 新的nonce必须大于当前nonce", ErrInvalidListing)
+	}
+	if _, err := uc.listingRepo.CancelListingsBelowNonce(seller, nonce); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+	return nil
+}
+
+// 接入链上结算合约，成交事件到达时更新挂单状态
+func (uc *ListingUseCase) AttachSettlement(settlement ListingSettlement) error {
+	fills := make(chan ListingFill)
+	if err := settlement.WatchFills(uc.ctx, fills); err != nil {
+		return fmt.Errorf("监听结算事件失败: %w", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case fill := <-fills:
+				if err := uc.HandleFill(fill); err != nil {
+					log.Printf("处理挂单成交失败: %v", err)
+				}
+			case <-uc.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// 将挂单标记为已成交
+func (uc *ListingUseCase) HandleFill(fill ListingFill) error {
+	rows, err := uc.listingRepo.MarkListingFilled(fill.ListingHash, common.HexToAddress(fill.Buyer).Hex(), fill.TransactionHash)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("挂单不存在或已失效: %s", fill.ListingHash)
+	}
+	if listing, err := uc.listingRepo.GetListingByHash(fill.ListingHash); err == nil {
+		if uc.marketUC.watchlistUC != nil {
+			uc.marketUC.watchlistUC.recordSale(listingActivityReference(listing.Hash), listing.NFTContractAddress, listing.TokenID, listing.TokenAddress, listing.Price, listing.Seller, listing.Buyer, listing.FillTransaction, time.Now())
+		}
+		uc.marketUC.alertUC.evaluateSale(listingActivityReference(listing.Hash), listing.NFTContractAddress, listing.TokenID, listing.TokenAddress, listing.Price, listing.Seller, listing.Buyer)
+		uc.marketUC.notificationUC.notifySale(listingActivityReference(listing.Hash), listing.NFTContractAddress, listing.TokenID, listing.TokenAddress, listing.Price, listing.Seller, listing.Buyer, listing.FillTransaction)
+	}
+	return nil
+}
+
+func (uc *ListingUseCase) startListingChecker() {
+	expiryTicker := time.NewTicker(listingExpiryCheckInterval)
+	defer expiryTicker.Stop()
+	validityTicker := time.NewTicker(orderValidityCheckInterval)
+	defer validityTicker.Stop()
+
+	for {
+		select {
+		case <-expiryTicker.C:
+			if _, err := uc.listingRepo.ExpireListings(time.Now()); err != nil {
+				log.Printf("更新过期挂单失败: %v", err)
+			}
+		case <-validityTicker.C:
+			if err := uc.RevalidateActiveListings(); err != nil {
+				log.Printf("检查挂单有效性失败: %v", err)
+			}
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+// 检查所有有效挂单的卖家持有和授权情况
+func (uc *ListingUseCase) RevalidateActiveListings() error {
+	listings, err := uc.listingRepo.GetActiveListings()
+	if err != nil {
+		return fmt.Errorf("获取有效挂单失败: %w", err)
+	}
+	for i := range listings {
+		if uc.ctx.Err() != nil {
+			return nil
+		}
+		uc.revalidateListing(&listings[i])
+	}
+	return nil
+}
+
+// NFT发生转移或授权变化后，重新检查该NFT的有效挂单
+func (uc *ListingUseCase) RevalidateNFTListings(contractAddress string, tokenID uint) {
+	listings, err := uc.listingRepo.GetActiveListingsByNFT(common.HexToAddress(contractAddress).Hex(), tokenID)
+	if err != nil {
+		log.Printf("获取NFT有效挂单失败 (地址: %s, TokenID: %d): %v", contractAddress, tokenID, err)
+		return
+	}
+	for i := range listings {
+		uc.revalidateListing(&listings[i])
+	}
+}
+
+// 卖家修改 ApprovalForAll 后，重新检查其在该合约下的有效挂单
+func (uc *ListingUseCase) RevalidateSellerListings(contractAddress, seller string) {
+	listings, err := uc.listingRepo.GetActiveListingsBySeller(common.HexToAddress(contractAddress).Hex(), common.HexToAddress(seller).Hex())
+	if err != nil {
+		log.Printf("获取卖家有效挂单失败 (地址: %s, 卖家: %s): %v", contractAddress, seller, err)
+		return
+	}
+	for i := range listings {
+		uc.revalidateListing(&listings[i])
+	}
+}
+
+func (uc *ListingUseCase) revalidateListing(listing *domain.Listing) {
+	invalid, reason, err := uc.checkListingValidity(listing)
+	if err != nil {
+		log.Printf("检查挂单有效性失败 (挂单: %s): %v", listing.Hash, err)
+		return
+	}
+	if invalid == listing.Invalid && reason == listing.InvalidReason {
+		return
+	}
+	if err := uc.listingRepo.UpdateListingValidity(listing.ID, invalid, reason); err != nil {
+		log.Printf("更新挂单有效性失败 (挂单: %s): %v", listing.Hash, err)
+		return
+	}
+	listing.Invalid = invalid
+	listing.InvalidReason = reason
+}
+
+// 解析以逗号分隔的挂单状态名称
+func ParseListingStatuses(raw string) ([]uint, error) {
+	names := map[string]uint{
+		"active":    ListingStatusActive,
+		"filled":    ListingStatusFilled,
+		"cancelled": ListingStatusCancelled,
+		"expired":   ListingStatusExpired,
+	}
+	statuses := make([]uint, 0)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		status, exists := names[name]
+		if !exists {
+			return nil, fmt.Errorf("%w: 未知的挂单状态 %s", ErrInvalidListing, name)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// 市场链下签名（出价、挂单等）使用的 EIP-712 签名域名称和版本
+const (
+	marketSigningName    = "NFTMarket"
+	marketSigningVersion = "1"
+)
+
+var marketDomainType = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+}
+
+// 市场签名域，verifyingContract 为市场合约地址，以便将来由链上结算合约校验
+func (uc *MarketUseCase) marketDomain() apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              marketSigningName,
+		Version:           marketSigningVersion,
+		ChainId:           (*math.HexOrDecimal256)(uc.chainID),
+		VerifyingContract: uc.contract.Address().Hex(),
+	}
+}
+
+// 构建市场签名数据，types 中只需声明主类型
+func (uc *MarketUseCase) marketTypedData(primaryType string, fields []apitypes.Type, message apitypes.TypedDataMessage) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": marketDomainType,
+			primaryType:    fields,
+		},
+		PrimaryType: primaryType,
+		Domain:      uc.marketDomain(),
+		Message:     message,
+	}
+}
+
+// 计算签名数据哈希并校验签名者
+func verifyTypedDataSigner(typedData apitypes.TypedData, signature string, signer string) (common.Hash, error) {
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != common.HexToAddress(signer) {
+		return common.Hash{}, fmt.Errorf("%w: 签名者不一致", ErrInvalidSignature)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// 计算签名数据哈希
+func typedDataHash(typedData apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// 规范化签名为 v 为 27/28 的十六进制字符串
+func normalizeSignature(signature string) (string, error) {
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return "", err
+	}
+	sig[64] += 27
+	return hexutil.Encode(sig), nil
+}
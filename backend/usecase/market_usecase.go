@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,41 +16,72 @@ import (
 )
 
 type MarketUseCase struct {
-	repo     *repository.MarketRepository
-	nftRepo  *repository.NFTRepository
-	contract *contracts.NFTMarketContract
-	nftUC    *NFTUseCase
-	ctx      context.Context
-	cancel   context.CancelFunc
+	repo           *repository.MarketRepository
+	nftRepo        *repository.NFTRepository
+	historyRepo    *repository.PriceHistoryRepository
+	contract       *contracts.NFTMarketContract
+	chainID        *big.Int
+	nftUC          *NFTUseCase
+	search         *SearchUseCase
+	tokenUC        *TokenUseCase
+	listingUC      *ListingUseCase      // 由 NewListingUseCase 设置，用于返回链下挂单和同步检查挂单有效性
+	profileUC      *ProfileUseCase      // 由 NewProfileUseCase 设置，用于在溯源中附加持有者资料
+	watchlistUC    *WatchlistUseCase    // 由 NewWatchlistUseCase 设置，用于记录挂单、降价和成交动态
+	webhookUC      *WebhookUseCase      // 由 NewWebhookUseCase 设置，用于推送订单事件
+	alertUC        *AlertUseCase        // 由 NewAlertUseCase 设置，用于在挂单、成交和出价时触发提醒
+	notificationUC *NotificationUseCase // 由 NewNotificationUseCase 设置，用于生成成交、撤单和出价通知
+	historyMutex   sync.Mutex           // 保证成交记录和K线的更新不会交错
+	ctx            context.Context
+	cancel         context.CancelFunc
 }
 
-func NewMarketUseCase(repo *repository.MarketRepository, nftRepo *repository.NFTRepository, nftUC *NFTUseCase, ethClientURL, contractAddress string) (*MarketUseCase, error) {
+func NewMarketUseCase(repo *repository.MarketRepository, nftRepo *repository.NFTRepository, historyRepo *repository.PriceHistoryRepository, nftUC *NFTUseCase, search *SearchUseCase, tokenUC *TokenUseCase, ethClientURL, contractAddress string) (*MarketUseCase, error) {
 	contract, err := contracts.NewNFTMarketContract(ethClientURL, contractAddress)
 	if err != nil {
 		return nil, fmt.Errorf("创建NFTMarketContract失败: %w", err)
 	}
+	chainID, err := contract.ChainID()
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", err)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	uc := &MarketUseCase{
-		repo:     repo,
-		nftRepo:  nftRepo,
-		contract: contract,
-		nftUC:    nftUC,
-		ctx:      ctx,
-		cancel:   cancel,
+		repo:        repo,
+		nftRepo:     nftRepo,
+		historyRepo: historyRepo,
+		contract:    contract,
+		chainID:     chainID,
+		nftUC:       nftUC,
+		search:      search,
+		tokenUC:     tokenUC,
+		ctx:         ctx,
+		cancel:      cancel,
 	}
+	nftUC.marketUC = uc
+
+	return uc, nil
+}
 
+// Start 加载订单和NFT数据并启动事件监听。初始化过程会启动NFT合约的事件监听，
+// 必须在所有用例构造完成、相互引用设置好之后调用，避免监听协程读到未设置的字段
+func (uc *MarketUseCase) Start() error {
 	// 初始化数据库
 	if err := uc.InitializeOrders(); err != nil {
-		cancel()
-		return nil, fmt.Errorf("初始化订单数据失败: %w", err)
+		return fmt.Errorf("初始化订单数据失败: %w", err)
+	}
+	// 初始化会清空并重新加载订单等数据，完成后再构建搜索索引
+	if err := uc.search.Rebuild(); err != nil {
+		log.Printf("构建搜索索引失败: %v", err)
 	}
 
 	// 启动事件监听协程
 	go uc.startEventListener()
+	// 启动订单有效性检查协程
+	go uc.startValidityChecker()
 
-	return uc, nil
+	return nil
 }
 
 func (uc *MarketUseCase) startEventListener() {
@@ -78,11 +110,29 @@ func (uc *MarketUseCase) Close() {
 }
 
 func (uc *MarketUseCase) GetOrderByID(id uint) (*domain.Order, error) {
-	return uc.repo.GetOrderByID(id + 1)
+	order, err := uc.repo.GetOrderByID(id + 1)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrOrderNotFound)
+	}
+	return order, nil
 }
 
-func (uc *MarketUseCase) GetAllOrders() ([]domain.Order, error) {
-	return uc.repo.GetAllOrders()
+// 获取所有链上订单，以及有效和已成交的链下签名挂单
+func (uc *MarketUseCase) GetAllOrders() ([]domain.OrderView, error) {
+	orders, err := uc.repo.GetAllOrders()
+	if err != nil {
+		return nil, err
+	}
+	views := uc.tokenUC.DescribeOrders(orders)
+
+	if uc.listingUC != nil {
+		listings, err := uc.listingUC.listingOrderViews()
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, listings...)
+	}
+	return views, nil
 }
 
 func (uc *MarketUseCase) InitializeOrders() error {
@@ -98,6 +148,9 @@ func (uc *MarketUseCase) InitializeOrders() error {
 	if err := uc.nftRepo.ClearNFTAttributes(); err != nil {
 		return fmt.Errorf("清空 NFT 属性表失败: %w", err)
 	}
+	if err := uc.nftRepo.ClearNFTRarities(); err != nil {
+		return fmt.Errorf("清空 NFT 稀有度表失败: %w", err)
+	}
 
 	// 清空事件转移表
 	if err := uc.nftRepo.ClearNFTTransferEvents(); err != nil {
@@ -118,9 +171,24 @@ func (uc *MarketUseCase) InitializeOrders() error {
 		return fmt.Errorf("批量插入订单失败: %w", err)
 	}
 
-	// 收集所有涉及到的NFT合约地址
+	// 重建挂单和撤单事件，用于NFT溯源
+	if err := uc.scanHistoricalOrderEvents(orders); err != nil {
+		log.Printf("重建订单事件失败: %v", err)
+	}
+
+	// 收集所有涉及到的NFT合约地址和支付代币
+	paymentTokens := make(map[string]bool)
 	for _, order := range orders {
 		nftContracts[order.NFTContractAddress] = true
+		paymentTokens[order.TokenAddress] = true
+		uc.search.IndexAddress(order.Seller)
+	}
+
+	// 登记支付代币
+	for tokenAddress := range paymentTokens {
+		if _, err := uc.tokenUC.EnsureToken(tokenAddress); err != nil {
+			log.Printf("登记支付代币失败 (地址: %s): %v", tokenAddress, err)
+		}
 	}
 
 	// 获取现有的 NFT 集合
@@ -139,15 +207,30 @@ func (uc *MarketUseCase) InitializeOrders() error {
 		// 初始化NFT合约
 		if err := uc.nftUC.InitializeNFTCollection(contractAddress); err != nil {
 			log.Printf("初始化NFT合约失败 (地址: %s): %v", contractAddress, err)
+			uc.nftUC.EnqueueRetry(RetryJobTypeCollection, contractAddress, 0, err)
 			// 继续初始化其他合约，不中断整个过程
 		}
 	}
 
+	// 重建成交记录和价格K线
+	if err := uc.scanHistoricalSales(); err != nil {
+		log.Printf("重建价格历史失败: %v", err)
+	}
+
+	// 检查链上订单当前是否仍可成交
+	if err := uc.RevalidateActiveOrders(); err != nil {
+		log.Printf("检查订单有效性失败: %v", err)
+	}
+
 	return nil
 }
 
-func (uc *MarketUseCase) GetOrderByNFT(contractAddress string, tokenID uint) (*domain.Order, error) {
-	return uc.repo.GetOrderByNFT(contractAddress, tokenID)
+func (uc *MarketUseCase) GetOrderByNFT(contractAddress string, tokenID uint) (*domain.OrderView, error) {
+	order, err := uc.repo.GetOrderByNFT(contractAddress, tokenID)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrOrderNotFound)
+	}
+	return uc.tokenUC.DescribeOrder(order), nil
 }
 
 // 定义事件签名常量
@@ -183,7 +266,6 @@ func (uc *MarketUseCase) handleOrderCreated(event *types.Log) error {
 	price := new(big.Int).SetBytes(data[32:64])
 	seller := common.BytesToAddress(data[64:])
 
-
 	order := domain.Order{
 		ID:                 uint(orderId + 1),
 		NFTContractAddress: nftAddress.Hex(),
@@ -194,17 +276,65 @@ func (uc *MarketUseCase) handleOrderCreated(event *types.Log) error {
 		Status:             0,
 	}
 
-	return uc.repo.BatchInsertOrders([]domain.Order{order})
+	if err := uc.repo.BatchInsertOrders([]domain.Order{order}); err != nil {
+		return err
+	}
+
+	uc.recordOrderEvent(event, &order)
+	uc.search.IndexAddress(order.Seller)
+	if _, err := uc.tokenUC.EnsureToken(order.TokenAddress); err != nil {
+		log.Printf("登记支付代币失败 (地址: %s): %v", order.TokenAddress, err)
+	}
+	if uc.watchlistUC != nil {
+		uc.watchlistUC.recordListing(orderActivityReference(order.ID), order.NFTContractAddress, order.TokenID, order.TokenAddress, order.Price, order.Seller)
+	}
+	uc.alertUC.evaluateListing(orderActivityReference(order.ID), order.NFTContractAddress, order.TokenID, order.TokenAddress, order.Price, order.Seller)
+	uc.webhookUC.dispatch(webhookEvent{
+		Type:       WebhookEventOrderCreated,
+		Collection: order.NFTContractAddress,
+		Addresses:  []string{order.Seller},
+		Data:       uc.tokenUC.DescribeOrder(&order),
+	})
+	return nil
 }
 
 func (uc *MarketUseCase) handleOrderCancelled(event *types.Log) error {
 	orderId := new(big.Int).SetBytes(event.Topics[1].Bytes()).Uint64()
-	return uc.repo.UpdateOrderStatus(uint(orderId+1), 2)
+	if err := uc.repo.UpdateOrderStatus(uint(orderId+1), 2); err != nil {
+		return err
+	}
+	if order, err := uc.repo.GetOrderByID(uint(orderId + 1)); err == nil {
+		uc.recordOrderEvent(event, order)
+		uc.webhookUC.dispatch(webhookEvent{
+			Type:       WebhookEventOrderCancelled,
+			Collection: order.NFTContractAddress,
+			Addresses:  []string{order.Seller},
+			Data:       uc.tokenUC.DescribeOrder(order),
+		})
+		uc.notificationUC.notifyOrderCancelled(order, event.TxHash.Hex())
+	}
+	return nil
 }
 
 func (uc *MarketUseCase) handleOrderFulfilled(event *types.Log) error {
 	orderId := new(big.Int).SetBytes(event.Topics[1].Bytes()).Uint64()
-	return uc.repo.UpdateOrderStatus(uint(orderId+1), 1)
+	if err := uc.repo.UpdateOrderStatus(uint(orderId+1), 1); err != nil {
+		return err
+	}
+	if order, err := uc.repo.GetOrderByID(uint(orderId + 1)); err == nil {
+		buyer := common.BytesToAddress(event.Data[:32]).Hex()
+		uc.webhookUC.dispatch(webhookEvent{
+			Type:       WebhookEventOrderFulfilled,
+			Collection: order.NFTContractAddress,
+			Addresses:  []string{order.Seller, buyer},
+			Data: OrderFulfilledData{
+				Order:           uc.tokenUC.DescribeOrder(order),
+				Buyer:           buyer,
+				TransactionHash: event.TxHash.Hex(),
+			},
+		})
+	}
+	return uc.recordSale(event)
 }
 
 func (uc *MarketUseCase) handleNFTContractDeployed(event *types.Log) error {
@@ -212,6 +342,7 @@ func (uc *MarketUseCase) handleNFTContractDeployed(event *types.Log) error {
 
 	// 初始化NFT合约
 	if err := uc.nftUC.InitializeNFTCollection(nftAddress.Hex()); err != nil {
+		uc.nftUC.EnqueueRetry(RetryJobTypeCollection, nftAddress.Hex(), 0, err)
 		return fmt.Errorf("初始化NFT合约失败: %w", err)
 	}
 
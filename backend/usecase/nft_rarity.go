@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"backend/domain"
+)
+
+// 缺失属性在统计中使用的取值
+const missingTraitValue = "<missing>"
+
+// 铸造或元数据更新后延迟重算稀有度，合并短时间内的多次触发
+const rarityRecomputeDelay = 10 * time.Second
+
+// 安排重算NFT系列的稀有度。
+// 稀有度无法只按变化的属性增量更新：铸造改变了系列总数，所有属性取值的频率随之变化，
+// 每个NFT的分数和排名都可能改变。因此每次仍整体重算并替换系列的全部记录，
+// 由防抖把批量铸造或元数据刷新期间的多次触发合并为一次，重算的次数取决于触发的停顿而不是NFT数量
+func (uc *NFTUseCase) scheduleRarityRecompute(contractAddress string) {
+	uc.rarityRecomputes.trigger(contractAddress)
+}
+
+func (uc *NFTUseCase) recomputeScheduledRarity(contractAddress string) {
+	if uc.ctx.Err() != nil {
+		return
+	}
+	if err := uc.RecomputeCollectionRarity(contractAddress); err != nil {
+		log.Printf("重算稀有度失败 (地址: %s): %v", contractAddress, err)
+	}
+}
+
+// debouncer 按 key 合并短时间内的多次触发：最后一次触发 delay 之后执行一次 run
+type debouncer struct {
+	delay  time.Duration
+	run    func(key string)
+	timers map[string]*time.Timer
+	mutex  sync.Mutex
+}
+
+func newDebouncer(delay time.Duration, run func(key string)) *debouncer {
+	return &debouncer{delay: delay, run: run, timers: make(map[string]*time.Timer)}
+}
+
+func (d *debouncer) trigger(key string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// 计时器已触发但回调尚未执行时 Stop 返回 false，此时另建计时器，避免重置已触发的计时器导致重复执行
+	if timer, exists := d.timers[key]; exists && timer.Stop() {
+		timer.Reset(d.delay)
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d.delay, func() {
+		d.mutex.Lock()
+		if d.timers[key] == timer {
+			delete(d.timers, key)
+		}
+		d.mutex.Unlock()
+		d.run(key)
+	})
+	d.timers[key] = timer
+}
+
+// 重新计算NFT系列中每个NFT的稀有度分数和排名
+func (uc *NFTUseCase) RecomputeCollectionRarity(contractAddress string) error {
+	collection, err := uc.nftRepo.GetCollectionByAddress(contractAddress)
+	if err != nil {
+		return fmt.Errorf("获取NFT集合失败: %w", err)
+	}
+
+	nfts, err := uc.nftRepo.GetNFTsByCollectionID(collection.ID)
+	if err != nil {
+		return fmt.Errorf("获取NFT列表失败: %w", err)
+	}
+
+	attributes, err := uc.nftRepo.GetAttributesByCollectionID(collection.ID)
+	if err != nil {
+		return fmt.Errorf("获取NFT属性失败: %w", err)
+	}
+
+	rarities := computeRarities(collection.ID, nfts, attributes)
+	if err := uc.nftRepo.ReplaceCollectionRarities(collection.ID, rarities); err != nil {
+		return fmt.Errorf("保存稀有度失败: %w", err)
+	}
+
+	return nil
+}
+
+// 获取NFT的稀有度
+func (uc *NFTUseCase) GetNFTRarity(nftID uint) (*domain.NFTRarity, error) {
+	return uc.nftRepo.GetRarityByNFTID(nftID)
+}
+
+// 根据属性频率计算稀有度，NFT缺少某属性类型时视为取值 missingTraitValue
+func computeRarities(collectionID uint, nfts []domain.NFT, attributes []domain.NFTAttribute) []domain.NFTRarity {
+	total := float64(len(nfts))
+	if total == 0 {
+		return nil
+	}
+
+	// 统计每个属性类型下各取值的数量
+	valueCounts := make(map[string]map[string]int)
+	traitsByNFT := make(map[uint]map[string]string)
+	for _, attr := range attributes {
+		if valueCounts[attr.TraitType] == nil {
+			valueCounts[attr.TraitType] = make(map[string]int)
+		}
+		valueCounts[attr.TraitType][attr.Value]++
+		if traitsByNFT[attr.NFTID] == nil {
+			traitsByNFT[attr.NFTID] = make(map[string]string)
+		}
+		traitsByNFT[attr.NFTID][attr.TraitType] = attr.Value
+	}
+	for _, counts := range valueCounts {
+		present := 0
+		for _, count := range counts {
+			present += count
+		}
+		if missing := len(nfts) - present; missing > 0 {
+			counts[missingTraitValue] = missing
+		}
+	}
+
+	rarities := make([]domain.NFTRarity, len(nfts))
+	tokenIDs := make(map[uint]uint, len(nfts))
+	for i, nft := range nfts {
+		rarity := domain.NFTRarity{
+			NFTID:             nft.ID,
+			CollectionID:      collectionID,
+			StatisticalRarity: 1,
+		}
+		for traitType, counts := range valueCounts {
+			value, exists := traitsByNFT[nft.ID][traitType]
+			if !exists {
+				value = missingTraitValue
+			}
+			frequency := float64(counts[value]) / total
+			rarity.RarityScore += 1 / frequency
+			rarity.StatisticalRarity *= frequency
+			rarity.InformationContent += -math.Log2(frequency)
+		}
+		rarities[i] = rarity
+		tokenIDs[nft.ID] = nft.TokenID
+	}
+
+	// 按分数从高到低排名，分数相同的排名相同
+	sort.Slice(rarities, func(i, j int) bool {
+		if !sameRarityScore(rarities[i].RarityScore, rarities[j].RarityScore) {
+			return rarities[i].RarityScore > rarities[j].RarityScore
+		}
+		return tokenIDs[rarities[i].NFTID] < tokenIDs[rarities[j].NFTID]
+	})
+	for i := range rarities {
+		if i > 0 && sameRarityScore(rarities[i].RarityScore, rarities[i-1].RarityScore) {
+			rarities[i].Rank = rarities[i-1].Rank
+		} else {
+			rarities[i].Rank = uint(i + 1)
+		}
+	}
+
+	return rarities
+}
+
+// 属性遍历顺序不同会导致浮点累加存在微小误差
+func sameRarityScore(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/domain"
+)
+
+func TestComputeRarities(t *testing.T) {
+	nfts := []domain.NFT{
+		{ID: 11, TokenID: 1},
+		{ID: 12, TokenID: 2},
+		{ID: 13, TokenID: 3},
+		{ID: 14, TokenID: 4},
+	}
+	attributes := []domain.NFTAttribute{
+		{NFTID: 11, TraitType: "Hat", Value: "Red"},
+		{NFTID: 11, TraitType: "Eyes", Value: "Blue"},
+		{NFTID: 12, TraitType: "Hat", Value: "Red"},
+		{NFTID: 12, TraitType: "Eyes", Value: "Green"},
+		{NFTID: 13, TraitType: "Hat", Value: "Gold"}, // 缺少 Eyes，按 missingTraitValue 统计
+		{NFTID: 14, TraitType: "Eyes", Value: "Blue"},
+		{NFTID: 14, TraitType: "Hat", Value: "Red"},
+	}
+
+	// Hat: Red 3/4, Gold 1/4; Eyes: Blue 2/4, Green 1/4, 缺失 1/4
+	want := []domain.NFTRarity{
+		{NFTID: 13, CollectionID: 7, RarityScore: 4 + 4, StatisticalRarity: 0.25 * 0.25, InformationContent: 2 + 2, Rank: 1},
+		{NFTID: 12, CollectionID: 7, RarityScore: 4.0/3 + 4, StatisticalRarity: 0.75 * 0.25, InformationContent: -math.Log2(0.75) + 2, Rank: 2},
+		{NFTID: 11, CollectionID: 7, RarityScore: 4.0/3 + 2, StatisticalRarity: 0.75 * 0.5, InformationContent: -math.Log2(0.75) + 1, Rank: 3},
+		{NFTID: 14, CollectionID: 7, RarityScore: 4.0/3 + 2, StatisticalRarity: 0.75 * 0.5, InformationContent: -math.Log2(0.75) + 1, Rank: 3},
+	}
+
+	got := computeRarities(7, nfts, attributes)
+	if len(got) != len(want) {
+		t.Fatalf("稀有度数量为 %d，应为 %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.NFTID != w.NFTID || g.CollectionID != w.CollectionID || g.Rank != w.Rank {
+			t.Errorf("第 %d 名为 NFT %d (系列 %d, 排名 %d)，应为 NFT %d (系列 %d, 排名 %d)", i, g.NFTID, g.CollectionID, g.Rank, w.NFTID, w.CollectionID, w.Rank)
+		}
+		if !sameRarityScore(g.RarityScore, w.RarityScore) || !sameRarityScore(g.StatisticalRarity, w.StatisticalRarity) || !sameRarityScore(g.InformationContent, w.InformationContent) {
+			t.Errorf("NFT %d 的分数为 %v/%v/%v，应为 %v/%v/%v", g.NFTID, g.RarityScore, g.StatisticalRarity, g.InformationContent, w.RarityScore, w.StatisticalRarity, w.InformationContent)
+		}
+	}
+}
+
+func TestComputeRaritiesEdgeCases(t *testing.T) {
+	tests := []struct {
+		name       string
+		nfts       []domain.NFT
+		attributes []domain.NFTAttribute
+		wantRanks  map[uint]uint
+	}{
+		{
+			name:      "空系列",
+			wantRanks: map[uint]uint{},
+		},
+		{
+			name:      "没有属性时排名相同",
+			nfts:      []domain.NFT{{ID: 1, TokenID: 1}, {ID: 2, TokenID: 2}},
+			wantRanks: map[uint]uint{1: 1, 2: 1},
+		},
+		{
+			name: "只有部分NFT有属性时缺失值也参与统计",
+			nfts: []domain.NFT{{ID: 1, TokenID: 1}, {ID: 2, TokenID: 2}, {ID: 3, TokenID: 3}},
+			attributes: []domain.NFTAttribute{
+				{NFTID: 2, TraitType: "Background", Value: "Gold"},
+			},
+			wantRanks: map[uint]uint{2: 1, 1: 2, 3: 2},
+		},
+		{
+			name: "排名并列后跳过名次",
+			nfts: []domain.NFT{{ID: 1, TokenID: 1}, {ID: 2, TokenID: 2}, {ID: 3, TokenID: 3}},
+			attributes: []domain.NFTAttribute{
+				{NFTID: 1, TraitType: "Hat", Value: "A"},
+				{NFTID: 2, TraitType: "Hat", Value: "B"},
+				{NFTID: 3, TraitType: "Hat", Value: "B"},
+			},
+			wantRanks: map[uint]uint{1: 1, 2: 2, 3: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeRarities(1, tt.nfts, tt.attributes)
+			if len(got) != len(tt.wantRanks) {
+				t.Fatalf("稀有度数量为 %d，应为 %d", len(got), len(tt.wantRanks))
+			}
+			for _, rarity := range got {
+				if rarity.Rank != tt.wantRanks[rarity.NFTID] {
+					t.Errorf("NFT %d 的排名为 %d，应为 %d", rarity.NFTID, rarity.Rank, tt.wantRanks[rarity.NFTID])
+				}
+			}
+		})
+	}
+}
+
+func TestDebouncer(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	var mutex sync.Mutex
+	runs := make(map[string]int)
+	d := newDebouncer(delay, func(key string) {
+		mutex.Lock()
+		runs[key]++
+		mutex.Unlock()
+	})
+	count := func(key string) int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return runs[key]
+	}
+
+	// 连续触发只在最后一次触发之后执行一次
+	for i := 0; i < 5; i++ {
+		d.trigger("a")
+		time.Sleep(delay / 5)
+	}
+	d.trigger("b")
+	if got := count("a"); got != 0 {
+		t.Fatalf("连续触发期间执行了 %d 次，应为 0", got)
+	}
+	time.Sleep(3 * delay)
+	if got := count("a"); got != 1 {
+		t.Fatalf("a 执行了 %d 次，应为 1", got)
+	}
+	if got := count("b"); got != 1 {
+		t.Fatalf("b 执行了 %d 次，应为 1", got)
+	}
+
+	// 执行完成后再次触发会重新执行
+	d.trigger("a")
+	time.Sleep(3 * delay)
+	if got := count("a"); got != 2 {
+		t.Fatalf("再次触发后 a 执行了 %d 次，应为 2", got)
+	}
+}
@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"strconv"
 	"sync"
 	"time"
 
@@ -15,26 +17,38 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"gorm.io/gorm"
 )
 
 type NFTUseCase struct {
-	nftRepo       *repository.NFTRepository
-	ethClientURL  string
-	contractCache map[string]*contracts.NFTContract
-	mutex         sync.RWMutex
-	ctx           context.Context
-	cancel        context.CancelFunc
+	nftRepo          *repository.NFTRepository
+	retryRepo        *repository.RetryRepository
+	search           *SearchUseCase
+	marketUC         *MarketUseCase       // 由 NewMarketUseCase 设置，用于在转移和授权变化时检查订单有效性
+	profileUC        *ProfileUseCase      // 由 NewProfileUseCase 设置，用于附加持有者资料和在转移后清除头像
+	webhookUC        *WebhookUseCase      // 由 NewWebhookUseCase 设置，用于推送铸造、转移和元数据更新事件
+	notificationUC   *NotificationUseCase // 由 NewNotificationUseCase 设置，用于通知NFT接收方
+	ethClientURL     string
+	contractCache    map[string]*contracts.NFTContract
+	mutex            sync.RWMutex
+	rarityRecomputes *debouncer
+	ctx              context.Context
+	cancel           context.CancelFunc
 }
 
-func NewNFTUseCase(nftRepo *repository.NFTRepository, ethClientURL string) *NFTUseCase {
+func NewNFTUseCase(nftRepo *repository.NFTRepository, retryRepo *repository.RetryRepository, search *SearchUseCase, ethClientURL string) *NFTUseCase {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &NFTUseCase{
+	uc := &NFTUseCase{
 		nftRepo:       nftRepo,
+		retryRepo:     retryRepo,
+		search:        search,
 		ethClientURL:  ethClientURL,
 		contractCache: make(map[string]*contracts.NFTContract),
 		ctx:           ctx,
 		cancel:        cancel,
 	}
+	uc.rarityRecomputes = newDebouncer(rarityRecomputeDelay, uc.recomputeScheduledRarity)
+	return uc
 }
 
 func (uc *NFTUseCase) getNFTContract(contractAddress string) (*contracts.NFTContract, error) {
@@ -58,43 +72,121 @@ func (uc *NFTUseCase) getNFTContract(contractAddress string) (*contracts.NFTCont
 	return contract, nil
 }
 
+// 获取只读检查使用的NFT合约实例：市场未登记的合约不写入缓存，用完后需调用 release 释放连接
+func (uc *NFTUseCase) peekNFTContract(contractAddress string) (contract *contracts.NFTContract, release func(), err error) {
+	contractAddress = common.HexToAddress(contractAddress).Hex()
+	uc.mutex.RLock()
+	_, cached := uc.contractCache[contractAddress]
+	uc.mutex.RUnlock()
+	if !cached {
+		if _, err := uc.nftRepo.GetCollectionByAddress(contractAddress); errors.Is(err, gorm.ErrRecordNotFound) {
+			contract, err := contracts.NewNFTContract(uc.ethClientURL, contractAddress)
+			if err != nil {
+				return nil, nil, err
+			}
+			return contract, contract.Close, nil
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("获取NFT系列失败: %w", err)
+		}
+	}
+
+	contract, err = uc.getNFTContract(contractAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contract, func() {}, nil
+}
+
 func (uc *NFTUseCase) GetAllCollections() ([]domain.NFTCollection, error) {
 	return uc.nftRepo.GetAllCollections()
 }
 
-func (uc *NFTUseCase) GetCollectionByAddress(contractAddress string) (*domain.NFTCollection, []domain.NFT, error) {
+func (uc *NFTUseCase) GetCollectionByAddress(contractAddress string, filter *domain.TraitFilter, sort string) (*domain.NFTCollection, []domain.NFTView, error) {
 	collection, err := uc.nftRepo.GetCollectionByAddress(contractAddress)
 	if err == nil {
-		nfts, err := uc.nftRepo.GetNFTsByCollectionID(collection.ID)
+		nfts, err := uc.nftRepo.GetNFTsByCollectionIDFiltered(collection.ID, filter, sort)
 		if err != nil {
 			return nil, nil, err
 		}
-		return collection, nfts, nil
+		return collection, uc.profileUC.DescribeNFTs(nfts), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("获取NFT集合失败: %w", err)
 	}
 
 	// 如果数据库中没有找到，尝试初始化
 	err = uc.InitializeNFTCollection(contractAddress)
 	if err != nil {
-		return nil, nil, fmt.Errorf("该地址不是有效的NFT合约: %w", err)
+		return nil, nil, chainCallError(err, domain.ErrCollectionNotFound.Wrap(fmt.Errorf("该地址不是有效的NFT合约: %w", err)))
 	}
 
 	// 再次尝试从数据库获取
 	collection, err = uc.nftRepo.GetCollectionByAddress(contractAddress)
 	if err != nil {
-		return nil, nil, fmt.Errorf("该地址不是有效的NFT合约: %w", err)
+		return nil, nil, notFoundAs(fmt.Errorf("该地址不是有效的NFT合约: %w", err), domain.ErrCollectionNotFound)
 	}
-	nfts, err := uc.nftRepo.GetNFTsByCollectionID(collection.ID)
+	nfts, err := uc.nftRepo.GetNFTsByCollectionIDFiltered(collection.ID, filter, sort)
 	if err != nil {
 		return nil, nil, err
 	}
-	return collection, nfts, nil
+	return collection, uc.profileUC.DescribeNFTs(nfts), nil
+}
+
+// 获取NFT系列的属性统计，包含每个取值的数量和在售数量
+func (uc *NFTUseCase) GetCollectionTraitFacets(contractAddress string) ([]domain.TraitFacet, error) {
+	collection, err := uc.nftRepo.GetCollectionByAddress(contractAddress)
+	if err != nil {
+		return nil, notFoundAs(fmt.Errorf("获取NFT集合失败: %w", err), domain.ErrCollectionNotFound)
+	}
+
+	counts, err := uc.nftRepo.GetTraitValueCounts(collection.ID)
+	if err != nil {
+		return nil, fmt.Errorf("统计NFT属性失败: %w", err)
+	}
+
+	facets := make([]domain.TraitFacet, 0)
+	index := make(map[string]int)
+	for _, count := range counts {
+		i, exists := index[count.TraitType]
+		if !exists {
+			i = len(facets)
+			index[count.TraitType] = i
+			facets = append(facets, domain.TraitFacet{TraitType: count.TraitType, Numeric: true})
+		}
+		facet := &facets[i]
+		facet.Count += count.Count
+		facet.ListedCount += count.ListedCount
+		facet.Values = append(facet.Values, count)
+
+		// 所有取值均为数值时，提供区间范围
+		value, err := strconv.ParseFloat(count.Value, 64)
+		if err != nil {
+			facet.Numeric = false
+			facet.Min, facet.Max = nil, nil
+			continue
+		}
+		if !facet.Numeric {
+			continue
+		}
+		if facet.Min == nil || value < *facet.Min {
+			facet.Min = &value
+		}
+		if facet.Max == nil || value > *facet.Max {
+			facet.Max = &value
+		}
+	}
+
+	return facets, nil
 }
 
-func (uc *NFTUseCase) GetNFTByTokenID(contractAddress string, tokenID uint) (*domain.NFT, []domain.NFTAttribute, error) {
+func (uc *NFTUseCase) GetNFTByTokenID(contractAddress string, tokenID uint) (*domain.NFTView, []domain.NFTAttribute, error) {
 	nft, err := uc.nftRepo.GetByTokenID(contractAddress, tokenID)
 	if err == nil {
 		attributes, err := uc.nftRepo.GetAttributes(nft.ID)
-		return nft, attributes, err
+		return &uc.profileUC.DescribeNFTs([]domain.NFT{*nft})[0], attributes, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("获取NFT失败: %w", err)
 	}
 	// 如果数据库中没有找到，先检查NFT系列是否存在
 	_, err = uc.nftRepo.GetCollectionByAddress(contractAddress)
@@ -102,24 +194,24 @@ func (uc *NFTUseCase) GetNFTByTokenID(contractAddress string, tokenID uint) (*do
 		// NFT系列不存在，初始化NFT系列
 		err = uc.InitializeNFTCollection(contractAddress)
 		if err != nil {
-			return nil, nil, fmt.Errorf("初始化NFT系列失败: %w", err)
+			return nil, nil, chainCallError(err, domain.ErrNFTNotFound.Wrap(fmt.Errorf("初始化NFT系列失败: %w", err)))
 		}
 	} else {
 		// NFT系列存在，初始化单个NFT
 		err = uc.InitializeNFT(contractAddress, tokenID)
 		if err != nil {
-			return nil, nil, fmt.Errorf("初始化NFT失败: %w", err)
+			return nil, nil, chainCallError(err, domain.ErrNFTNotFound.Wrap(fmt.Errorf("初始化NFT失败: %w", err)))
 		}
 	}
 
 	// 再次尝试从数据库获取
 	nft, err = uc.nftRepo.GetByTokenID(contractAddress, tokenID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("TokenID不存在: %w", err)
+		return nil, nil, notFoundAs(fmt.Errorf("TokenID不存在: %w", err), domain.ErrNFTNotFound)
 	}
 
 	attributes, err := uc.nftRepo.GetAttributes(nft.ID)
-	return nft, attributes, err
+	return &uc.profileUC.DescribeNFTs([]domain.NFT{*nft})[0], attributes, err
 }
 
 func (uc *NFTUseCase) InitializeNFT(contractAddress string, tokenID uint) error {
@@ -163,6 +255,7 @@ func (uc *NFTUseCase) InitializeNFT(contractAddress string, tokenID uint) error
 		return fmt.Errorf("创建NFT记录失败: %w", err)
 	}
 
+	attributes := make([]domain.NFTAttribute, 0, len(metadata.Attributes))
 	for _, attr := range metadata.Attributes {
 		attribute := &domain.NFTAttribute{
 			NFTID:     nft.ID,
@@ -172,8 +265,12 @@ func (uc *NFTUseCase) InitializeNFT(contractAddress string, tokenID uint) error
 		if err := uc.nftRepo.SaveNFTAttribute(attribute); err != nil {
 			return fmt.Errorf("创建NFT属性失败: %w", err)
 		}
+		attributes = append(attributes, *attribute)
 	}
 
+	uc.search.IndexNFT(nft, attributes)
+	uc.scheduleRarityRecompute(contractAddress)
+
 	return nil
 }
 
@@ -211,6 +308,7 @@ func (uc *NFTUseCase) InitializeNFTCollection(contractAddress string) error {
 		if err := uc.nftRepo.UpsertCollection(collection); err != nil {
 			return fmt.Errorf("创建 NFT 集合失败: %w", err)
 		}
+		uc.search.IndexCollection(collection)
 	} else if collection.TokenIconURI == "" {
 		// 如果集合存在但 TokenIconURI 为空，尝试从链上获取
 		tokenIconURI, err := nftContract.TokenIconURI()
@@ -220,6 +318,8 @@ func (uc *NFTUseCase) InitializeNFTCollection(contractAddress string) error {
 			collection.TokenIconURI = tokenIconURI
 			if err := uc.nftRepo.UpsertCollection(collection); err != nil {
 				log.Printf("更新 NFT 集合的 TokenIconURI 失败: %v", err)
+			} else {
+				uc.search.IndexCollection(collection)
 			}
 		}
 	}
@@ -240,6 +340,7 @@ func (uc *NFTUseCase) InitializeNFTCollection(contractAddress string) error {
 	for i := uint(0); i < totalSupply; i++ {
 		if err := uc.InitializeNFT(contractAddress, i); err != nil {
 			log.Printf("初始化NFT失败 (TokenID: %d): %v", i, err)
+			uc.EnqueueRetry(RetryJobTypeNFT, contractAddress, i, err)
 		}
 	}
 
@@ -278,10 +379,34 @@ func (uc *NFTUseCase) handleNFTEvent(contractAddress string, event *types.Log) {
 	case crypto.Keccak256Hash([]byte("MetadataUpdate(uint256)")).Hex():
 		uc.handleMetadataUpdate(contractAddress, event)
 	case crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")).Hex():
-		uc.handleTransfer(contractAddress, event)
+		uc.handleTransfer(contractAddress, event, false)
+	case crypto.Keccak256Hash([]byte("Approval(address,address,uint256)")).Hex():
+		uc.handleApproval(contractAddress, event)
+	case crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)")).Hex():
+		uc.handleApprovalForAll(contractAddress, event)
 	}
 }
 
+func (uc *NFTUseCase) handleApproval(contractAddress string, event *types.Log) {
+	if uc.marketUC == nil {
+		return
+	}
+	tokenID := new(big.Int).SetBytes(event.Topics[3].Bytes()).Uint64()
+	uc.marketUC.RevalidateNFTOrders(contractAddress, uint(tokenID))
+}
+
+func (uc *NFTUseCase) handleApprovalForAll(contractAddress string, event *types.Log) {
+	if uc.marketUC == nil {
+		return
+	}
+	owner := common.HexToAddress(event.Topics[1].Hex())
+	operator := common.HexToAddress(event.Topics[2].Hex())
+	if !uc.marketUC.isMarketAddress(operator) {
+		return
+	}
+	uc.marketUC.RevalidateSellerOrders(contractAddress, owner.Hex())
+}
+
 func (uc *NFTUseCase) handleMetadataUpdate(contractAddress string, event *types.Log) {
 	tokenID := new(big.Int).SetBytes(event.Data).Uint64()
 
@@ -311,6 +436,8 @@ func (uc *NFTUseCase) handleMetadataUpdate(contractAddress string, event *types.
 
 	if err := uc.nftRepo.SaveNFTTransferEvent(transferEvent); err != nil {
 		log.Printf("保存NFT mint事件失败: %v", err)
+	} else {
+		uc.search.IndexTransferEvent(transferEvent)
 	}
 
 	// 更新NFT元数据
@@ -323,18 +450,21 @@ func (uc *NFTUseCase) handleMetadataUpdate(contractAddress string, event *types.
 	tokenURI, err := nftContract.TokenURI(uint(tokenID))
 	if err != nil {
 		log.Printf("获取TokenURI失败: %v", err)
+		uc.EnqueueRetry(RetryJobTypeNFT, contractAddress, uint(tokenID), err)
 		return
 	}
 
 	metadata, err := nftContract.GetNFTMetadata(tokenURI)
 	if err != nil {
 		log.Printf("获取NFT元数据失败: %v", err)
+		uc.EnqueueRetry(RetryJobTypeNFT, contractAddress, uint(tokenID), err)
 		return
 	}
 
 	owner, err := nftContract.OwnerOf(uint(tokenID))
 	if err != nil {
 		log.Printf("获取NFT所有者失败: %v", err)
+		uc.EnqueueRetry(RetryJobTypeNFT, contractAddress, uint(tokenID), err)
 		return
 	}
 
@@ -353,6 +483,7 @@ func (uc *NFTUseCase) handleMetadataUpdate(contractAddress string, event *types.
 		return
 	}
 
+	attributes := make([]domain.NFTAttribute, 0, len(metadata.Attributes))
 	for _, attr := range metadata.Attributes {
 		attribute := &domain.NFTAttribute{
 			NFTID:     nft.ID,
@@ -362,11 +493,26 @@ func (uc *NFTUseCase) handleMetadataUpdate(contractAddress string, event *types.
 		err = uc.nftRepo.SaveNFTAttribute(attribute)
 		if err != nil {
 			log.Printf("保存NFT属性失败: %v", err)
+			continue
 		}
+		attributes = append(attributes, *attribute)
 	}
+
+	uc.search.IndexNFT(nft, attributes)
+
+	uc.scheduleRarityRecompute(contractAddress)
+
+	uc.webhookUC.dispatch(webhookEvent{
+		Type:       WebhookEventMetadataUpdated,
+		Collection: contractAddress,
+		Addresses:  []string{nft.Owner},
+		Data:       nft,
+	})
 }
 
-func (uc *NFTUseCase) handleTransfer(contractAddress string, event *types.Log) {
+// replaying 为 true 表示启动时重放历史事件，只重建转移记录和所有者，
+// 不重新校验订单、不发送通知和 Webhook（市场用例此时尚未加载订单，历史事件也不应再次推送）
+func (uc *NFTUseCase) handleTransfer(contractAddress string, event *types.Log, replaying bool) {
 	from := common.HexToAddress(event.Topics[1].Hex())
 	to := common.HexToAddress(event.Topics[2].Hex())
 	tokenID := new(big.Int).SetBytes(event.Topics[3].Bytes()).Uint64()
@@ -396,22 +542,61 @@ func (uc *NFTUseCase) handleTransfer(contractAddress string, event *types.Log) {
 
 	if from == common.HexToAddress("0x0000000000000000000000000000000000000000") {
 		transferEvent.EventType = "mint"
+		// 新铸造的NFT会改变系列的属性分布
+		uc.scheduleRarityRecompute(contractAddress)
 	}
 
 	if err := uc.nftRepo.SaveNFTTransferEvent(transferEvent); err != nil {
 		log.Printf("保存NFT transfer事件失败: %v", err)
+	} else {
+		uc.search.IndexTransferEvent(transferEvent)
+		if !replaying {
+			uc.notificationUC.notifyTransfer(transferEvent)
+		}
 	}
 
 	// 更新NFT所有者
 	err = uc.nftRepo.UpdateNFTOwner(contractAddress, uint(tokenID), to.Hex())
 	if err != nil {
 		log.Printf("更新NFT所有者失败: %v", err)
+	} else if err := uc.search.ReindexNFT(contractAddress, uint(tokenID)); err != nil {
+		log.Printf("更新NFT搜索索引失败: %v", err)
 	}
+
+	// 回放的是历史转移，不能据此清除当前持有者设置的头像
+	if replaying {
+		return
+	}
+
+	// 原持有者使用该NFT设置的头像失效
+	if uc.profileUC != nil {
+		uc.profileUC.handleTransfer(contractAddress, uint(tokenID), to.Hex())
+	}
+
+	// NFT离开卖家后挂单无法成交，回到卖家后可能恢复
+	if uc.marketUC != nil {
+		uc.marketUC.RevalidateNFTOrders(contractAddress, uint(tokenID))
+	}
+
+	webhookType := WebhookEventNFTTransfer
+	if transferEvent.EventType == "mint" {
+		webhookType = WebhookEventNFTMint
+	}
+	uc.webhookUC.dispatch(webhookEvent{
+		Type:       webhookType,
+		Collection: contractAddress,
+		Addresses:  []string{transferEvent.FromAddress, transferEvent.ToAddress},
+		Data:       transferEvent,
+	})
 }
 
 // 获取NFT的转移历史
-func (uc *NFTUseCase) GetNFTTransferHistory(contractAddress string, tokenID uint) ([]domain.NFTTransferEvent, error) {
-	return uc.nftRepo.GetNFTTransferEvents(contractAddress, tokenID)
+func (uc *NFTUseCase) GetNFTTransferHistory(contractAddress string, tokenID uint) ([]domain.NFTTransferEventView, error) {
+	events, err := uc.nftRepo.GetNFTTransferEvents(contractAddress, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.profileUC.DescribeTransferEvents(events), nil
 }
 
 // 获取NFT的当前所有者
@@ -423,6 +608,20 @@ func (uc *NFTUseCase) GetNFTCurrentOwner(contractAddress string, tokenID uint) (
 	return latestEvent.ToAddress, nil
 }
 
+// 将初始化失败的NFT或集合加入持久化重试队列
+func (uc *NFTUseCase) EnqueueRetry(jobType, contractAddress string, tokenID uint, cause error) {
+	job := &domain.RetryJob{
+		JobType:         jobType,
+		ContractAddress: contractAddress,
+		TokenID:         tokenID,
+		NextRetryAt:     time.Now().Add(retryBaseDelay),
+		LastError:       cause.Error(),
+	}
+	if err := uc.retryRepo.EnqueueJob(job); err != nil {
+		log.Printf("加入重试队列失败 (类型: %s, 地址: %s, TokenID: %d): %v", jobType, contractAddress, tokenID, err)
+	}
+}
+
 func (uc *NFTUseCase) Close() {
 	uc.cancel()
 }
@@ -450,7 +649,7 @@ func (uc *NFTUseCase) scanHistoricalEvents(contractAddress string) error {
 	}
 
 	for _, log := range logs {
-		uc.handleTransfer(contractAddress, &log)
+		uc.handleTransfer(contractAddress, &log, true)
 	}
 
 	return nil
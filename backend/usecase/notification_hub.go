@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"strings"
+	"sync"
+
+	"backend/domain"
+)
+
+// notificationHub 将新通知推送给该地址已连接的客户端
+type notificationHub struct {
+	subscribers map[string]map[chan domain.NotificationView]struct{}
+	mutex       sync.Mutex
+}
+
+func newNotificationHub() *notificationHub {
+	return &notificationHub{subscribers: make(map[string]map[chan domain.NotificationView]struct{})}
+}
+
+func (h *notificationHub) subscribe(address string) (chan domain.NotificationView, func()) {
+	key := strings.ToLower(address)
+	ch := make(chan domain.NotificationView, 16)
+
+	h.mutex.Lock()
+	if h.subscribers[key] == nil {
+		h.subscribers[key] = make(map[chan domain.NotificationView]struct{})
+	}
+	h.subscribers[key][ch] = struct{}{}
+	h.mutex.Unlock()
+
+	unsubscribe := func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		if _, exists := h.subscribers[key][ch]; exists {
+			delete(h.subscribers[key], ch)
+			if len(h.subscribers[key]) == 0 {
+				delete(h.subscribers, key)
+			}
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// 推送通知，订阅者处理不及时时丢弃本次推送，客户端可通过列表接口补齐
+func (h *notificationHub) publish(view domain.NotificationView) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for ch := range h.subscribers[strings.ToLower(view.Address)] {
+		select {
+		case ch <- view:
+		default:
+		}
+	}
+}
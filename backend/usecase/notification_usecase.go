@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 通知类型
+const (
+	NotificationTypeSold           = "sold"
+	NotificationTypePurchased      = "purchased"
+	NotificationTypeOrderCancelled = "order_cancelled"
+	NotificationTypeTransferIn     = "transfer_in"
+	NotificationTypeOfferReceived  = "offer_received"
+)
+
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+)
+
+type NotificationUseCase struct {
+	notificationRepo *repository.NotificationRepository
+	nftRepo          *repository.NFTRepository
+	tokenUC          *TokenUseCase
+	profileUC        *ProfileUseCase
+	hub              *notificationHub
+}
+
+// 创建后挂到 nftUC 和 marketUC 上，由转移、订单和出价事件生成通知
+func NewNotificationUseCase(notificationRepo *repository.NotificationRepository, nftRepo *repository.NFTRepository, tokenUC *TokenUseCase, profileUC *ProfileUseCase, nftUC *NFTUseCase, marketUC *MarketUseCase) *NotificationUseCase {
+	uc := &NotificationUseCase{
+		notificationRepo: notificationRepo,
+		nftRepo:          nftRepo,
+		tokenUC:          tokenUC,
+		profileUC:        profileUC,
+		hub:              newNotificationHub(),
+	}
+	nftUC.notificationUC = uc
+	marketUC.notificationUC = uc
+	return uc
+}
+
+// 分页获取通知和未读数量，before 为上一页最后一条通知的ID
+func (uc *NotificationUseCase) GetNotifications(address string, unreadOnly bool, beforeID uint, limit int) ([]domain.NotificationView, int64, error) {
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+	address = common.HexToAddress(address).Hex()
+	notifications, err := uc.notificationRepo.GetNotifications(address, unreadOnly, beforeID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := uc.notificationRepo.CountUnread(address)
+	if err != nil {
+		return nil, 0, err
+	}
+	return uc.describeNotifications(notifications), unread, nil
+}
+
+func (uc *NotificationUseCase) CountUnread(address string) (int64, error) {
+	return uc.notificationRepo.CountUnread(common.HexToAddress(address).Hex())
+}
+
+// 标记通知为已读，ids 为空时标记全部
+func (uc *NotificationUseCase) MarkRead(address string, ids []uint) (int64, error) {
+	return uc.notificationRepo.MarkRead(common.HexToAddress(address).Hex(), ids, time.Now())
+}
+
+// 订阅地址的新通知
+func (uc *NotificationUseCase) Subscribe(address string) (chan domain.NotificationView, func()) {
+	return uc.hub.subscribe(address)
+}
+
+// 为通知附加NFT、支付代币、格式化价格和对方资料
+func (uc *NotificationUseCase) describeNotifications(notifications []domain.Notification) []domain.NotificationView {
+	tokenAddresses := make([]string, 0, len(notifications))
+	parties := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.TokenAddress != "" {
+			tokenAddresses = append(tokenAddresses, notification.TokenAddress)
+		}
+		if notification.Counterparty != "" {
+			parties = append(parties, notification.Counterparty)
+		}
+	}
+	tokens := uc.tokenUC.lookupTokens(tokenAddresses)
+	profiles := uc.profileUC.Summaries(parties...)
+
+	nfts := make(map[string]*domain.NFT)
+	views := make([]domain.NotificationView, len(notifications))
+	for i, notification := range notifications {
+		key := fmt.Sprintf("%s:%d", strings.ToLower(notification.NFTContractAddress), notification.TokenID)
+		nft, exists := nfts[key]
+		if !exists {
+			if found, err := uc.nftRepo.GetByTokenID(notification.NFTContractAddress, notification.TokenID); err == nil {
+				nft = found
+			}
+			nfts[key] = nft
+		}
+
+		views[i] = domain.NotificationView{
+			Notification:        notification,
+			NFT:                 nft,
+			PriceFormatted:      notification.Price,
+			CounterpartyProfile: profiles[strings.ToLower(notification.Counterparty)],
+		}
+		if token, exists := tokens[strings.ToLower(notification.TokenAddress)]; exists {
+			views[i].PaymentToken = token
+			views[i].PriceFormatted = FormatTokenAmount(notification.Price, token.Decimals)
+		}
+	}
+	return views
+}
+
+// 保存通知并推送给在线的客户端，uc 为 nil 时不做任何事
+func (uc *NotificationUseCase) notify(notification *domain.Notification) {
+	if uc == nil {
+		return
+	}
+	notification.Address = common.HexToAddress(notification.Address).Hex()
+	notification.CreatedAt = time.Now()
+	created, err := uc.notificationRepo.CreateNotification(notification)
+	if err != nil {
+		log.Printf("保存通知失败: %v", err)
+		return
+	}
+	if created {
+		uc.hub.publish(uc.describeNotifications([]domain.Notification{*notification})[0])
+	}
+}
+
+// 成交时通知卖家和买家
+func (uc *NotificationUseCase) notifySale(reference, nftAddress string, tokenID uint, tokenAddress, price, seller, buyer, transactionHash string) {
+	if uc == nil {
+		return
+	}
+	uc.notify(&domain.Notification{
+		Address:            seller,
+		Type:               NotificationTypeSold,
+		Reference:          reference,
+		Title:              fmt.Sprintf("你的NFT #%d 已售出", tokenID),
+		NFTContractAddress: nftAddress,
+		TokenID:            tokenID,
+		TokenAddress:       tokenAddress,
+		Price:              price,
+		Counterparty:       buyer,
+		TransactionHash:    transactionHash,
+	})
+	uc.notify(&domain.Notification{
+		Address:            buyer,
+		Type:               NotificationTypePurchased,
+		Reference:          reference,
+		Title:              fmt.Sprintf("你已购得NFT #%d", tokenID),
+		NFTContractAddress: nftAddress,
+		TokenID:            tokenID,
+		TokenAddress:       tokenAddress,
+		Price:              price,
+		Counterparty:       seller,
+		TransactionHash:    transactionHash,
+	})
+}
+
+// 链上订单被取消时通知卖家
+func (uc *NotificationUseCase) notifyOrderCancelled(order *domain.Order, transactionHash string) {
+	if uc == nil {
+		return
+	}
+	uc.notify(&domain.Notification{
+		Address:            order.Seller,
+		Type:               NotificationTypeOrderCancelled,
+		Reference:          orderActivityReference(order.ID),
+		Title:              fmt.Sprintf("NFT #%d 的挂单已取消", order.TokenID),
+		NFTContractAddress: order.NFTContractAddress,
+		TokenID:            order.TokenID,
+		TokenAddress:       order.TokenAddress,
+		Price:              order.Price,
+		TransactionHash:    transactionHash,
+	})
+}
+
+// NFT转入时通知接收方，铸造也视为转入
+func (uc *NotificationUseCase) notifyTransfer(event *domain.NFTTransferEvent) {
+	if uc == nil || common.HexToAddress(event.ToAddress) == (common.Address{}) {
+		return
+	}
+	title := fmt.Sprintf("你收到了NFT #%d", event.TokenID)
+	if event.EventType == "mint" {
+		title = fmt.Sprintf("你铸造了NFT #%d", event.TokenID)
+	}
+	uc.notify(&domain.Notification{
+		Address:            event.ToAddress,
+		Type:               NotificationTypeTransferIn,
+		Reference:          fmt.Sprintf("transfer:%s:%s:%d", event.TransactionHash, event.ContractAddress, event.TokenID),
+		Title:              title,
+		NFTContractAddress: event.ContractAddress,
+		TokenID:            event.TokenID,
+		Counterparty:       event.FromAddress,
+		TransactionHash:    event.TransactionHash,
+	})
+}
+
+// 收到针对单个NFT的出价时通知持有者，系列出价不逐一通知
+func (uc *NotificationUseCase) notifyOffer(offer *domain.Offer) {
+	if uc == nil || offer.CollectionWide {
+		return
+	}
+	nft, err := uc.nftRepo.GetByTokenID(offer.NFTContractAddress, offer.TokenID)
+	if err != nil || strings.EqualFold(nft.Owner, offer.Bidder) {
+		return
+	}
+	uc.notify(&domain.Notification{
+		Address:            nft.Owner,
+		Type:               NotificationTypeOfferReceived,
+		Reference:          fmt.Sprintf("offer:%s", offer.Hash),
+		Title:              fmt.Sprintf("你的NFT #%d 收到新出价", offer.TokenID),
+		NFTContractAddress: offer.NFTContractAddress,
+		TokenID:            offer.TokenID,
+		TokenAddress:       offer.TokenAddress,
+		Price:              offer.Price,
+		Counterparty:       offer.Bidder,
+	})
+}
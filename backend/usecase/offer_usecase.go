@@ -0,0 +1,465 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"gorm.io/gorm"
+)
+
+// 出价状态
+const (
+	OfferStatusActive    = 0
+	OfferStatusCancelled = 1
+	OfferStatusExpired   = 2
+)
+
+// 出价失效原因
+const (
+	OfferInvalidReasonBalance   = "insufficient_balance"
+	OfferInvalidReasonAllowance = "insufficient_allowance"
+)
+
+// 定期检查出价过期和买家资金的间隔
+const offerCheckInterval = time.Minute
+
+var (
+	ErrInvalidOffer   = domain.NewError(domain.ErrKindInvalid, "invalid_offer", "无效的出价")
+	ErrOfferExists    = domain.NewError(domain.ErrKindConflict, "offer_exists", "出价已存在")
+	ErrOfferNotActive = domain.NewError(domain.ErrKindConflict, "offer_not_active", "出价已失效")
+)
+
+var offerFields = []apitypes.Type{
+	{Name: "bidder", Type: "address"},
+	{Name: "nft", Type: "address"},
+	{Name: "tokenId", Type: "uint256"},
+	{Name: "collectionWide", Type: "bool"},
+	{Name: "paymentToken", Type: "address"},
+	{Name: "price", Type: "uint256"},
+	{Name: "nonce", Type: "uint256"},
+	{Name: "expiry", Type: "uint256"},
+}
+
+var cancelOfferFields = []apitypes.Type{
+	{Name: "offerHash", Type: "bytes32"},
+}
+
+var increaseOfferNonceFields = []apitypes.Type{
+	{Name: "bidder", Type: "address"},
+	{Name: "nonce", Type: "uint256"},
+}
+
+// OfferRequest 表示买家提交的出价参数，系列出价时 TokenID 为 0
+type OfferRequest struct {
+	Bidder         string
+	NFTAddress     string
+	TokenID        uint
+	CollectionWide bool
+	TokenAddress   string
+	Price          string
+	Nonce          string
+	Expiry         uint64
+	Signature      string
+}
+
+// OfferTypedData 表示待买家签名的出价数据
+type OfferTypedData struct {
+	Hash      string
+	TypedData TypedDataJSON // 可直接用于 eth_signTypedData_v4
+}
+
+// BidderNonceInfo 表示买家当前的出价 nonce，以及将其加一（取消全部已签名出价）需要签名的数据
+type BidderNonceInfo struct {
+	Bidder        string
+	Nonce         uint64
+	IncreaseNonce OfferTypedData
+}
+
+type OfferUseCase struct {
+	offerRepo *repository.OfferRepository
+	marketUC  *MarketUseCase
+	tokenUC   *TokenUseCase
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func NewOfferUseCase(offerRepo *repository.OfferRepository, marketUC *MarketUseCase, tokenUC *TokenUseCase) *OfferUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	uc := &OfferUseCase{
+		offerRepo: offerRepo,
+		marketUC:  marketUC,
+		tokenUC:   tokenUC,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	return uc
+}
+
+// Start 启动出价检查协程，需在所有用例构造完成后调用
+func (uc *OfferUseCase) Start() {
+	go uc.startOfferChecker()
+}
+
+func (uc *OfferUseCase) Close() {
+	uc.cancel()
+}
+
+// 校验出价参数并构建签名数据
+func (uc *OfferUseCase) offerTypedData(req OfferRequest) (apitypes.TypedData, error) {
+	if !common.IsHexAddress(req.Bidder) || !common.IsHexAddress(req.NFTAddress) || !common.IsHexAddress(req.TokenAddress) {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 地址格式错误", ErrInvalidOffer)
+	}
+	price, ok := new(big.Int).SetString(req.Price, 10)
+	if !ok || price.Sign() <= 0 {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 价格必须为大于0的整数", ErrInvalidOffer)
+	}
+	nonce, ok := new(big.Int).SetString(req.Nonce, 10)
+	if !ok || nonce.Sign() < 0 {
+		return apitypes.TypedData{}, fmt.Errorf("%w: nonce必须为非负整数", ErrInvalidOffer)
+	}
+	if req.Expiry <= uint64(time.Now().Unix()) {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 过期时间必须晚于当前时间", ErrInvalidOffer)
+	}
+	if req.CollectionWide && req.TokenID != 0 {
+		return apitypes.TypedData{}, fmt.Errorf("%w: 系列出价的tokenId必须为0", ErrInvalidOffer)
+	}
+
+	return uc.marketUC.marketTypedData("Offer", offerFields, apitypes.TypedDataMessage{
+		"bidder":         common.HexToAddress(req.Bidder).Hex(),
+		"nft":            common.HexToAddress(req.NFTAddress).Hex(),
+		"tokenId":        new(big.Int).SetUint64(uint64(req.TokenID)).String(),
+		"collectionWide": req.CollectionWide,
+		"paymentToken":   common.HexToAddress(req.TokenAddress).Hex(),
+		"price":          price.String(),
+		"nonce":          nonce.String(),
+		"expiry":         new(big.Int).SetUint64(req.Expiry).String(),
+	}), nil
+}
+
+// 构建待签名的出价数据
+func (uc *OfferUseCase) BuildOfferTypedData(req OfferRequest) (*OfferTypedData, error) {
+	typedData, err := uc.offerTypedData(req)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &OfferTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)}, nil
+}
+
+// 校验签名、支付代币和买家资金后保存出价
+func (uc *OfferUseCase) SubmitOffer(req OfferRequest) (*domain.OfferView, error) {
+	typedData, err := uc.offerTypedData(req)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := verifyTypedDataSigner(typedData, req.Signature, req.Bidder)
+	if err != nil {
+		return nil, err
+	}
+	if current, valid, err := uc.checkBidderNonce(req.Bidder, typedData.Message["nonce"].(string)); err != nil {
+		return nil, err
+	} else if !valid {
+		return nil, fmt.Errorf("%w: nonce已失效，当前nonce为 %d", ErrInvalidOffer, current)
+	}
+	if _, err := uc.offerRepo.GetOfferByHash(hash.Hex()); err == nil {
+		return nil, ErrOfferExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	paymentToken, err := uc.tokenUC.EnsureToken(req.TokenAddress)
+	if err != nil {
+		return nil, chainCallError(err, fmt.Errorf("%w: 支付代币不是有效的ERC-20合约", ErrInvalidOffer))
+	}
+	accepted, err := uc.tokenUC.IsTokenAccepted(paymentToken)
+	if err != nil {
+		return nil, fmt.Errorf("检查代币名单失败: %w", err)
+	}
+	if !accepted {
+		return nil, fmt.Errorf("%w: 市场不接受该支付代币", ErrInvalidOffer)
+	}
+
+	signature, err := normalizeSignature(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	offer := &domain.Offer{
+		Hash:               hash.Hex(),
+		Bidder:             common.HexToAddress(req.Bidder).Hex(),
+		NFTContractAddress: common.HexToAddress(req.NFTAddress).Hex(),
+		TokenID:            req.TokenID,
+		CollectionWide:     req.CollectionWide,
+		TokenAddress:       common.HexToAddress(req.TokenAddress).Hex(),
+		Price:              typedData.Message["price"].(string),
+		Nonce:              typedData.Message["nonce"].(string),
+		Expiry:             time.Unix(int64(req.Expiry), 0),
+		Signature:          signature,
+		Status:             OfferStatusActive,
+	}
+
+	_, reason, err := uc.checkOfferFunds(offer)
+	if err != nil {
+		return nil, chainCallError(fmt.Errorf("检查买家资金失败: %w", err), fmt.Errorf("%w: 无法读取买家的代币余额", ErrInvalidOffer))
+	}
+	switch reason {
+	case OfferInvalidReasonBalance:
+		return nil, fmt.Errorf("%w: 买家余额不足", ErrInvalidOffer)
+	case OfferInvalidReasonAllowance:
+		return nil, fmt.Errorf("%w: 买家未授权市场合约使用足够的代币", ErrInvalidOffer)
+	}
+
+	if err := uc.offerRepo.CreateOffer(offer); err != nil {
+		return nil, fmt.Errorf("保存出价失败: %w", err)
+	}
+	uc.marketUC.alertUC.evaluateOffer(offer)
+	uc.marketUC.notificationUC.notifyOffer(offer)
+	return uc.tokenUC.DescribeOffer(offer), nil
+}
+
+// 检查买家的代币余额和对市场合约的授权额度是否足以支付出价
+func (uc *OfferUseCase) checkOfferFunds(offer *domain.Offer) (bool, string, error) {
+	token, err := uc.tokenUC.getTokenContract(offer.TokenAddress)
+	if err != nil {
+		return false, "", fmt.Errorf("获取代币合约实例失败: %w", err)
+	}
+	price, ok := new(big.Int).SetString(offer.Price, 10)
+	if !ok {
+		return false, "", fmt.Errorf("无效的出价价格: %s", offer.Price)
+	}
+	bidder := common.HexToAddress(offer.Bidder)
+
+	balance, err := token.BalanceOf(bidder)
+	if err != nil {
+		return false, "", fmt.Errorf("获取买家余额失败: %w", err)
+	}
+	if balance.Cmp(price) < 0 {
+		return true, OfferInvalidReasonBalance, nil
+	}
+
+	allowance, err := token.Allowance(bidder, uc.marketUC.contract.Address())
+	if err != nil {
+		return false, "", fmt.Errorf("获取买家授权额度失败: %w", err)
+	}
+	if allowance.Cmp(price) < 0 {
+		return true, OfferInvalidReasonAllowance, nil
+	}
+	return false, "", nil
+}
+
+func (uc *OfferUseCase) GetOffer(id uint) (*domain.OfferView, error) {
+	offer, err := uc.offerRepo.GetOfferByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrOfferNotFound)
+	}
+	return uc.tokenUC.DescribeOffer(offer), nil
+}
+
+// 获取NFT的有效出价（包括系列出价），按美元价格从高到低排序
+func (uc *OfferUseCase) GetOffersForNFT(contractAddress string, tokenID uint, includeInvalid bool) ([]domain.OfferView, error) {
+	offers, err := uc.offerRepo.GetActiveOffersByNFT(common.HexToAddress(contractAddress).Hex(), tokenID, includeInvalid)
+	if err != nil {
+		return nil, err
+	}
+	views := uc.tokenUC.DescribeOffers(offers)
+	sortOffersByUSD(views)
+	return views, nil
+}
+
+// 按美元价格从高到低排序，没有报价的排在最后
+func sortOffersByUSD(views []domain.OfferView) {
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := views[i].PriceUSD, views[j].PriceUSD
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a > *b
+	})
+}
+
+func (uc *OfferUseCase) GetCollectionOffers(contractAddress string, includeInvalid bool) ([]domain.OfferView, error) {
+	offers, err := uc.offerRepo.GetActiveCollectionOffers(common.HexToAddress(contractAddress).Hex(), includeInvalid)
+	if err != nil {
+		return nil, err
+	}
+	views := uc.tokenUC.DescribeOffers(offers)
+	sortOffersByUSD(views)
+	return views, nil
+}
+
+func (uc *OfferUseCase) GetOffersByBidder(bidder string) ([]domain.OfferView, error) {
+	offers, err := uc.offerRepo.GetOffersByBidder(common.HexToAddress(bidder).Hex())
+	if err != nil {
+		return nil, err
+	}
+	return uc.tokenUC.DescribeOffers(offers), nil
+}
+
+func (uc *OfferUseCase) cancelTypedData(offer *domain.Offer) apitypes.TypedData {
+	return uc.marketUC.marketTypedData("CancelOffer", cancelOfferFields, apitypes.TypedDataMessage{
+		"offerHash": offer.Hash,
+	})
+}
+
+// 构建取消出价需要买家签名的数据
+func (uc *OfferUseCase) BuildCancelTypedData(id uint) (*OfferTypedData, error) {
+	offer, err := uc.offerRepo.GetOfferByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrOfferNotFound)
+	}
+	if offer.Status != OfferStatusActive {
+		return nil, ErrOfferNotActive
+	}
+	typedData := uc.cancelTypedData(offer)
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &OfferTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)}, nil
+}
+
+// 校验买家的取消签名后取消出价
+func (uc *OfferUseCase) CancelOffer(id uint, signature string) (*domain.OfferView, error) {
+	offer, err := uc.offerRepo.GetOfferByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrOfferNotFound)
+	}
+	if offer.Status != OfferStatusActive {
+		return nil, ErrOfferNotActive
+	}
+	if _, err := verifyTypedDataSigner(uc.cancelTypedData(offer), signature, offer.Bidder); err != nil {
+		return nil, err
+	}
+	if err := uc.offerRepo.UpdateOfferStatus(offer.ID, OfferStatusCancelled); err != nil {
+		return nil, fmt.Errorf("取消出价失败: %w", err)
+	}
+	offer.Status = OfferStatusCancelled
+	return uc.tokenUC.DescribeOffer(offer), nil
+}
+
+func (uc *OfferUseCase) startOfferChecker() {
+	ticker := time.NewTicker(offerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uc.RevalidateOffers(); err != nil {
+				log.Printf("检查出价有效性失败: %v", err)
+			}
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+// 将过期出价标记为过期，并按买家当前余额和授权额度更新其余出价的有效性
+func (uc *OfferUseCase) RevalidateOffers() error {
+	if _, err := uc.offerRepo.ExpireOffers(time.Now()); err != nil {
+		return fmt.Errorf("更新过期出价失败: %w", err)
+	}
+
+	offers, err := uc.offerRepo.GetActiveOffers()
+	if err != nil {
+		return fmt.Errorf("获取有效出价失败: %w", err)
+	}
+	for i := range offers {
+		if uc.ctx.Err() != nil {
+			return nil
+		}
+		offer := &offers[i]
+		invalid, reason, err := uc.checkOfferFunds(offer)
+		if err != nil {
+			log.Printf("检查出价资金失败 (出价ID: %d): %v", offer.ID, err)
+			continue
+		}
+		if invalid == offer.Invalid && reason == offer.InvalidReason {
+			continue
+		}
+		if err := uc.offerRepo.UpdateOfferValidity(offer.ID, invalid, reason); err != nil {
+			log.Printf("更新出价有效性失败 (出价ID: %d): %v", offer.ID, err)
+		}
+	}
+	return nil
+}
+
+// 检查出价的 nonce 不小于买家当前的 nonce，返回当前 nonce
+func (uc *OfferUseCase) checkBidderNonce(bidder, nonce string) (uint64, bool, error) {
+	current, err := uc.offerRepo.GetBidderNonce(common.HexToAddress(bidder).Hex())
+	if err != nil {
+		return 0, false, fmt.Errorf("获取买家nonce失败: %w", err)
+	}
+	value, ok := new(big.Int).SetString(nonce, 10)
+	return current, ok && value.Cmp(new(big.Int).SetUint64(current)) >= 0, nil
+}
+
+func (uc *OfferUseCase) increaseNonceTypedData(bidder string, nonce uint64) apitypes.TypedData {
+	return uc.marketUC.marketTypedData("IncreaseOfferNonce", increaseOfferNonceFields, apitypes.TypedDataMessage{
+		"bidder": common.HexToAddress(bidder).Hex(),
+		"nonce":  new(big.Int).SetUint64(nonce).String(),
+	})
+}
+
+// 获取买家当前的出价 nonce
+func (uc *OfferUseCase) GetBidderNonce(bidder string) (*BidderNonceInfo, error) {
+	bidder = common.HexToAddress(bidder).Hex()
+	nonce, err := uc.offerRepo.GetBidderNonce(bidder)
+	if err != nil {
+		return nil, err
+	}
+
+	typedData := uc.increaseNonceTypedData(bidder, nonce+1)
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+	return &BidderNonceInfo{
+		Bidder:        bidder,
+		Nonce:         nonce,
+		IncreaseNonce: OfferTypedData{Hash: hash.Hex(), TypedData: newTypedDataJSON(typedData)},
+	}, nil
+}
+
+// 校验买家签名后提高其出价 nonce，取消 nonce 更小的全部有效出价
+func (uc *OfferUseCase) IncreaseNonceWithSignature(bidder string, nonce uint64, signature string) (*BidderNonceInfo, error) {
+	if _, err := verifyTypedDataSigner(uc.increaseNonceTypedData(bidder, nonce), signature, bidder); err != nil {
+		return nil, err
+	}
+	bidder = common.HexToAddress(bidder).Hex()
+	increased, err := uc.offerRepo.IncreaseBidderNonce(bidder, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("更新买家nonce失败: %w", err)
+	}
+	if !increased {
+		return nil, fmt.Errorf("%w: 新的nonce必须大于当前nonce", ErrInvalidOffer)
+	}
+
+	// 出价的 nonce 为 uint256 字符串，逐个比较后取消
+	offers, err := uc.offerRepo.GetActiveOffersByBidder(bidder)
+	if err != nil {
+		return nil, fmt.Errorf("获取买家出价失败: %w", err)
+	}
+	threshold := new(big.Int).SetUint64(nonce)
+	for _, offer := range offers {
+		if value, ok := new(big.Int).SetString(offer.Nonce, 10); ok && value.Cmp(threshold) >= 0 {
+			continue
+		}
+		if err := uc.offerRepo.UpdateOfferStatus(offer.ID, OfferStatusCancelled); err != nil {
+			return nil, fmt.Errorf("取消出价失败: %w", err)
+		}
+	}
+	return uc.GetBidderNonce(bidder)
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"backend/contracts"
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// 未指定 deadline 时建议的签名有效期
+const defaultPermitValidity = 30 * time.Minute
+
+var (
+	ErrOrderNotActive   = domain.NewError(domain.ErrKindConflict, "order_not_active", "订单不可购买")
+	ErrInvalidSignature = domain.NewError(domain.ErrKindInvalid, "invalid_signature", "签名无效")
+)
+
+// OrderPermit 表示购买订单所需的 EIP-2612 permit 签名数据
+type OrderPermit struct {
+	OrderIndex uint // buyNFT 使用的链上订单索引
+	Deadline   uint64
+	Nonce      string
+	TypedData  TypedDataJSON // 可直接用于 eth_signTypedData_v4
+
+	typedData apitypes.TypedData
+}
+
+// TypedDataJSON 是 eth_signTypedData_v4 接受的签名数据格式，签名域只包含已声明的字段
+type TypedDataJSON struct {
+	Types       apitypes.Types            `json:"types"`
+	PrimaryType string                    `json:"primaryType"`
+	Domain      map[string]interface{}    `json:"domain"`
+	Message     apitypes.TypedDataMessage `json:"message"`
+}
+
+func newTypedDataJSON(typedData apitypes.TypedData) TypedDataJSON {
+	typedDomain := map[string]interface{}{
+		"name":              typedData.Domain.Name,
+		"version":           typedData.Domain.Version,
+		"verifyingContract": typedData.Domain.VerifyingContract,
+	}
+	if typedData.Domain.ChainId != nil {
+		typedDomain["chainId"] = (*big.Int)(typedData.Domain.ChainId).Uint64()
+	}
+	return TypedDataJSON{
+		Types:       typedData.Types,
+		PrimaryType: typedData.PrimaryType,
+		Domain:      typedDomain,
+		Message:     typedData.Message,
+	}
+}
+
+// SplitSignature 表示拆分后的签名，可直接作为 buyNFT 的参数
+type SplitSignature struct {
+	OrderIndex uint
+	Deadline   uint64
+	V          uint8
+	R          string
+	S          string
+}
+
+// 获取可购买的订单，orderIndex 为链上订单索引
+func (uc *MarketUseCase) getActiveOrder(orderIndex uint) (*domain.Order, error) {
+	order, err := uc.GetOrderByID(orderIndex)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != 0 {
+		return nil, ErrOrderNotActive
+	}
+	return order, nil
+}
+
+// 构建购买订单的 permit 签名数据，deadline 为 0 时使用默认有效期
+func (uc *MarketUseCase) BuildOrderPermit(orderIndex uint, buyer string, deadline uint64) (*OrderPermit, error) {
+	order, err := uc.getActiveOrder(orderIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := uc.tokenUC.getTokenContract(order.TokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("获取代币合约实例失败: %w", err)
+	}
+
+	nonce, err := token.Nonces(common.HexToAddress(buyer))
+	if err != nil {
+		return nil, fmt.Errorf("获取permit nonce失败，代币可能不支持EIP-2612: %w", err)
+	}
+
+	typedDomain, err := uc.permitDomain(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline == 0 {
+		deadline = uint64(time.Now().Add(defaultPermitValidity).Unix())
+	}
+
+	typedData := uc.permitTypedData(typedDomain, order, buyer, nonce, deadline)
+	return &OrderPermit{
+		OrderIndex: orderIndex,
+		Deadline:   deadline,
+		Nonce:      nonce.String(),
+		TypedData:  newTypedDataJSON(typedData),
+		typedData:  typedData,
+	}, nil
+}
+
+// 校验买家的 permit 签名并拆分为 v/r/s
+func (uc *MarketUseCase) VerifyOrderPermit(orderIndex uint, buyer string, deadline uint64, signature string) (*SplitSignature, error) {
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	return uc.verifyPermitSignature(orderIndex, buyer, deadline, sig)
+}
+
+// 解析65字节的签名，v 统一为 0/1
+func parseSignature(signature string) ([]byte, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: 签名格式错误", ErrInvalidSignature)
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	return sig, nil
+}
+
+func (uc *MarketUseCase) verifyPermitSignature(orderIndex uint, buyer string, deadline uint64, sig []byte) (*SplitSignature, error) {
+	if deadline < uint64(time.Now().Unix()) {
+		return nil, fmt.Errorf("%w: 签名已过期", ErrInvalidSignature)
+	}
+
+	permit, err := uc.BuildOrderPermit(orderIndex, buyer, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(permit.typedData)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名数据哈希失败: %w", err)
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != common.HexToAddress(buyer) {
+		return nil, fmt.Errorf("%w: 签名者与买家不一致", ErrInvalidSignature)
+	}
+
+	return &SplitSignature{
+		OrderIndex: orderIndex,
+		Deadline:   deadline,
+		V:          sig[64] + 27,
+		R:          hexutil.Encode(sig[:32]),
+		S:          hexutil.Encode(sig[32:64]),
+	}, nil
+}
+
+// 确定代币的 EIP-712 签名域：优先使用 EIP-5267 eip712Domain()，否则用 name()/version() 并与 DOMAIN_SEPARATOR 校验
+func (uc *MarketUseCase) permitDomain(token *contracts.ERC20Contract) (apitypes.TypedDataDomain, error) {
+	if d, err := token.EIP712Domain(); err == nil {
+		return apitypes.TypedDataDomain{
+			Name:              d.Name,
+			Version:           d.Version,
+			ChainId:           (*math.HexOrDecimal256)(d.ChainID),
+			VerifyingContract: d.VerifyingContract.Hex(),
+		}, nil
+	}
+
+	name, err := token.Name()
+	if err != nil {
+		return apitypes.TypedDataDomain{}, fmt.Errorf("获取代币名称失败: %w", err)
+	}
+	version, err := token.Version()
+	if err != nil {
+		version = "1"
+	}
+	chainID, err := token.ChainID()
+	if err != nil {
+		return apitypes.TypedDataDomain{}, fmt.Errorf("获取链ID失败: %w", err)
+	}
+
+	typedDomain := apitypes.TypedDataDomain{
+		Name:              name,
+		Version:           version,
+		ChainId:           (*math.HexOrDecimal256)(chainID),
+		VerifyingContract: token.Address().Hex(),
+	}
+
+	if separator, err := token.DomainSeparator(); err == nil {
+		typedData := apitypes.TypedData{Types: permitTypes, Domain: typedDomain}
+		computed, err := typedData.HashStruct("EIP712Domain", typedDomain.Map())
+		if err != nil {
+			return apitypes.TypedDataDomain{}, fmt.Errorf("计算签名域哈希失败: %w", err)
+		}
+		if common.BytesToHash(computed) != separator {
+			return apitypes.TypedDataDomain{}, fmt.Errorf("无法确定代币的签名域")
+		}
+	}
+
+	return typedDomain, nil
+}
+
+var permitTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"Permit": {
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	},
+}
+
+func (uc *MarketUseCase) permitTypedData(typedDomain apitypes.TypedDataDomain, order *domain.Order, buyer string, nonce *big.Int, deadline uint64) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       permitTypes,
+		PrimaryType: "Permit",
+		Domain:      typedDomain,
+		Message: apitypes.TypedDataMessage{
+			"owner":    common.HexToAddress(buyer).Hex(),
+			"spender":  uc.contract.Address().Hex(),
+			"value":    order.Price,
+			"nonce":    nonce.String(),
+			"deadline": new(big.Int).SetUint64(deadline).String(),
+		},
+	}
+}
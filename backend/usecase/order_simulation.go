@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+
+	"backend/contracts/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// 购买预检的检查项
+const (
+	BuyCheckOrderActive     = "order_active"
+	BuyCheckSellerOwnsNFT   = "seller_owns_nft"
+	BuyCheckMarketApproved  = "market_approved"
+	BuyCheckPermitSignature = "permit_signature"
+	BuyCheckBuyerBalance    = "buyer_balance"
+	BuyCheckAllowance       = "allowance"
+	BuyCheckCall            = "eth_call"
+)
+
+// PreflightCheck 表示一项交易预检的结果
+type PreflightCheck struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// PreflightChecks 记录所有检查项及其中未通过的项
+type PreflightChecks struct {
+	Checks   []PreflightCheck
+	Failures []PreflightCheck
+}
+
+func newPreflightChecks() PreflightChecks {
+	return PreflightChecks{Checks: []PreflightCheck{}, Failures: []PreflightCheck{}}
+}
+
+func (p *PreflightChecks) addCheck(name string, passed bool, message string) {
+	check := PreflightCheck{Name: name, Passed: passed, Message: message}
+	p.Checks = append(p.Checks, check)
+	if !passed {
+		p.Failures = append(p.Failures, check)
+	}
+}
+
+// BuySimulation 表示 buyNFT 的预检和模拟执行结果
+type BuySimulation struct {
+	PreflightChecks
+	OrderIndex   uint
+	CanFill      bool
+	RevertReason string
+	GasEstimate  uint64
+}
+
+// 模拟买家使用 permit 签名购买订单，逐项检查可能导致交易失败的原因，并在最新状态上 eth_call buyNFT
+func (uc *MarketUseCase) SimulateBuy(orderIndex uint, buyer string, deadline uint64, signature string) (*BuySimulation, error) {
+	order, err := uc.GetOrderByID(orderIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &BuySimulation{PreflightChecks: newPreflightChecks(), OrderIndex: orderIndex}
+	buyerAddress := common.HexToAddress(buyer)
+
+	// 订单状态
+	simulation.addCheck(BuyCheckOrderActive, order.Status == 0, orderStatusMessage(order.Status))
+
+	// 卖家持有NFT且市场合约已获授权
+	invalid, reason, err := uc.checkOrderValidity(order)
+	if err != nil {
+		err = fmt.Errorf("检查订单有效性失败: %w", err)
+		return nil, chainCallError(err, err)
+	}
+	if invalid && reason == OrderInvalidReasonNotOwner {
+		simulation.addCheck(BuyCheckSellerOwnsNFT, false, "卖家已不再持有该NFT")
+	} else {
+		simulation.addCheck(BuyCheckSellerOwnsNFT, true, "卖家持有该NFT")
+		if invalid {
+			simulation.addCheck(BuyCheckMarketApproved, false, "卖家已取消对市场合约的授权")
+		} else {
+			simulation.addCheck(BuyCheckMarketApproved, true, "市场合约已获授权")
+		}
+	}
+
+	// permit 签名
+	permitValid := false
+	if order.Status == 0 {
+		_, err := uc.verifyPermitSignature(orderIndex, buyer, deadline, sig)
+		switch {
+		case err == nil:
+			permitValid = true
+			simulation.addCheck(BuyCheckPermitSignature, true, "permit签名有效")
+		default:
+			// 包括签名无效以及代币不支持 EIP-2612 等情况
+			simulation.addCheck(BuyCheckPermitSignature, false, err.Error())
+		}
+	}
+
+	// 买家余额和授权额度
+	token, err := uc.tokenUC.getTokenContract(order.TokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("获取代币合约实例失败: %w", err)
+	}
+	price, ok := new(big.Int).SetString(order.Price, 10)
+	if !ok {
+		return nil, fmt.Errorf("无效的订单价格: %s", order.Price)
+	}
+	balance, err := token.BalanceOf(buyerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("获取买家余额失败: %w", err)
+	}
+	simulation.addCheck(BuyCheckBuyerBalance, balance.Cmp(price) >= 0, fmt.Sprintf("余额 %s, 需要 %s", balance, price))
+
+	allowance, err := token.Allowance(buyerAddress, uc.contract.Address())
+	if err != nil {
+		return nil, fmt.Errorf("获取买家授权额度失败: %w", err)
+	}
+	// permit 成功后授权额度会被设置为订单价格
+	allowanceOK := permitValid || allowance.Cmp(price) >= 0
+	simulation.addCheck(BuyCheckAllowance, allowanceOK, fmt.Sprintf("当前授权额度 %s, 需要 %s", allowance, price))
+
+	// 在最新状态上模拟执行
+	var r, s [32]byte
+	copy(r[:], sig[:32])
+	copy(s[:], sig[32:64])
+	v := sig[64] + 27
+	index := new(big.Int).SetUint64(uint64(orderIndex))
+	deadlineInt := new(big.Int).SetUint64(deadline)
+
+	if err := uc.contract.CallBuyNFT(buyerAddress, index, deadlineInt, v, r, s); err != nil {
+		simulation.RevertReason = utils.RevertReason(err)
+		simulation.addCheck(BuyCheckCall, false, simulation.RevertReason)
+	} else {
+		simulation.addCheck(BuyCheckCall, true, "模拟执行成功")
+		gas, err := uc.contract.EstimateBuyNFTGas(buyerAddress, index, deadlineInt, v, r, s)
+		if err != nil {
+			return nil, fmt.Errorf("估算gas失败: %w", err)
+		}
+		simulation.GasEstimate = gas
+	}
+
+	simulation.CanFill = len(simulation.Failures) == 0
+	return simulation, nil
+}
+
+// 将v/r/s拼接为65字节签名
+func JoinSignature(v uint8, r, s string) (string, error) {
+	rBytes, err := hexutil.Decode(r)
+	if err != nil || len(rBytes) != 32 {
+		return "", fmt.Errorf("%w: r 格式错误", ErrInvalidSignature)
+	}
+	sBytes, err := hexutil.Decode(s)
+	if err != nil || len(sBytes) != 32 {
+		return "", fmt.Errorf("%w: s 格式错误", ErrInvalidSignature)
+	}
+	return hexutil.Encode(append(append(rBytes, sBytes...), v)), nil
+}
+
+func orderStatusMessage(status uint) string {
+	switch status {
+	case 0:
+		return "订单可购买"
+	case 1:
+		return "订单已售出"
+	case 2:
+		return "订单已取消"
+	default:
+		return fmt.Sprintf("未知的订单状态: %d", status)
+	}
+}
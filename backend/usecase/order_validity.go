@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 订单失效原因
+const (
+	OrderInvalidReasonNotOwner    = "not_owner"
+	OrderInvalidReasonNotApproved = "not_approved"
+)
+
+// 定期全量检查未成交订单的间隔
+const orderValidityCheckInterval = 10 * time.Minute
+
+func (uc *MarketUseCase) startValidityChecker() {
+	ticker := time.NewTicker(orderValidityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uc.RevalidateActiveOrders(); err != nil {
+				log.Printf("检查订单有效性失败: %v", err)
+			}
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+// 检查所有未成交订单的有效性
+func (uc *MarketUseCase) RevalidateActiveOrders() error {
+	orders, err := uc.repo.GetActiveOrders()
+	if err != nil {
+		return fmt.Errorf("获取未成交订单失败: %w", err)
+	}
+	for i := range orders {
+		if uc.ctx.Err() != nil {
+			return nil
+		}
+		uc.revalidateOrder(&orders[i])
+	}
+	return nil
+}
+
+// NFT发生转移或授权变化后，重新检查该NFT的未成交订单
+func (uc *MarketUseCase) RevalidateNFTOrders(contractAddress string, tokenID uint) {
+	orders, err := uc.repo.GetActiveOrdersByNFT(contractAddress, tokenID)
+	if err != nil {
+		log.Printf("获取NFT未成交订单失败 (地址: %s, TokenID: %d): %v", contractAddress, tokenID, err)
+		return
+	}
+	for i := range orders {
+		uc.revalidateOrder(&orders[i])
+	}
+	if uc.listingUC != nil {
+		uc.listingUC.RevalidateNFTListings(contractAddress, tokenID)
+	}
+}
+
+// 卖家修改 ApprovalForAll 后，重新检查其在该合约下的未成交订单
+func (uc *MarketUseCase) RevalidateSellerOrders(contractAddress, seller string) {
+	orders, err := uc.repo.GetActiveOrdersBySeller(contractAddress, seller)
+	if err != nil {
+		log.Printf("获取卖家未成交订单失败 (地址: %s, 卖家: %s): %v", contractAddress, seller, err)
+		return
+	}
+	for i := range orders {
+		uc.revalidateOrder(&orders[i])
+	}
+	if uc.listingUC != nil {
+		uc.listingUC.RevalidateSellerListings(contractAddress, seller)
+	}
+}
+
+// 是否为市场合约地址
+func (uc *MarketUseCase) isMarketAddress(address common.Address) bool {
+	return address == uc.contract.Address()
+}
+
+func (uc *MarketUseCase) revalidateOrder(order *domain.Order) {
+	invalid, reason, err := uc.checkOrderValidity(order)
+	if err != nil {
+		log.Printf("检查订单有效性失败 (订单ID: %d): %v", order.ID, err)
+		return
+	}
+	if invalid == order.Invalid && reason == order.InvalidReason {
+		return
+	}
+	if err := uc.repo.UpdateOrderValidity(order.ID, invalid, reason); err != nil {
+		log.Printf("更新订单有效性失败 (订单ID: %d): %v", order.ID, err)
+		return
+	}
+	order.Invalid = invalid
+	order.InvalidReason = reason
+}
+
+// 检查卖家是否仍持有NFT且市场合约仍有转移权限
+func (uc *MarketUseCase) checkOrderValidity(order *domain.Order) (bool, string, error) {
+	nftContract, err := uc.nftUC.getNFTContract(order.NFTContractAddress)
+	if err != nil {
+		return false, "", fmt.Errorf("获取NFT合约实例失败: %w", err)
+	}
+
+	owner, err := nftContract.OwnerOf(order.TokenID)
+	if err != nil {
+		return false, "", err
+	}
+	seller := common.HexToAddress(order.Seller)
+	if common.HexToAddress(owner) != seller {
+		return true, OrderInvalidReasonNotOwner, nil
+	}
+
+	approved, err := nftContract.GetApproved(order.TokenID)
+	if err != nil {
+		return false, "", fmt.Errorf("获取NFT授权失败: %w", err)
+	}
+	if uc.isMarketAddress(approved) {
+		return false, "", nil
+	}
+
+	approvedForAll, err := nftContract.IsApprovedForAll(seller, uc.contract.Address())
+	if err != nil {
+		return false, "", fmt.Errorf("获取NFT全部授权失败: %w", err)
+	}
+	if !approvedForAll {
+		return true, OrderInvalidReasonNotApproved, nil
+	}
+	return false, "", nil
+}
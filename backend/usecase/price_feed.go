@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/contracts"
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrPriceUnavailable = domain.NewError(domain.ErrKindNotFound, "price_unavailable", "没有可用的价格")
+
+// PriceQuote 表示一个代币的美元报价
+type PriceQuote struct {
+	TokenAddress string
+	USD          float64
+	Source       string
+	UpdatedAt    time.Time // 报价源给出的更新时间，用于判断是否过期
+}
+
+// PriceFeed 是价格源适配器，不支持的代币返回 ErrPriceUnavailable
+type PriceFeed interface {
+	Name() string
+	GetUSDPrice(tokenAddress string) (*PriceQuote, error)
+}
+
+// ChainlinkPriceFeed 读取 Chainlink 聚合器的 latestRoundData
+type ChainlinkPriceFeed struct {
+	ethClientURL string
+	aggregators  map[string]string // 代币地址(小写) -> 聚合器地址
+	contracts    map[string]*contracts.PriceFeedContract
+	decimals     map[string]uint8
+	mutex        sync.Mutex
+}
+
+func NewChainlinkPriceFeed(ethClientURL string, aggregators map[string]string) *ChainlinkPriceFeed {
+	feed := &ChainlinkPriceFeed{
+		ethClientURL: ethClientURL,
+		aggregators:  make(map[string]string),
+		contracts:    make(map[string]*contracts.PriceFeedContract),
+		decimals:     make(map[string]uint8),
+	}
+	for token, aggregator := range aggregators {
+		feed.aggregators[strings.ToLower(token)] = aggregator
+	}
+	return feed
+}
+
+func (f *ChainlinkPriceFeed) Name() string {
+	return "chainlink"
+}
+
+// 获取聚合器合约实例及其精度，精度不会变化因此只读取一次
+func (f *ChainlinkPriceFeed) getAggregator(key string) (*contracts.PriceFeedContract, uint8, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if contract, exists := f.contracts[key]; exists {
+		return contract, f.decimals[key], nil
+	}
+
+	contract, err := contracts.NewPriceFeedContract(f.ethClientURL, f.aggregators[key])
+	if err != nil {
+		return nil, 0, err
+	}
+	decimals, err := contract.Decimals()
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取聚合器精度失败: %w", err)
+	}
+	f.contracts[key] = contract
+	f.decimals[key] = decimals
+	return contract, decimals, nil
+}
+
+func (f *ChainlinkPriceFeed) GetUSDPrice(tokenAddress string) (*PriceQuote, error) {
+	key := strings.ToLower(tokenAddress)
+	if _, exists := f.aggregators[key]; !exists {
+		return nil, ErrPriceUnavailable
+	}
+
+	contract, decimals, err := f.getAggregator(key)
+	if err != nil {
+		return nil, err
+	}
+	round, err := contract.LatestRoundData()
+	if err != nil {
+		return nil, fmt.Errorf("获取聚合器报价失败: %w", err)
+	}
+	if round.Answer.Sign() <= 0 {
+		return nil, fmt.Errorf("聚合器报价无效: %s", round.Answer)
+	}
+
+	price, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(round.Answer),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	).Float64()
+	return &PriceQuote{
+		TokenAddress: common.HexToAddress(tokenAddress).Hex(),
+		USD:          price,
+		Source:       f.Name(),
+		UpdatedAt:    round.UpdatedAt,
+	}, nil
+}
+
+// StaticPriceFeed 使用配置中的固定价格，适合稳定币或测试代币
+type StaticPriceFeed struct {
+	prices map[string]float64 // 代币地址(小写) -> 美元价格
+}
+
+func NewStaticPriceFeed(prices map[string]float64) *StaticPriceFeed {
+	feed := &StaticPriceFeed{prices: make(map[string]float64)}
+	for token, price := range prices {
+		feed.prices[strings.ToLower(token)] = price
+	}
+	return feed
+}
+
+func (f *StaticPriceFeed) Name() string {
+	return "static"
+}
+
+func (f *StaticPriceFeed) GetUSDPrice(tokenAddress string) (*PriceQuote, error) {
+	price, exists := f.prices[strings.ToLower(tokenAddress)]
+	if !exists {
+		return nil, ErrPriceUnavailable
+	}
+	// 固定价格永不过期
+	return &PriceQuote{
+		TokenAddress: common.HexToAddress(tokenAddress).Hex(),
+		USD:          price,
+		Source:       f.Name(),
+		UpdatedAt:    time.Now(),
+	}, nil
+}
+
+// HTTPPriceFeed 请求 {baseURL}?token=<地址>，响应格式为 {"price": 1.23, "updatedAt": 1700000000}，404 表示不支持该代币
+type HTTPPriceFeed struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPPriceFeed(baseURL string) *HTTPPriceFeed {
+	return &HTTPPriceFeed{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (f *HTTPPriceFeed) Name() string {
+	return "http"
+}
+
+func (f *HTTPPriceFeed) GetUSDPrice(tokenAddress string) (*PriceQuote, error) {
+	requestURL, err := url.Parse(f.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的价格接口地址: %w", err)
+	}
+	query := requestURL.Query()
+	query.Set("token", common.HexToAddress(tokenAddress).Hex())
+	requestURL.RawQuery = query.Encode()
+
+	resp, err := f.client.Get(requestURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("请求价格接口失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrPriceUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("价格接口返回错误状态: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Price     float64 `json:"price"`
+		UpdatedAt int64   `json:"updatedAt"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("解析价格接口响应失败: %w", err)
+	}
+	if body.Price <= 0 {
+		return nil, fmt.Errorf("价格接口报价无效: %v", body.Price)
+	}
+
+	updatedAt := time.Now()
+	if body.UpdatedAt > 0 {
+		updatedAt = time.Unix(body.UpdatedAt, 0)
+	}
+	return &PriceQuote{
+		TokenAddress: common.HexToAddress(tokenAddress).Hex(),
+		USD:          body.Price,
+		Source:       f.Name(),
+		UpdatedAt:    updatedAt,
+	}, nil
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// K线周期
+const (
+	CandleInterval1h = "1h"
+	CandleInterval1d = "1d"
+	CandleInterval1w = "1w"
+)
+
+var ErrInvalidInterval = domain.NewError(domain.ErrKindInvalid, "invalid_interval", "无效的K线周期")
+
+var candleIntervals = map[string]time.Duration{
+	CandleInterval1h: time.Hour,
+	CandleInterval1d: 24 * time.Hour,
+	CandleInterval1w: 7 * 24 * time.Hour,
+}
+
+// 计算成交时间所在的K线起始时间（UTC），周K线从周一开始
+func candleBucketStart(t time.Time, interval string) time.Time {
+	return t.UTC().Truncate(candleIntervals[interval])
+}
+
+// 将一笔成交计入K线，成交可以乱序到达
+func applySaleToCandle(candle *domain.PriceCandle, sale *domain.Sale) {
+	price, ok := new(big.Int).SetString(sale.Price, 10)
+	if !ok {
+		return
+	}
+
+	if candle.Count == 0 {
+		candle.Open, candle.High, candle.Low, candle.Close = sale.Price, sale.Price, sale.Price, sale.Price
+		candle.OpenAt, candle.CloseAt = sale.BlockTimestamp, sale.BlockTimestamp
+		candle.Volume = "0"
+	} else {
+		if high, _ := new(big.Int).SetString(candle.High, 10); high == nil || price.Cmp(high) > 0 {
+			candle.High = sale.Price
+		}
+		if low, _ := new(big.Int).SetString(candle.Low, 10); low == nil || price.Cmp(low) < 0 {
+			candle.Low = sale.Price
+		}
+		if sale.BlockTimestamp.Before(candle.OpenAt) {
+			candle.Open, candle.OpenAt = sale.Price, sale.BlockTimestamp
+		}
+		if !sale.BlockTimestamp.Before(candle.CloseAt) {
+			candle.Close, candle.CloseAt = sale.Price, sale.BlockTimestamp
+		}
+	}
+
+	volume, ok := new(big.Int).SetString(candle.Volume, 10)
+	if !ok {
+		volume = new(big.Int)
+	}
+	volume.Add(volume, price)
+	candle.Count++
+	candle.Volume = volume.String()
+	candle.Average = new(big.Int).Div(volume, new(big.Int).SetUint64(uint64(candle.Count))).String()
+}
+
+// 根据 OrderFulfilled 事件和订单数据构建成交记录
+func (uc *MarketUseCase) saleFromEvent(event *types.Log) (*domain.Sale, error) {
+	orderID := new(big.Int).SetBytes(event.Topics[1].Bytes()).Uint64()
+	buyer := common.BytesToAddress(event.Data[:32])
+
+	order, err := uc.repo.GetOrderByID(uint(orderID + 1))
+	if err != nil {
+		return nil, fmt.Errorf("获取成交订单失败 (订单ID: %d): %w", orderID, err)
+	}
+
+	timestamp, err := uc.contract.GetBlockTimestamp(event.BlockNumber)
+	if err != nil {
+		log.Printf("获取区块时间戳失败: %v", err)
+		timestamp = 0 // 如果获取失败,使用0作为默认值
+	}
+
+	return &domain.Sale{
+		OrderID:            order.ID,
+		NFTContractAddress: order.NFTContractAddress,
+		TokenID:            order.TokenID,
+		TokenAddress:       order.TokenAddress,
+		Price:              order.Price,
+		Seller:             order.Seller,
+		Buyer:              buyer.Hex(),
+		TransactionHash:    event.TxHash.Hex(),
+		BlockNumber:        uint(event.BlockNumber),
+		BlockTimestamp:     time.Unix(int64(timestamp), 0),
+	}, nil
+}
+
+// 记录 OrderFulfilled 事件对应的成交，并更新各周期的K线
+func (uc *MarketUseCase) recordSale(event *types.Log) error {
+	sale, err := uc.saleFromEvent(event)
+	if err != nil {
+		return err
+	}
+
+	buckets := make(map[string]time.Time, len(candleIntervals))
+	for interval := range candleIntervals {
+		buckets[interval] = candleBucketStart(sale.BlockTimestamp, interval)
+	}
+
+	uc.historyMutex.Lock()
+	defer uc.historyMutex.Unlock()
+
+	// 成交记录和K线在同一事务中更新，已记录的成交不重复计入K线
+	recorded, err := uc.historyRepo.RecordSale(sale, buckets, func(candle *domain.PriceCandle) {
+		applySaleToCandle(candle, sale)
+	})
+	if err != nil {
+		return fmt.Errorf("保存成交记录失败: %w", err)
+	}
+	if !recorded {
+		return nil
+	}
+
+	if uc.watchlistUC != nil {
+		uc.watchlistUC.recordSale(orderActivityReference(sale.OrderID), sale.NFTContractAddress, sale.TokenID, sale.TokenAddress, sale.Price, sale.Seller, sale.Buyer, sale.TransactionHash, sale.BlockTimestamp)
+	}
+	uc.alertUC.evaluateSale(orderActivityReference(sale.OrderID), sale.NFTContractAddress, sale.TokenID, sale.TokenAddress, sale.Price, sale.Seller, sale.Buyer)
+	uc.notificationUC.notifySale(orderActivityReference(sale.OrderID), sale.NFTContractAddress, sale.TokenID, sale.TokenAddress, sale.Price, sale.Seller, sale.Buyer, sale.TransactionHash)
+	return nil
+}
+
+// 从链上重新扫描所有 OrderFulfilled 事件，重建成交记录和K线
+func (uc *MarketUseCase) scanHistoricalSales() error {
+	creationBlock, err := uc.contract.GetCreationBlockNumber()
+	if err != nil {
+		return fmt.Errorf("获取合约创建区块号失败: %w", err)
+	}
+	latestBlock, err := uc.contract.GetLatestBlockNumber()
+	if err != nil {
+		return fmt.Errorf("获取最新区块号失败: %w", err)
+	}
+
+	logs, err := uc.contract.GetOrderFulfilledEvents(new(big.Int).SetUint64(creationBlock), new(big.Int).SetUint64(latestBlock))
+	if err != nil {
+		return err
+	}
+
+	// 全部事件获取成功后再替换已保存的成交记录
+	sales := make([]domain.Sale, 0, len(logs))
+	for i := range logs {
+		sale, err := uc.saleFromEvent(&logs[i])
+		if err != nil {
+			log.Printf("记录历史成交失败: %v", err)
+			continue
+		}
+		sales = append(sales, *sale)
+	}
+	uc.historyMutex.Lock()
+	err = uc.historyRepo.ReplaceSales(sales)
+	uc.historyMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("保存历史成交失败: %w", err)
+	}
+
+	return uc.RebuildPriceHistory()
+}
+
+// 根据成交记录重建所有K线
+func (uc *MarketUseCase) RebuildPriceHistory() error {
+	uc.historyMutex.Lock()
+	defer uc.historyMutex.Unlock()
+
+	sales, err := uc.historyRepo.GetAllSales()
+	if err != nil {
+		return fmt.Errorf("获取成交记录失败: %w", err)
+	}
+	if err := uc.historyRepo.ReplaceCandles(buildCandles(sales)); err != nil {
+		return fmt.Errorf("保存K线失败: %w", err)
+	}
+	return nil
+}
+
+// 按NFT系列、支付代币和周期将成交聚合为K线，按首次出现的顺序返回
+func buildCandles(sales []domain.Sale) []domain.PriceCandle {
+	type candleKey struct {
+		contractAddress, tokenAddress, interval string
+		bucketStart                             time.Time
+	}
+	candles := make(map[candleKey]*domain.PriceCandle)
+	keys := make([]candleKey, 0)
+	for i := range sales {
+		sale := &sales[i]
+		for interval := range candleIntervals {
+			key := candleKey{sale.NFTContractAddress, sale.TokenAddress, interval, candleBucketStart(sale.BlockTimestamp, interval)}
+			candle, exists := candles[key]
+			if !exists {
+				candle = &domain.PriceCandle{
+					NFTContractAddress: key.contractAddress,
+					TokenAddress:       key.tokenAddress,
+					Interval:           key.interval,
+					BucketStart:        key.bucketStart,
+				}
+				candles[key] = candle
+				keys = append(keys, key)
+			}
+			applySaleToCandle(candle, sale)
+		}
+	}
+
+	result := make([]domain.PriceCandle, len(keys))
+	for i, key := range keys {
+		result[i] = *candles[key]
+	}
+	return result
+}
+
+// 获取NFT系列的价格K线，tokenAddress 为空时返回所有支付代币
+func (uc *MarketUseCase) GetPriceHistory(contractAddress, interval, tokenAddress string, from, to time.Time) ([]domain.PriceCandleView, error) {
+	if _, exists := candleIntervals[interval]; !exists {
+		return nil, fmt.Errorf("%w，可选值为 1h、1d、1w", ErrInvalidInterval)
+	}
+	if tokenAddress != "" {
+		tokenAddress = common.HexToAddress(tokenAddress).Hex()
+	}
+
+	candles, err := uc.historyRepo.GetCandles(common.HexToAddress(contractAddress).Hex(), tokenAddress, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return uc.tokenUC.DescribeCandles(candles), nil
+}
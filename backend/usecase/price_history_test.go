@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"backend/domain"
+)
+
+func TestCandleBucketStart(t *testing.T) {
+	shanghai := time.FixedZone("UTC+8", 8*3600)
+	tests := []struct {
+		name     string
+		at       time.Time
+		interval string
+		want     time.Time
+	}{
+		{"小时", time.Date(2024, 1, 3, 10, 59, 59, 0, time.UTC), CandleInterval1h, time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)},
+		{"整点属于当前小时", time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC), CandleInterval1h, time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"天", time.Date(2024, 1, 3, 23, 59, 0, 0, time.UTC), CandleInterval1d, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"非 UTC 时间按 UTC 划分天", time.Date(2024, 1, 4, 7, 0, 0, 0, shanghai), CandleInterval1d, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"周从周一开始", time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC), CandleInterval1w, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"周日属于本周", time.Date(2024, 1, 7, 23, 59, 59, 0, time.UTC), CandleInterval1w, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"周一开始新的一周", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), CandleInterval1w, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{"跨年的周", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), CandleInterval1w, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := candleBucketStart(tt.at, tt.interval); !got.Equal(tt.want) {
+				t.Fatalf("candleBucketStart(%s, %s) = %s，应为 %s", tt.at, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySaleToCandleIgnoresInvalidPrice(t *testing.T) {
+	candle := &domain.PriceCandle{}
+	applySaleToCandle(candle, &domain.Sale{Price: "abc", BlockTimestamp: time.Now()})
+	if candle.Count != 0 || candle.Volume != "" {
+		t.Fatalf("无效价格不应计入K线，实际为 %+v", candle)
+	}
+}
+
+func TestBuildCandles(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	sale := func(tokenAddress, price string, soldAt time.Time) domain.Sale {
+		return domain.Sale{NFTContractAddress: "0xC", TokenAddress: tokenAddress, Price: price, BlockTimestamp: soldAt}
+	}
+	sales := []domain.Sale{
+		sale("0xT1", "100", at(3, 10, 15)),
+		sale("0xT1", "300", at(3, 10, 45)),
+		sale("0xT1", "200", at(3, 10, 5)), // 乱序到达，成为开盘价
+		sale("0xT1", "50", at(3, 11, 0)),
+		sale("0xT2", "7", at(3, 10, 30)), // 不同支付代币单独统计
+		sale("0xT1", "10", at(7, 23, 59)),
+		sale("0xT1", "20", at(8, 0, 0)),
+	}
+
+	type candleKey struct {
+		tokenAddress, interval string
+		bucketStart            time.Time
+	}
+	type candleValue struct {
+		open, high, low, close, volume, average string
+		count                                   uint
+		openAt, closeAt                         time.Time
+	}
+	want := map[candleKey]candleValue{
+		{"0xT1", CandleInterval1h, at(3, 10, 0)}: {"200", "300", "100", "300", "600", "200", 3, at(3, 10, 5), at(3, 10, 45)},
+		{"0xT1", CandleInterval1h, at(3, 11, 0)}: {"50", "50", "50", "50", "50", "50", 1, at(3, 11, 0), at(3, 11, 0)},
+		{"0xT1", CandleInterval1h, at(7, 23, 0)}: {"10", "10", "10", "10", "10", "10", 1, at(7, 23, 59), at(7, 23, 59)},
+		{"0xT1", CandleInterval1h, at(8, 0, 0)}:  {"20", "20", "20", "20", "20", "20", 1, at(8, 0, 0), at(8, 0, 0)},
+		{"0xT1", CandleInterval1d, at(3, 0, 0)}:  {"200", "300", "50", "50", "650", "162", 4, at(3, 10, 5), at(3, 11, 0)},
+		{"0xT1", CandleInterval1d, at(7, 0, 0)}:  {"10", "10", "10", "10", "10", "10", 1, at(7, 23, 59), at(7, 23, 59)},
+		{"0xT1", CandleInterval1d, at(8, 0, 0)}:  {"20", "20", "20", "20", "20", "20", 1, at(8, 0, 0), at(8, 0, 0)},
+		{"0xT1", CandleInterval1w, at(1, 0, 0)}:  {"200", "300", "10", "10", "660", "132", 5, at(3, 10, 5), at(7, 23, 59)},
+		{"0xT1", CandleInterval1w, at(8, 0, 0)}:  {"20", "20", "20", "20", "20", "20", 1, at(8, 0, 0), at(8, 0, 0)},
+		{"0xT2", CandleInterval1h, at(3, 10, 0)}: {"7", "7", "7", "7", "7", "7", 1, at(3, 10, 30), at(3, 10, 30)},
+		{"0xT2", CandleInterval1d, at(3, 0, 0)}:  {"7", "7", "7", "7", "7", "7", 1, at(3, 10, 30), at(3, 10, 30)},
+		{"0xT2", CandleInterval1w, at(1, 0, 0)}:  {"7", "7", "7", "7", "7", "7", 1, at(3, 10, 30), at(3, 10, 30)},
+	}
+
+	candles := buildCandles(sales)
+	if len(candles) != len(want) {
+		t.Fatalf("K线数量为 %d，应为 %d", len(candles), len(want))
+	}
+	for _, candle := range candles {
+		if candle.NFTContractAddress != "0xC" {
+			t.Errorf("K线的NFT系列为 %s，应为 0xC", candle.NFTContractAddress)
+		}
+		key := candleKey{candle.TokenAddress, candle.Interval, candle.BucketStart}
+		w, exists := want[key]
+		if !exists {
+			t.Errorf("多余的K线 %+v", key)
+			continue
+		}
+		got := candleValue{candle.Open, candle.High, candle.Low, candle.Close, candle.Volume, candle.Average, candle.Count, candle.OpenAt, candle.CloseAt}
+		if got != w {
+			t.Errorf("K线 %+v 为 %+v，应为 %+v", key, got, w)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认的报价缓存时间和最大允许的报价延迟
+const (
+	defaultPriceCacheTTL     = time.Minute
+	defaultPriceMaxStaleness = 2 * time.Hour
+)
+
+// PriceOracleConfig 表示价格源配置，按 Chainlink、HTTP、固定价格的顺序依次尝试
+type PriceOracleConfig struct {
+	Chainlink           map[string]string  `json:"chainlink"` // 代币地址 -> 聚合器地址
+	HTTP                string             `json:"http"`      // HTTP 价格接口地址，为空时不启用
+	Static              map[string]float64 `json:"static"`    // 代币地址 -> 美元价格
+	CacheTTLSeconds     int64              `json:"cacheTTLSeconds"`
+	MaxStalenessSeconds int64              `json:"maxStalenessSeconds"`
+}
+
+// quote 为 nil 表示所有价格源都没有可用报价，在缓存时间内不再重复查询
+type cachedQuote struct {
+	quote     *PriceQuote
+	fetchedAt time.Time
+}
+
+type PriceOracleUseCase struct {
+	feeds        []PriceFeed
+	cacheTTL     time.Duration
+	maxStaleness time.Duration
+	cache        map[string]cachedQuote
+	mutex        sync.RWMutex
+}
+
+func NewPriceOracleUseCase(feeds []PriceFeed, cacheTTL, maxStaleness time.Duration) *PriceOracleUseCase {
+	if cacheTTL <= 0 {
+		cacheTTL = defaultPriceCacheTTL
+	}
+	if maxStaleness <= 0 {
+		maxStaleness = defaultPriceMaxStaleness
+	}
+	return &PriceOracleUseCase{
+		feeds:        feeds,
+		cacheTTL:     cacheTTL,
+		maxStaleness: maxStaleness,
+		cache:        make(map[string]cachedQuote),
+	}
+}
+
+// 根据配置创建价格源
+func NewPriceOracleFromConfig(config PriceOracleConfig, ethClientURL string) *PriceOracleUseCase {
+	feeds := make([]PriceFeed, 0)
+	if len(config.Chainlink) > 0 {
+		feeds = append(feeds, NewChainlinkPriceFeed(ethClientURL, config.Chainlink))
+	}
+	if config.HTTP != "" {
+		feeds = append(feeds, NewHTTPPriceFeed(config.HTTP))
+	}
+	if len(config.Static) > 0 {
+		feeds = append(feeds, NewStaticPriceFeed(config.Static))
+	}
+	return NewPriceOracleUseCase(feeds,
+		time.Duration(config.CacheTTLSeconds)*time.Second,
+		time.Duration(config.MaxStalenessSeconds)*time.Second)
+}
+
+func (uc *PriceOracleUseCase) isStale(quote *PriceQuote) bool {
+	return time.Since(quote.UpdatedAt) > uc.maxStaleness
+}
+
+// 获取代币的美元报价，依次尝试各价格源，过期的报价视为不可用
+func (uc *PriceOracleUseCase) GetUSDPrice(tokenAddress string) (*PriceQuote, error) {
+	key := strings.ToLower(tokenAddress)
+
+	uc.mutex.RLock()
+	cached, exists := uc.cache[key]
+	uc.mutex.RUnlock()
+	if exists && time.Since(cached.fetchedAt) < uc.cacheTTL {
+		if cached.quote == nil {
+			return nil, ErrPriceUnavailable
+		}
+		if !uc.isStale(cached.quote) {
+			return cached.quote, nil
+		}
+	}
+
+	for _, feed := range uc.feeds {
+		quote, err := feed.GetUSDPrice(tokenAddress)
+		if err != nil {
+			if !errors.Is(err, ErrPriceUnavailable) {
+				log.Printf("价格源 %s 获取报价失败 (代币: %s): %v", feed.Name(), tokenAddress, err)
+			}
+			continue
+		}
+		if uc.isStale(quote) {
+			log.Printf("价格源 %s 的报价已过期 (代币: %s, 更新时间: %s)", feed.Name(), tokenAddress, quote.UpdatedAt)
+			continue
+		}
+
+		uc.mutex.Lock()
+		uc.cache[key] = cachedQuote{quote: quote, fetchedAt: time.Now()}
+		uc.mutex.Unlock()
+		return quote, nil
+	}
+
+	// 所有价格源都失败时，仍未过期的缓存报价可以继续使用
+	if exists && cached.quote != nil && !uc.isStale(cached.quote) {
+		return cached.quote, nil
+	}
+	// 没有可用报价时同样缓存，避免每次请求都重新查询失败的价格源
+	uc.mutex.Lock()
+	uc.cache[key] = cachedQuote{fetchedAt: time.Now()}
+	uc.mutex.Unlock()
+	return nil, ErrPriceUnavailable
+}
+
+// 批量获取代币的美元报价，每个代币只查询一次，结果以小写地址为键，没有可用报价的代币不在结果中
+func (uc *PriceOracleUseCase) USDPrices(tokenAddresses []string) map[string]*PriceQuote {
+	quotes := make(map[string]*PriceQuote)
+	queried := make(map[string]bool)
+	for _, address := range tokenAddresses {
+		key := strings.ToLower(address)
+		if queried[key] {
+			continue
+		}
+		queried[key] = true
+		if quote, err := uc.GetUSDPrice(address); err == nil {
+			quotes[key] = quote
+		}
+	}
+	return quotes
+}
+
+// 将按精度表示的代币金额换算为美元，没有可用报价时返回 nil
+func (uc *PriceOracleUseCase) ToUSD(tokenAddress, amount string, decimals uint8) *float64 {
+	quote, err := uc.GetUSDPrice(tokenAddress)
+	if err != nil {
+		return nil
+	}
+	return quoteToUSD(quote, amount, decimals)
+}
+
+// 按报价将代币金额换算为美元，quote 为 nil 或金额无效时返回 nil
+func quoteToUSD(quote *PriceQuote, amount string, decimals uint8) *float64 {
+	raw, ok := new(big.Int).SetString(amount, 10)
+	if !ok || quote == nil {
+		return nil
+	}
+
+	value := new(big.Float).Quo(
+		new(big.Float).SetInt(raw),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	)
+	usd, _ := value.Mul(value, big.NewFloat(quote.USD)).Float64()
+	return &usd
+}
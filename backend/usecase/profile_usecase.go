@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidProfile = domain.NewError(domain.ErrKindInvalid, "invalid_profile", "无效的资料")
+	ErrUsernameTaken  = domain.NewError(domain.ErrKindConflict, "username_taken", "用户名已被占用")
+)
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+	twitterPattern  = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+)
+
+// ProfileRequest 表示资料的修改内容，字段为空表示清除
+type ProfileRequest struct {
+	Username         string
+	DisplayName      string
+	Bio              string
+	AvatarNFTAddress string
+	AvatarTokenID    uint
+	Twitter          string
+	Discord          string
+	Website          string
+}
+
+type ProfileUseCase struct {
+	profileRepo *repository.ProfileRepository
+	nftRepo     *repository.NFTRepository
+}
+
+// 创建后挂到 tokenUC、nftUC 和 marketUC 上，由它们在返回地址时附加资料摘要
+func NewProfileUseCase(profileRepo *repository.ProfileRepository, nftRepo *repository.NFTRepository, tokenUC *TokenUseCase, nftUC *NFTUseCase, marketUC *MarketUseCase) *ProfileUseCase {
+	uc := &ProfileUseCase{
+		profileRepo: profileRepo,
+		nftRepo:     nftRepo,
+	}
+	tokenUC.profileUC = uc
+	nftUC.profileUC = uc
+	marketUC.profileUC = uc
+	return uc
+}
+
+// 获取地址的资料，address 也可以是用户名
+func (uc *ProfileUseCase) GetProfile(addressOrUsername string) (*domain.Profile, error) {
+	var profile *domain.Profile
+	var err error
+	if common.IsHexAddress(addressOrUsername) {
+		profile, err = uc.profileRepo.GetProfileByAddress(common.HexToAddress(addressOrUsername).Hex())
+	} else {
+		profile, err = uc.profileRepo.GetProfileByUsername(addressOrUsername)
+	}
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrProfileNotFound)
+	}
+	return profile, nil
+}
+
+// 校验并保存登录地址的资料，头像NFT必须由该地址持有
+func (uc *ProfileUseCase) UpdateProfile(address string, req ProfileRequest) (*domain.Profile, error) {
+	address = common.HexToAddress(address).Hex()
+	profile := &domain.Profile{Address: address}
+	if existing, err := uc.profileRepo.GetProfileByAddress(address); err == nil {
+		profile = existing
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+
+	profile.Username = nil
+	if req.Username != "" {
+		if !usernamePattern.MatchString(req.Username) {
+			return nil, fmt.Errorf("%w: 用户名只能包含字母、数字和下划线，长度3到30", ErrInvalidProfile)
+		}
+		if common.IsHexAddress(req.Username) {
+			return nil, fmt.Errorf("%w: 用户名不能是地址", ErrInvalidProfile)
+		}
+		if owner, err := uc.profileRepo.GetProfileByUsername(req.Username); err == nil && owner.Address != address {
+			return nil, ErrUsernameTaken
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("检查用户名失败: %w", err)
+		}
+		username := req.Username
+		profile.Username = &username
+	}
+
+	if utf8.RuneCountInString(req.DisplayName) > 50 {
+		return nil, fmt.Errorf("%w: 显示名称不能超过50个字符", ErrInvalidProfile)
+	}
+	if utf8.RuneCountInString(req.Bio) > 500 {
+		return nil, fmt.Errorf("%w: 简介不能超过500个字符", ErrInvalidProfile)
+	}
+	profile.DisplayName = strings.TrimSpace(req.DisplayName)
+	profile.Bio = strings.TrimSpace(req.Bio)
+
+	profile.Twitter = strings.TrimPrefix(req.Twitter, "@")
+	if profile.Twitter != "" && !twitterPattern.MatchString(profile.Twitter) {
+		return nil, fmt.Errorf("%w: Twitter 用户名格式错误", ErrInvalidProfile)
+	}
+	if utf8.RuneCountInString(req.Discord) > 64 {
+		return nil, fmt.Errorf("%w: Discord 用户名不能超过64个字符", ErrInvalidProfile)
+	}
+	profile.Discord = req.Discord
+	if req.Website != "" {
+		website, err := url.Parse(req.Website)
+		if err != nil || (website.Scheme != "http" && website.Scheme != "https") || website.Host == "" || len(req.Website) > 255 {
+			return nil, fmt.Errorf("%w: 网站必须为 http 或 https 链接", ErrInvalidProfile)
+		}
+	}
+	profile.Website = req.Website
+
+	profile.AvatarNFTAddress, profile.AvatarTokenID, profile.AvatarImage = "", 0, ""
+	if req.AvatarNFTAddress != "" {
+		if !common.IsHexAddress(req.AvatarNFTAddress) {
+			return nil, fmt.Errorf("%w: 头像NFT合约地址格式错误", ErrInvalidProfile)
+		}
+		nftAddress := common.HexToAddress(req.AvatarNFTAddress).Hex()
+		nft, err := uc.nftRepo.GetByTokenID(nftAddress, req.AvatarTokenID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 头像NFT未找到", ErrInvalidProfile)
+		}
+		if !strings.EqualFold(nft.Owner, address) {
+			return nil, fmt.Errorf("%w: 只能使用自己持有的NFT作为头像", ErrInvalidProfile)
+		}
+		profile.AvatarNFTAddress, profile.AvatarTokenID, profile.AvatarImage = nftAddress, nft.TokenID, nft.Image
+	}
+
+	if err := uc.profileRepo.SaveProfile(profile); err != nil {
+		return nil, fmt.Errorf("保存资料失败: %w", err)
+	}
+	return profile, nil
+}
+
+// NFT转移后，原持有者使用该NFT的头像失效
+func (uc *ProfileUseCase) handleTransfer(nftAddress string, tokenID uint, newOwner string) {
+	if err := uc.profileRepo.ClearAvatar(nftAddress, tokenID, newOwner); err != nil {
+		log.Printf("清除头像失败: %v", err)
+	}
+}
+
+// 批量获取地址的资料摘要，key 为小写地址；uc 为 nil 时返回空结果
+func (uc *ProfileUseCase) Summaries(addresses ...string) map[string]*domain.ProfileSummary {
+	summaries := make(map[string]*domain.ProfileSummary)
+	if uc == nil {
+		return summaries
+	}
+
+	unique := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		key := strings.ToLower(address)
+		if address != "" && !seen[key] {
+			seen[key] = true
+			unique = append(unique, address)
+		}
+	}
+	if len(unique) == 0 {
+		return summaries
+	}
+
+	profiles, err := uc.profileRepo.GetProfilesByAddresses(unique)
+	if err != nil {
+		log.Printf("获取资料失败: %v", err)
+		return summaries
+	}
+	for _, profile := range profiles {
+		summary := &domain.ProfileSummary{
+			Address:     profile.Address,
+			DisplayName: profile.DisplayName,
+			AvatarImage: profile.AvatarImage,
+		}
+		if profile.Username != nil {
+			summary.Username = *profile.Username
+		}
+		summaries[strings.ToLower(profile.Address)] = summary
+	}
+	return summaries
+}
+
+// 为NFT附加持有者资料
+func (uc *ProfileUseCase) DescribeNFTs(nfts []domain.NFT) []domain.NFTView {
+	owners := make([]string, len(nfts))
+	for i, nft := range nfts {
+		owners[i] = nft.Owner
+	}
+	profiles := uc.Summaries(owners...)
+
+	views := make([]domain.NFTView, len(nfts))
+	for i, nft := range nfts {
+		views[i] = domain.NFTView{NFT: nft, OwnerProfile: profiles[strings.ToLower(nft.Owner)]}
+	}
+	return views
+}
+
+// 为转移事件附加转出方和转入方资料
+func (uc *ProfileUseCase) DescribeTransferEvents(events []domain.NFTTransferEvent) []domain.NFTTransferEventView {
+	addresses := make([]string, 0, len(events)*2)
+	for _, event := range events {
+		addresses = append(addresses, event.FromAddress, event.ToAddress)
+	}
+	profiles := uc.Summaries(addresses...)
+
+	views := make([]domain.NFTTransferEventView, len(events))
+	for i, event := range events {
+		views[i] = domain.NFTTransferEventView{
+			NFTTransferEvent: event,
+			FromProfile:      profiles[strings.ToLower(event.FromAddress)],
+			ToProfile:        profiles[strings.ToLower(event.ToAddress)],
+		}
+	}
+	return views
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 同一区块内事件的先后顺序：先挂单，再转移/成交，最后撤单
+var provenanceTypeOrder = map[string]int{
+	domain.ProvenanceListed:   0,
+	domain.ProvenanceMint:     1,
+	domain.ProvenanceTransfer: 1,
+	domain.ProvenanceSale:     1,
+	domain.ProvenanceDelisted: 2,
+}
+
+// 合并转移记录、成交记录和链上挂单/撤单事件，生成NFT的溯源记录和各持有者的持有期
+func (uc *MarketUseCase) GetNFTProvenance(contractAddress string, tokenID uint) (*domain.Provenance, error) {
+	contractAddress = common.HexToAddress(contractAddress).Hex()
+
+	transfers, err := uc.nftRepo.GetNFTTransferEvents(contractAddress, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := uc.historyRepo.GetSalesByNFT(contractAddress, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := uc.repo.GetOrdersByNFT(contractAddress, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.ProvenanceEvent, 0)
+
+	// 转移与成交在同一笔交易中的视为市场成交，其余为私下转移
+	salesByTx := make(map[string]*domain.Sale)
+	for i := range sales {
+		salesByTx[strings.ToLower(sales[i].TransactionHash)] = &sales[i]
+	}
+	for _, transfer := range transfers {
+		// MetadataUpdate 事件会额外记录一条接收方为NFT合约本身的 mint，与 Transfer 记录的铸造重复
+		if transfer.EventType == "mint" && strings.EqualFold(transfer.ToAddress, contractAddress) {
+			continue
+		}
+		event := domain.ProvenanceEvent{
+			Type:            domain.ProvenanceTransfer,
+			From:            transfer.FromAddress,
+			To:              transfer.ToAddress,
+			TransactionHash: transfer.TransactionHash,
+			BlockNumber:     transfer.BlockNumber,
+			Timestamp:       transfer.BlockTimestamp,
+		}
+		if transfer.EventType == "mint" {
+			event.Type = domain.ProvenanceMint
+		} else if sale, exists := salesByTx[strings.ToLower(transfer.TransactionHash)]; exists {
+			orderIndex := sale.OrderID - 1
+			event.Type = domain.ProvenanceSale
+			event.OrderIndex = &orderIndex
+			event.TokenAddress = sale.TokenAddress
+			event.Price = sale.Price
+			delete(salesByTx, strings.ToLower(transfer.TransactionHash))
+		}
+		events = append(events, event)
+	}
+	// 转移记录缺失时仍保留成交
+	for _, sale := range salesByTx {
+		orderIndex := sale.OrderID - 1
+		events = append(events, domain.ProvenanceEvent{
+			Type:            domain.ProvenanceSale,
+			From:            sale.Seller,
+			To:              sale.Buyer,
+			OrderIndex:      &orderIndex,
+			TokenAddress:    sale.TokenAddress,
+			Price:           sale.Price,
+			TransactionHash: sale.TransactionHash,
+			BlockNumber:     sale.BlockNumber,
+			Timestamp:       sale.BlockTimestamp,
+		})
+	}
+
+	// 挂单和撤单记录，获取失败时省略这部分记录
+	listings, err := uc.orderProvenanceEvents(contractAddress, tokenID, orders)
+	if err != nil {
+		log.Printf("获取NFT挂单事件失败: %v", err)
+	} else {
+		events = append(events, listings...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return provenanceTypeOrder[events[i].Type] < provenanceTypeOrder[events[j].Type]
+	})
+
+	addresses := make([]string, 0)
+	for _, event := range events {
+		if event.TokenAddress != "" {
+			addresses = append(addresses, event.TokenAddress)
+		}
+	}
+	tokens := uc.tokenUC.lookupTokens(addresses)
+	for i := range events {
+		events[i].PriceFormatted = events[i].Price
+		if token, exists := tokens[strings.ToLower(events[i].TokenAddress)]; exists {
+			events[i].PaymentToken = token
+			events[i].PriceFormatted = FormatTokenAmount(events[i].Price, token.Decimals)
+		}
+	}
+
+	holdings := buildHoldings(events, tokens)
+	parties := make([]string, 0, len(events)*2)
+	for _, event := range events {
+		parties = append(parties, event.From, event.To)
+	}
+	profiles := uc.profileUC.Summaries(parties...)
+	for i := range events {
+		events[i].FromProfile = profiles[strings.ToLower(events[i].From)]
+		events[i].ToProfile = profiles[strings.ToLower(events[i].To)]
+	}
+	for i := range holdings {
+		holdings[i].OwnerProfile = profiles[strings.ToLower(holdings[i].Owner)]
+	}
+
+	return &domain.Provenance{
+		ContractAddress: contractAddress,
+		TokenID:         tokenID,
+		Events:          events,
+		Holdings:        holdings,
+	}, nil
+}
+
+// 从保存的挂单和撤单事件生成溯源记录，价格、代币和卖家取自对应订单
+func (uc *MarketUseCase) orderProvenanceEvents(contractAddress string, tokenID uint, orders []domain.Order) ([]domain.ProvenanceEvent, error) {
+	ordersByID := make(map[uint]domain.Order, len(orders))
+	for _, order := range orders {
+		ordersByID[order.ID] = order
+	}
+
+	orderEvents, err := uc.repo.GetOrderEventsByNFT(contractAddress, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.ProvenanceEvent, 0, len(orderEvents))
+	for _, orderEvent := range orderEvents {
+		order, exists := ordersByID[orderEvent.OrderID]
+		if !exists {
+			continue
+		}
+		orderIndex := order.ID - 1
+		events = append(events, domain.ProvenanceEvent{
+			Type:            orderEvent.EventType,
+			From:            order.Seller,
+			OrderIndex:      &orderIndex,
+			TokenAddress:    order.TokenAddress,
+			Price:           order.Price,
+			TransactionHash: orderEvent.TransactionHash,
+			BlockNumber:     orderEvent.BlockNumber,
+			Timestamp:       orderEvent.BlockTimestamp,
+		})
+	}
+	return events, nil
+}
+
+// 根据 OrderCreated/OrderCancelled 事件生成挂单或撤单记录，order 为事件对应的订单
+func (uc *MarketUseCase) orderEventFromLog(event *types.Log, order *domain.Order, blockTime func(uint64) time.Time) domain.OrderEvent {
+	eventType := domain.ProvenanceListed
+	if event.Topics[0] == crypto.Keccak256Hash([]byte(OrderCancelledSignature)) {
+		eventType = domain.ProvenanceDelisted
+	}
+	return domain.OrderEvent{
+		OrderID:            order.ID,
+		EventType:          eventType,
+		NFTContractAddress: order.NFTContractAddress,
+		TokenID:            order.TokenID,
+		TransactionHash:    event.TxHash.Hex(),
+		BlockNumber:        uint(event.BlockNumber),
+		BlockTimestamp:     blockTime(event.BlockNumber),
+	}
+}
+
+// 读取区块时间戳，失败时使用0
+func (uc *MarketUseCase) blockTime(blockNumber uint64) time.Time {
+	timestamp, err := uc.contract.GetBlockTimestamp(blockNumber)
+	if err != nil {
+		log.Printf("获取区块时间戳失败: %v", err)
+	}
+	return time.Unix(int64(timestamp), 0)
+}
+
+// 记录实时收到的挂单或撤单事件
+func (uc *MarketUseCase) recordOrderEvent(event *types.Log, order *domain.Order) {
+	orderEvent := uc.orderEventFromLog(event, order, uc.blockTime)
+	if err := uc.repo.SaveOrderEvent(&orderEvent); err != nil {
+		log.Printf("保存订单事件失败 (订单ID: %d): %v", order.ID-1, err)
+	}
+}
+
+// 扫描历史挂单和撤单事件，全部获取成功后再替换已保存的记录
+func (uc *MarketUseCase) scanHistoricalOrderEvents(orders []domain.Order) error {
+	creationBlock, err := uc.contract.GetCreationBlockNumber()
+	if err != nil {
+		return fmt.Errorf("获取合约创建区块号失败: %w", err)
+	}
+	latestBlock, err := uc.contract.GetLatestBlockNumber()
+	if err != nil {
+		return fmt.Errorf("获取最新区块号失败: %w", err)
+	}
+
+	logs, err := uc.contract.GetOrderLifecycleEvents(new(big.Int).SetUint64(creationBlock), new(big.Int).SetUint64(latestBlock))
+	if err != nil {
+		return err
+	}
+
+	ordersByID := make(map[uint]*domain.Order, len(orders))
+	for i := range orders {
+		ordersByID[orders[i].ID] = &orders[i]
+	}
+	timestamps := make(map[uint64]time.Time)
+	blockTime := func(blockNumber uint64) time.Time {
+		if t, exists := timestamps[blockNumber]; exists {
+			return t
+		}
+		timestamps[blockNumber] = uc.blockTime(blockNumber)
+		return timestamps[blockNumber]
+	}
+
+	events := make([]domain.OrderEvent, 0, len(logs))
+	for i := range logs {
+		orderID := uint(new(big.Int).SetBytes(logs[i].Topics[1].Bytes()).Uint64()) + 1
+		order, exists := ordersByID[orderID]
+		if !exists {
+			continue
+		}
+		events = append(events, uc.orderEventFromLog(&logs[i], order, blockTime))
+	}
+	return uc.repo.ReplaceOrderEvents(events)
+}
+
+// 按所有权变化计算各持有者的持有期和已实现收益
+func buildHoldings(events []domain.ProvenanceEvent, tokens map[string]*domain.PaymentToken) []domain.Holding {
+	holdings := make([]domain.Holding, 0)
+	for _, event := range events {
+		if event.Type != domain.ProvenanceMint && event.Type != domain.ProvenanceTransfer && event.Type != domain.ProvenanceSale {
+			continue
+		}
+
+		if len(holdings) > 0 {
+			current := &holdings[len(holdings)-1]
+			releasedAt := event.Timestamp
+			current.ReleasedAt = &releasedAt
+			current.ReleasedVia = event.Type
+			current.HoldingSeconds = int64(releasedAt.Sub(current.AcquiredAt).Seconds())
+			if event.Type == domain.ProvenanceSale {
+				current.DisposalPrice = event.Price
+				setRealizedGain(current, event.TokenAddress, tokens)
+			}
+		}
+
+		holding := domain.Holding{
+			Owner:       event.To,
+			AcquiredVia: event.Type,
+			AcquiredAt:  event.Timestamp,
+		}
+		if event.Type == domain.ProvenanceSale {
+			holding.AcquisitionPrice = event.Price
+			holding.TokenAddress = event.TokenAddress
+		}
+		holdings = append(holdings, holding)
+	}
+
+	if len(holdings) > 0 {
+		current := &holdings[len(holdings)-1]
+		current.HoldingSeconds = int64(time.Since(current.AcquiredAt).Seconds())
+	}
+	return holdings
+}
+
+// 买入和卖出使用同一支付代币时计算已实现收益
+func setRealizedGain(holding *domain.Holding, disposalToken string, tokens map[string]*domain.PaymentToken) {
+	if holding.AcquisitionPrice == "" {
+		holding.TokenAddress = disposalToken
+		return
+	}
+	if !strings.EqualFold(holding.TokenAddress, disposalToken) {
+		return
+	}
+
+	bought, ok1 := new(big.Int).SetString(holding.AcquisitionPrice, 10)
+	sold, ok2 := new(big.Int).SetString(holding.DisposalPrice, 10)
+	if !ok1 || !ok2 {
+		return
+	}
+	gain := new(big.Int).Sub(sold, bought).String()
+	holding.RealizedGain = gain
+	holding.RealizedGainFormatted = gain
+	if token, exists := tokens[strings.ToLower(disposalToken)]; exists {
+		holding.RealizedGainFormatted = FormatTokenAmount(gain, token.Decimals)
+	}
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+)
+
+// 重试任务类型
+const (
+	RetryJobTypeNFT        = "nft"
+	RetryJobTypeCollection = "collection"
+)
+
+var ErrJobRunning = domain.NewError(domain.ErrKindConflict, "job_running", "任务正在执行")
+
+const (
+	retryBaseDelay    = 30 * time.Second
+	retryMaxDelay     = time.Hour
+	retryMaxAttempts  = 8
+	retryPollInterval = 30 * time.Second
+	retryBatchSize    = 20
+)
+
+type RetryUseCase struct {
+	retryRepo *repository.RetryRepository
+	nftUC     *NFTUseCase
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func NewRetryUseCase(retryRepo *repository.RetryRepository, nftUC *NFTUseCase) *RetryUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	uc := &RetryUseCase{
+		retryRepo: retryRepo,
+		nftUC:     nftUC,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	return uc
+}
+
+// Start 启动重试协程，需在所有用例构造完成后调用
+func (uc *RetryUseCase) Start() {
+	if err := uc.retryRepo.ReleaseRunningJobs(); err != nil {
+		log.Printf("恢复执行中的重试任务失败: %v", err)
+	}
+	go uc.startWorker()
+}
+
+func (uc *RetryUseCase) startWorker() {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			uc.drainQueue()
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+// 执行所有已到期的重试任务
+func (uc *RetryUseCase) drainQueue() {
+	for {
+		jobs, err := uc.retryRepo.GetDueJobs(time.Now(), retryBatchSize)
+		if err != nil {
+			log.Printf("获取待重试任务失败: %v", err)
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+
+		for _, job := range jobs {
+			if uc.ctx.Err() != nil {
+				return
+			}
+			claimed, err := uc.retryRepo.ClaimJob(job.ID, []uint{domain.RetryJobStatusPending})
+			if err != nil {
+				log.Printf("认领重试任务失败 (ID: %d): %v", job.ID, err)
+				return
+			}
+			if claimed {
+				uc.runJob(&job)
+			}
+		}
+	}
+}
+
+func (uc *RetryUseCase) runJob(job *domain.RetryJob) {
+	var err error
+	switch job.JobType {
+	case RetryJobTypeNFT:
+		err = uc.nftUC.InitializeNFT(job.ContractAddress, job.TokenID)
+	case RetryJobTypeCollection:
+		err = uc.nftUC.InitializeNFTCollection(job.ContractAddress)
+	default:
+		err = fmt.Errorf("未知的任务类型: %s", job.JobType)
+	}
+
+	if err == nil {
+		if err := uc.retryRepo.MarkJobSucceeded(job.ID); err != nil {
+			log.Printf("更新重试任务状态失败 (ID: %d): %v", job.ID, err)
+		}
+		return
+	}
+
+	attempts := job.Attempts + 1
+	dead := attempts >= retryMaxAttempts
+	if dead {
+		log.Printf("重试任务进入死信 (ID: %d, 类型: %s, 地址: %s, TokenID: %d): %v", job.ID, job.JobType, job.ContractAddress, job.TokenID, err)
+	}
+	if err := uc.retryRepo.MarkJobFailed(job.ID, attempts, time.Now().Add(retryDelay(attempts)), err.Error(), dead); err != nil {
+		log.Printf("更新重试任务状态失败 (ID: %d): %v", job.ID, err)
+	}
+}
+
+// 指数退避，最长不超过 retryMaxDelay
+func retryDelay(attempts uint) time.Duration {
+	delay := retryBaseDelay
+	for i := uint(1); i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// 获取失败的任务（仍在重试中的和已进入死信的）
+func (uc *RetryUseCase) GetFailedJobs() ([]domain.RetryJob, error) {
+	return uc.retryRepo.GetJobsByStatus([]uint{domain.RetryJobStatusPending, domain.RetryJobStatusRunning, domain.RetryJobStatusDead})
+}
+
+// 手动重试任务，立即执行一次并重新计算重试次数。任务先被认领，重试协程不会同时执行它
+func (uc *RetryUseCase) RetryJob(id uint) (*domain.RetryJob, error) {
+	job, err := uc.retryRepo.GetJobByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrJobNotFound)
+	}
+	if job.Status == domain.RetryJobStatusSucceeded {
+		return job, nil
+	}
+
+	claimed, err := uc.retryRepo.ClaimJob(id, []uint{domain.RetryJobStatusPending, domain.RetryJobStatusDead})
+	if err != nil {
+		return nil, fmt.Errorf("认领重试任务失败: %w", err)
+	}
+	if !claimed {
+		return nil, ErrJobRunning
+	}
+	job.Attempts = 0
+	uc.runJob(job)
+
+	return uc.retryRepo.GetJobByID(id)
+}
+
+func (uc *RetryUseCase) Close() {
+	uc.cancel()
+}
@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"backend/domain"
+)
+
+// 各字段的权重
+const (
+	searchWeightTitle   = 3.0
+	searchWeightSymbol  = 2.5
+	searchWeightKey     = 2.0
+	searchWeightTrait   = 1.5
+	searchWeightContent = 1.0
+)
+
+// 匹配方式对分数的折扣
+const (
+	searchMatchExact  = 1.0
+	searchMatchPrefix = 0.7
+	searchMatchFuzzy  = 0.4
+)
+
+// 模糊匹配的最短词长
+const searchFuzzyMinLength = 4
+
+// 结果类型的展示优先级
+var searchTypePriority = map[string]int{
+	domain.SearchTypeCollection:  0,
+	domain.SearchTypeNFT:         1,
+	domain.SearchTypeAddress:     2,
+	domain.SearchTypeTransaction: 3,
+}
+
+type searchField struct {
+	text   string
+	weight float64
+}
+
+type searchDocument struct {
+	result domain.SearchResult
+	fields []searchField
+	terms  map[string]float64
+}
+
+// searchIndex 是基于倒排表的内存索引，支持前缀和编辑距离匹配
+type searchIndex struct {
+	mutex     sync.RWMutex
+	documents map[string]*searchDocument
+	postings  map[string]map[string]float64 // 词 -> 文档 -> 权重
+	terms     []string                      // 有序词表，用于前缀查找
+	dirty     bool
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		documents: make(map[string]*searchDocument),
+		postings:  make(map[string]map[string]float64),
+	}
+}
+
+// 添加或替换文档，merge 为 true 时保留已有文档的字段
+func (idx *searchIndex) upsert(key string, result domain.SearchResult, fields []searchField, merge bool) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if existing, exists := idx.documents[key]; exists {
+		if merge {
+			fields = mergeSearchFields(existing.fields, fields)
+		}
+		idx.removeLocked(key)
+	}
+
+	doc := &searchDocument{result: result, fields: fields, terms: make(map[string]float64)}
+	for _, field := range fields {
+		for _, term := range tokenize(field.text) {
+			if field.weight > doc.terms[term] {
+				doc.terms[term] = field.weight
+			}
+		}
+	}
+
+	for term, weight := range doc.terms {
+		docs, exists := idx.postings[term]
+		if !exists {
+			docs = make(map[string]float64)
+			idx.postings[term] = docs
+			idx.dirty = true
+		}
+		docs[key] = weight
+	}
+	idx.documents[key] = doc
+}
+
+func mergeSearchFields(existing, fields []searchField) []searchField {
+	merged := append([]searchField{}, existing...)
+	for _, field := range fields {
+		duplicate := false
+		for _, e := range existing {
+			if e == field {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, field)
+		}
+	}
+	return merged
+}
+
+func (idx *searchIndex) has(key string) bool {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	_, exists := idx.documents[key]
+	return exists
+}
+
+func (idx *searchIndex) removeLocked(key string) {
+	doc, exists := idx.documents[key]
+	if !exists {
+		return
+	}
+	for term := range doc.terms {
+		delete(idx.postings[term], key)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+			idx.dirty = true
+		}
+	}
+	delete(idx.documents, key)
+}
+
+func (idx *searchIndex) reset() {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	idx.documents = make(map[string]*searchDocument)
+	idx.postings = make(map[string]map[string]float64)
+	idx.terms = nil
+	idx.dirty = false
+}
+
+// 获取有序词表，词表变化后在写锁下重建
+func (idx *searchIndex) sortedTerms() []string {
+	idx.mutex.RLock()
+	if !idx.dirty {
+		terms := idx.terms
+		idx.mutex.RUnlock()
+		return terms
+	}
+	idx.mutex.RUnlock()
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	if idx.dirty {
+		terms := make([]string, 0, len(idx.postings))
+		for term := range idx.postings {
+			terms = append(terms, term)
+		}
+		sort.Strings(terms)
+		idx.terms = terms
+		idx.dirty = false
+	}
+	return idx.terms
+}
+
+// 查找与查询词匹配的索引词及匹配系数
+func (idx *searchIndex) matchTerms(terms []string, queryTerm string) map[string]float64 {
+	matches := make(map[string]float64)
+
+	// 前缀匹配（包含完全匹配）
+	start := sort.SearchStrings(terms, queryTerm)
+	for i := start; i < len(terms) && strings.HasPrefix(terms[i], queryTerm); i++ {
+		if terms[i] == queryTerm {
+			matches[terms[i]] = searchMatchExact
+		} else {
+			matches[terms[i]] = searchMatchPrefix
+		}
+	}
+
+	// 模糊匹配
+	queryLength := len([]rune(queryTerm))
+	if queryLength < searchFuzzyMinLength {
+		return matches
+	}
+	maxDistance := 1
+	if queryLength >= 8 {
+		maxDistance = 2
+	}
+	for _, term := range terms {
+		if _, exists := matches[term]; exists {
+			continue
+		}
+		termLength := len([]rune(term))
+		if termLength < queryLength-maxDistance || termLength > queryLength+maxDistance {
+			continue
+		}
+		if editDistance(queryTerm, term, maxDistance) <= maxDistance {
+			matches[term] = searchMatchFuzzy
+		}
+	}
+	return matches
+}
+
+// 搜索文档，所有查询词均需匹配
+func (idx *searchIndex) search(query string, docType string, limit int) []domain.SearchResult {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 {
+		return []domain.SearchResult{}
+	}
+	terms := idx.sortedTerms()
+
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	var scores map[string]float64
+	for _, queryTerm := range queryTerms {
+		termScores := make(map[string]float64)
+		for term, factor := range idx.matchTerms(terms, queryTerm) {
+			for key, weight := range idx.postings[term] {
+				if score := weight * factor; score > termScores[key] {
+					termScores[key] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for key := range scores {
+			if termScore, exists := termScores[key]; exists {
+				scores[key] += termScore
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+
+	results := make([]domain.SearchResult, 0, len(scores))
+	for key, score := range scores {
+		doc := idx.documents[key]
+		if doc == nil || (docType != "" && doc.result.Type != docType) {
+			continue
+		}
+		result := doc.result
+		result.Score = score
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if searchTypePriority[results[i].Type] != searchTypePriority[results[j].Type] {
+			return searchTypePriority[results[i].Type] < searchTypePriority[results[j].Type]
+		}
+		return results[i].Title < results[j].Title
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// 按前缀补全查询的最后一个词，按文档数量排序
+func (idx *searchIndex) autocomplete(prefix string, limit int) []string {
+	queryTerms := tokenize(prefix)
+	if len(queryTerms) == 0 {
+		return []string{}
+	}
+	last := queryTerms[len(queryTerms)-1]
+	terms := idx.sortedTerms()
+
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	type completion struct {
+		term  string
+		count int
+	}
+	var completions []completion
+	start := sort.SearchStrings(terms, last)
+	for i := start; i < len(terms) && strings.HasPrefix(terms[i], last); i++ {
+		completions = append(completions, completion{term: terms[i], count: len(idx.postings[terms[i]])})
+	}
+	sort.SliceStable(completions, func(i, j int) bool {
+		return completions[i].count > completions[j].count
+	})
+
+	results := make([]string, 0, limit)
+	for _, c := range completions {
+		if len(results) >= limit {
+			break
+		}
+		results = append(results, c.term)
+	}
+	return results
+}
+
+// 分词：转小写，按非字母数字切分，连续的汉字额外按单字索引
+func tokenize(text string) []string {
+	var tokens []string
+	seen := make(map[string]bool)
+	add := func(token string) {
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		add(word)
+		for _, r := range word {
+			if unicode.Is(unicode.Han, r) {
+				add(string(r))
+			}
+		}
+	}
+	return tokens
+}
+
+// 计算编辑距离（相邻字符交换计为一次编辑），超过 maxDistance 时提前返回
+func editDistance(a, b string, maxDistance int) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > maxDistance {
+			return rowMin
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+type SearchUseCase struct {
+	nftRepo    *repository.NFTRepository
+	marketRepo *repository.MarketRepository
+	index      *searchIndex
+}
+
+// 创建时索引为空，由 MarketUseCase.Start 在初始化订单数据后调用 Rebuild 构建
+func NewSearchUseCase(nftRepo *repository.NFTRepository, marketRepo *repository.MarketRepository) *SearchUseCase {
+	return &SearchUseCase{
+		nftRepo:    nftRepo,
+		marketRepo: marketRepo,
+		index:      newSearchIndex(),
+	}
+}
+
+// 从数据库重建搜索索引
+func (uc *SearchUseCase) Rebuild() error {
+	uc.index.reset()
+
+	collections, err := uc.nftRepo.GetAllCollections()
+	if err != nil {
+		return fmt.Errorf("获取NFT集合失败: %w", err)
+	}
+	for i := range collections {
+		collection := &collections[i]
+		uc.IndexCollection(collection)
+
+		nfts, err := uc.nftRepo.GetAllNFTs(collection.ContractAddress)
+		if err != nil {
+			return fmt.Errorf("获取NFT列表失败: %w", err)
+		}
+		attributes, err := uc.nftRepo.GetAttributesByCollectionID(collection.ID)
+		if err != nil {
+			return fmt.Errorf("获取NFT属性失败: %w", err)
+		}
+		attributesByNFT := make(map[uint][]domain.NFTAttribute)
+		for _, attr := range attributes {
+			attributesByNFT[attr.NFTID] = append(attributesByNFT[attr.NFTID], attr)
+		}
+		for j := range nfts {
+			uc.IndexNFT(&nfts[j], attributesByNFT[nfts[j].ID])
+		}
+	}
+
+	events, err := uc.nftRepo.GetAllNFTTransferEvents()
+	if err != nil {
+		return fmt.Errorf("获取NFT转移事件失败: %w", err)
+	}
+	for i := range events {
+		uc.IndexTransferEvent(&events[i])
+	}
+
+	orders, err := uc.marketRepo.GetAllOrders()
+	if err != nil {
+		return fmt.Errorf("获取订单失败: %w", err)
+	}
+	for _, order := range orders {
+		uc.IndexAddress(order.Seller)
+	}
+
+	return nil
+}
+
+func (uc *SearchUseCase) IndexCollection(collection *domain.NFTCollection) {
+	key := domain.SearchTypeCollection + ":" + strings.ToLower(collection.ContractAddress)
+	uc.index.upsert(key, domain.SearchResult{
+		Type:            domain.SearchTypeCollection,
+		Title:           collection.Name,
+		Subtitle:        collection.Symbol,
+		Image:           collection.TokenIconURI,
+		ContractAddress: collection.ContractAddress,
+	}, []searchField{
+		{text: collection.Name, weight: searchWeightTitle},
+		{text: collection.Symbol, weight: searchWeightSymbol},
+		{text: collection.ContractAddress, weight: searchWeightKey},
+	}, false)
+}
+
+func (uc *SearchUseCase) IndexNFT(nft *domain.NFT, attributes []domain.NFTAttribute) {
+	title := nft.Name
+	if title == "" {
+		title = fmt.Sprintf("#%d", nft.TokenID)
+	}
+
+	fields := []searchField{
+		{text: nft.Name, weight: searchWeightTitle},
+		{text: nft.Description, weight: searchWeightContent},
+	}
+	for _, attr := range attributes {
+		fields = append(fields, searchField{text: attr.Value, weight: searchWeightTrait})
+	}
+
+	key := fmt.Sprintf("%s:%s:%d", domain.SearchTypeNFT, strings.ToLower(nft.ContractAddress), nft.TokenID)
+	uc.index.upsert(key, domain.SearchResult{
+		Type:            domain.SearchTypeNFT,
+		Title:           title,
+		Subtitle:        nft.Description,
+		Image:           nft.Image,
+		ContractAddress: nft.ContractAddress,
+		TokenID:         nft.TokenID,
+		Address:         nft.Owner,
+	}, fields, false)
+
+	uc.IndexAddress(nft.Owner)
+}
+
+// 从数据库重新索引单个NFT，用于所有者变化后更新索引；尚未保存的NFT在初始化时索引
+func (uc *SearchUseCase) ReindexNFT(contractAddress string, tokenID uint) error {
+	nft, err := uc.nftRepo.GetByTokenID(contractAddress, tokenID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("获取NFT失败: %w", err)
+	}
+	attributes, err := uc.nftRepo.GetAttributes(nft.ID)
+	if err != nil {
+		return fmt.Errorf("获取NFT属性失败: %w", err)
+	}
+	uc.IndexNFT(nft, attributes)
+	return nil
+}
+
+// 索引钱包或合约地址，零地址不索引
+func (uc *SearchUseCase) IndexAddress(address string) {
+	if address == "" || common.HexToAddress(address) == (common.Address{}) {
+		return
+	}
+	key := domain.SearchTypeAddress + ":" + strings.ToLower(address)
+	if uc.index.has(key) {
+		return
+	}
+	uc.index.upsert(key, domain.SearchResult{
+		Type:    domain.SearchTypeAddress,
+		Title:   address,
+		Address: address,
+	}, []searchField{{text: address, weight: searchWeightKey}}, false)
+}
+
+// 索引转移事件的交易哈希及相关地址
+func (uc *SearchUseCase) IndexTransferEvent(event *domain.NFTTransferEvent) {
+	key := domain.SearchTypeTransaction + ":" + strings.ToLower(event.TransactionHash)
+	uc.index.upsert(key, domain.SearchResult{
+		Type:            domain.SearchTypeTransaction,
+		Title:           event.TransactionHash,
+		Subtitle:        event.EventType,
+		ContractAddress: event.ContractAddress,
+		TokenID:         event.TokenID,
+		TransactionHash: event.TransactionHash,
+	}, []searchField{{text: event.TransactionHash, weight: searchWeightKey}}, true)
+
+	uc.IndexAddress(event.FromAddress)
+	uc.IndexAddress(event.ToAddress)
+}
+
+// 搜索集合、NFT、地址和交易，docType 为空时搜索全部类型
+func (uc *SearchUseCase) Search(query, docType string, limit int) []domain.SearchResult {
+	return uc.index.search(query, docType, limit)
+}
+
+// 返回查询最后一个词的补全建议
+func (uc *SearchUseCase) Autocomplete(query string, limit int) []string {
+	return uc.index.autocomplete(query, limit)
+}
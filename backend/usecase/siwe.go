@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidSIWEMessage = domain.NewError(domain.ErrKindUnauthorized, "invalid_siwe_message", "无效的登录消息")
+
+const siwePreamble = " wants you to sign in with your Ethereum account:"
+
+// SIWEMessage 表示解析后的 EIP-4361 登录消息
+type SIWEMessage struct {
+	Scheme         string
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// 按 EIP-4361 的 ABNF 解析登录消息
+func ParseSIWEMessage(raw string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], siwePreamble) {
+		return nil, fmt.Errorf("%w: 缺少登录声明", ErrInvalidSIWEMessage)
+	}
+
+	msg := &SIWEMessage{Domain: strings.TrimSuffix(lines[0], siwePreamble)}
+	if scheme, domain, found := strings.Cut(msg.Domain, "://"); found {
+		msg.Scheme, msg.Domain = scheme, domain
+	}
+	if msg.Domain == "" {
+		return nil, fmt.Errorf("%w: 缺少域名", ErrInvalidSIWEMessage)
+	}
+
+	// 地址必须为 EIP-55 校验和格式
+	if !common.IsHexAddress(lines[1]) || common.HexToAddress(lines[1]).Hex() != lines[1] {
+		return nil, fmt.Errorf("%w: 地址必须为EIP-55校验和格式", ErrInvalidSIWEMessage)
+	}
+	msg.Address = lines[1]
+
+	// 地址后为空行，可选的声明，以及声明后的空行
+	i := 2
+	if i >= len(lines) || lines[i] != "" {
+		return nil, fmt.Errorf("%w: 地址后缺少空行", ErrInvalidSIWEMessage)
+	}
+	i++
+	if i < len(lines) && !strings.HasPrefix(lines[i], "URI: ") {
+		msg.Statement = lines[i]
+		i++
+		if i >= len(lines) || lines[i] != "" {
+			return nil, fmt.Errorf("%w: 声明后缺少空行", ErrInvalidSIWEMessage)
+		}
+		i++
+	}
+
+	fields := make(map[string]string)
+	order := []string{"URI", "Version", "Chain ID", "Nonce", "Issued At", "Expiration Time", "Not Before", "Request ID"}
+	next := 0
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "Resources:" {
+			for i++; i < len(lines); i++ {
+				if !strings.HasPrefix(lines[i], "- ") {
+					return nil, fmt.Errorf("%w: 资源格式错误", ErrInvalidSIWEMessage)
+				}
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(lines[i], "- "))
+			}
+			break
+		}
+		key, value, found := strings.Cut(line, ": ")
+		if !found {
+			return nil, fmt.Errorf("%w: 无法解析的行 %q", ErrInvalidSIWEMessage, line)
+		}
+		// 字段必须按规定顺序出现
+		for next < len(order) && order[next] != key {
+			next++
+		}
+		if next == len(order) {
+			return nil, fmt.Errorf("%w: 未知或顺序错误的字段 %s", ErrInvalidSIWEMessage, key)
+		}
+		fields[key] = value
+		next++
+	}
+
+	for _, key := range []string{"URI", "Version", "Chain ID", "Nonce", "Issued At"} {
+		if fields[key] == "" {
+			return nil, fmt.Errorf("%w: 缺少字段 %s", ErrInvalidSIWEMessage, key)
+		}
+	}
+
+	msg.URI = fields["URI"]
+	msg.Version = fields["Version"]
+	if msg.Version != "1" {
+		return nil, fmt.Errorf("%w: 不支持的版本 %s", ErrInvalidSIWEMessage, msg.Version)
+	}
+	chainID, err := strconv.ParseUint(fields["Chain ID"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Chain ID 格式错误", ErrInvalidSIWEMessage)
+	}
+	msg.ChainID = chainID
+	msg.Nonce = fields["Nonce"]
+	if len(msg.Nonce) < 8 || strings.IndexFunc(msg.Nonce, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) >= 0 {
+		return nil, fmt.Errorf("%w: Nonce 必须为至少8位的字母数字", ErrInvalidSIWEMessage)
+	}
+	if msg.IssuedAt, err = time.Parse(time.RFC3339, fields["Issued At"]); err != nil {
+		return nil, fmt.Errorf("%w: Issued At 格式错误", ErrInvalidSIWEMessage)
+	}
+	if value, exists := fields["Expiration Time"]; exists {
+		expiration, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Expiration Time 格式错误", ErrInvalidSIWEMessage)
+		}
+		msg.ExpirationTime = &expiration
+	}
+	if value, exists := fields["Not Before"]; exists {
+		notBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Not Before 格式错误", ErrInvalidSIWEMessage)
+		}
+		msg.NotBefore = &notBefore
+	}
+	msg.RequestID = fields["Request ID"]
+
+	return msg, nil
+}
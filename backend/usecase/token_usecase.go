@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+
+	"backend/contracts"
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// 从链上读取代币信息失败，地址不是 ERC20 合约或节点不可用
+var ErrTokenUnreadable = domain.NewError(domain.ErrKindUpstream, "token_unreadable", "读取代币信息失败")
+
+// 代币名单状态
+const (
+	TokenListStatusNone    = 0
+	TokenListStatusAllowed = 1
+	TokenListStatusDenied  = 2
+)
+
+type TokenUseCase struct {
+	tokenRepo     *repository.TokenRepository
+	oracle        *PriceOracleUseCase
+	profileUC     *ProfileUseCase // 由 NewProfileUseCase 设置，用于附加卖家和买家资料
+	ethClientURL  string
+	contractCache map[string]*contracts.ERC20Contract
+	mutex         sync.RWMutex
+}
+
+func NewTokenUseCase(tokenRepo *repository.TokenRepository, oracle *PriceOracleUseCase, ethClientURL string) *TokenUseCase {
+	return &TokenUseCase{
+		tokenRepo:     tokenRepo,
+		oracle:        oracle,
+		ethClientURL:  ethClientURL,
+		contractCache: make(map[string]*contracts.ERC20Contract),
+	}
+}
+
+// 获取ERC20代币合约实例
+func (uc *TokenUseCase) getTokenContract(tokenAddress string) (*contracts.ERC20Contract, error) {
+	key := strings.ToLower(tokenAddress)
+
+	uc.mutex.RLock()
+	token, exists := uc.contractCache[key]
+	uc.mutex.RUnlock()
+	if exists {
+		return token, nil
+	}
+
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	token, err := contracts.NewERC20Contract(uc.ethClientURL, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	uc.contractCache[key] = token
+	return token, nil
+}
+
+// 确保代币已登记，未登记时从链上读取元数据
+func (uc *TokenUseCase) EnsureToken(tokenAddress string) (*domain.PaymentToken, error) {
+	token, err := uc.tokenRepo.GetTokenByAddress(tokenAddress)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return uc.RefreshToken(tokenAddress)
+}
+
+// 从链上重新读取代币的 name、symbol、decimals 和 permit 支持情况
+func (uc *TokenUseCase) RefreshToken(tokenAddress string) (*domain.PaymentToken, error) {
+	contract, err := uc.getTokenContract(tokenAddress)
+	if err != nil {
+		return nil, ErrTokenUnreadable.Wrap(fmt.Errorf("获取代币合约实例失败: %w", err))
+	}
+	token, err := readToken(contract)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tokenRepo.UpsertToken(token); err != nil {
+		return nil, fmt.Errorf("保存代币信息失败: %w", err)
+	}
+	return token, nil
+}
+
+// 获取只读检查使用的代币合约实例：未登记的代币不写入缓存，用完后需调用 release 释放连接
+func (uc *TokenUseCase) peekTokenContract(tokenAddress string) (contract *contracts.ERC20Contract, release func(), err error) {
+	uc.mutex.RLock()
+	_, cached := uc.contractCache[strings.ToLower(tokenAddress)]
+	uc.mutex.RUnlock()
+	if !cached {
+		if _, err := uc.tokenRepo.GetTokenByAddress(tokenAddress); errors.Is(err, gorm.ErrRecordNotFound) {
+			contract, err := contracts.NewERC20Contract(uc.ethClientURL, tokenAddress)
+			if err != nil {
+				return nil, nil, err
+			}
+			return contract, contract.Close, nil
+		} else if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	contract, err = uc.getTokenContract(tokenAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contract, func() {}, nil
+}
+
+// 获取代币信息但不登记：已登记的代币直接返回，未登记的代币从链上读取
+func (uc *TokenUseCase) peekToken(contract *contracts.ERC20Contract) (*domain.PaymentToken, error) {
+	token, err := uc.tokenRepo.GetTokenByAddress(contract.Address().Hex())
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return readToken(contract)
+}
+
+// 从链上读取代币元数据
+func readToken(contract *contracts.ERC20Contract) (*domain.PaymentToken, error) {
+	name, err := contract.Name()
+	if err != nil {
+		return nil, ErrTokenUnreadable.Wrap(fmt.Errorf("获取代币名称失败: %w", err))
+	}
+	symbol, err := contract.Symbol()
+	if err != nil {
+		return nil, ErrTokenUnreadable.Wrap(fmt.Errorf("获取代币符号失败: %w", err))
+	}
+	decimals, err := contract.Decimals()
+	if err != nil {
+		return nil, ErrTokenUnreadable.Wrap(fmt.Errorf("获取代币精度失败: %w", err))
+	}
+
+	return &domain.PaymentToken{
+		Address:        contract.Address().Hex(),
+		Name:           name,
+		Symbol:         symbol,
+		Decimals:       decimals,
+		SupportsPermit: supportsPermit(contract),
+	}, nil
+}
+
+// 同时提供 nonces 和 DOMAIN_SEPARATOR 视为支持 EIP-2612
+func supportsPermit(contract *contracts.ERC20Contract) bool {
+	if _, err := contract.Nonces(common.Address{}); err != nil {
+		return false
+	}
+	if _, err := contract.DomainSeparator(); err != nil {
+		return false
+	}
+	return true
+}
+
+func (uc *TokenUseCase) GetAllTokens() ([]domain.PaymentToken, error) {
+	return uc.tokenRepo.GetAllTokens()
+}
+
+func (uc *TokenUseCase) GetToken(tokenAddress string) (*domain.PaymentToken, error) {
+	token, err := uc.EnsureToken(tokenAddress)
+	if errors.Is(err, ErrTokenUnreadable) {
+		// 未登记且无法按 ERC20 读取的地址视为不存在，节点出错时除外
+		return nil, chainCallError(err, domain.ErrTokenNotFound.Wrap(err))
+	}
+	return token, err
+}
+
+// 获取代币的美元报价
+func (uc *TokenUseCase) GetTokenPrice(tokenAddress string) (*PriceQuote, error) {
+	return uc.oracle.GetUSDPrice(tokenAddress)
+}
+
+// 设置代币的白名单/黑名单状态
+func (uc *TokenUseCase) SetTokenListStatus(tokenAddress string, status uint) (*domain.PaymentToken, error) {
+	token, err := uc.EnsureToken(tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tokenRepo.UpdateTokenListStatus(token.Address, status); err != nil {
+		return nil, fmt.Errorf("更新代币名单状态失败: %w", err)
+	}
+	token.ListStatus = status
+	return token, nil
+}
+
+// 判断代币是否可用于挂单：黑名单代币不可用；存在白名单时只有白名单代币可用
+func (uc *TokenUseCase) IsTokenAccepted(token *domain.PaymentToken) (bool, error) {
+	switch token.ListStatus {
+	case TokenListStatusDenied:
+		return false, nil
+	case TokenListStatusAllowed:
+		return true, nil
+	}
+	hasAllowed, err := uc.tokenRepo.HasAllowedTokens()
+	if err != nil {
+		return false, err
+	}
+	return !hasAllowed, nil
+}
+
+// 批量获取已登记的代币信息，key 为小写地址
+func (uc *TokenUseCase) lookupTokens(tokenAddresses []string) map[string]*domain.PaymentToken {
+	addresses := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, address := range tokenAddresses {
+		key := strings.ToLower(address)
+		if !seen[key] {
+			seen[key] = true
+			addresses = append(addresses, address)
+		}
+	}
+
+	tokens := make(map[string]*domain.PaymentToken)
+	if len(addresses) > 0 {
+		found, err := uc.tokenRepo.GetTokensByAddresses(addresses)
+		if err != nil {
+			log.Printf("获取代币信息失败: %v", err)
+		}
+		for i := range found {
+			tokens[strings.ToLower(found[i].Address)] = &found[i]
+		}
+	}
+	return tokens
+}
+
+// 为订单附加支付代币信息、格式化价格和美元价格
+func (uc *TokenUseCase) DescribeOrders(orders []domain.Order) []domain.OrderView {
+	addresses := make([]string, len(orders))
+	for i, order := range orders {
+		addresses[i] = order.TokenAddress
+	}
+	tokens := uc.lookupTokens(addresses)
+	quotes := uc.oracle.USDPrices(addresses)
+	sellers := make([]string, len(orders))
+	for i, order := range orders {
+		sellers[i] = order.Seller
+	}
+	profiles := uc.profileUC.Summaries(sellers...)
+
+	views := make([]domain.OrderView, len(orders))
+	for i, order := range orders {
+		views[i] = domain.OrderView{Order: order, Source: domain.OrderSourceOnChain, PriceFormatted: order.Price}
+		views[i].SellerProfile = profiles[strings.ToLower(order.Seller)]
+		if token, exists := tokens[strings.ToLower(order.TokenAddress)]; exists {
+			views[i].PaymentToken = token
+			views[i].PriceFormatted = FormatTokenAmount(order.Price, token.Decimals)
+			views[i].PriceUSD = quoteToUSD(quotes[strings.ToLower(order.TokenAddress)], order.Price, token.Decimals)
+		}
+	}
+	return views
+}
+
+func (uc *TokenUseCase) DescribeOrder(order *domain.Order) *domain.OrderView {
+	views := uc.DescribeOrders([]domain.Order{*order})
+	return &views[0]
+}
+
+// 为出价附加支付代币信息、格式化价格和美元价格
+func (uc *TokenUseCase) DescribeOffers(offers []domain.Offer) []domain.OfferView {
+	addresses := make([]string, len(offers))
+	for i, offer := range offers {
+		addresses[i] = offer.TokenAddress
+	}
+	tokens := uc.lookupTokens(addresses)
+	quotes := uc.oracle.USDPrices(addresses)
+
+	views := make([]domain.OfferView, len(offers))
+	for i, offer := range offers {
+		views[i] = domain.OfferView{Offer: offer, PriceFormatted: offer.Price}
+		if token, exists := tokens[strings.ToLower(offer.TokenAddress)]; exists {
+			views[i].PaymentToken = token
+			views[i].PriceFormatted = FormatTokenAmount(offer.Price, token.Decimals)
+			views[i].PriceUSD = quoteToUSD(quotes[strings.ToLower(offer.TokenAddress)], offer.Price, token.Decimals)
+		}
+	}
+	return views
+}
+
+func (uc *TokenUseCase) DescribeOffer(offer *domain.Offer) *domain.OfferView {
+	views := uc.DescribeOffers([]domain.Offer{*offer})
+	return &views[0]
+}
+
+// 为链下挂单附加支付代币信息、格式化价格和美元价格
+func (uc *TokenUseCase) DescribeListings(listings []domain.Listing) []domain.ListingView {
+	addresses := make([]string, len(listings))
+	for i, listing := range listings {
+		addresses[i] = listing.TokenAddress
+	}
+	tokens := uc.lookupTokens(addresses)
+	quotes := uc.oracle.USDPrices(addresses)
+	parties := make([]string, 0, len(listings)*2)
+	for _, listing := range listings {
+		parties = append(parties, listing.Seller, listing.Buyer)
+	}
+	profiles := uc.profileUC.Summaries(parties...)
+
+	views := make([]domain.ListingView, len(listings))
+	for i, listing := range listings {
+		views[i] = domain.ListingView{Listing: listing, PriceFormatted: listing.Price}
+		views[i].SellerProfile = profiles[strings.ToLower(listing.Seller)]
+		if listing.Buyer != "" {
+			views[i].BuyerProfile = profiles[strings.ToLower(listing.Buyer)]
+		}
+		if token, exists := tokens[strings.ToLower(listing.TokenAddress)]; exists {
+			views[i].PaymentToken = token
+			views[i].PriceFormatted = FormatTokenAmount(listing.Price, token.Decimals)
+			views[i].PriceUSD = quoteToUSD(quotes[strings.ToLower(listing.TokenAddress)], listing.Price, token.Decimals)
+		}
+	}
+	return views
+}
+
+func (uc *TokenUseCase) DescribeListing(listing *domain.Listing) *domain.ListingView {
+	views := uc.DescribeListings([]domain.Listing{*listing})
+	return &views[0]
+}
+
+// 为K线附加支付代币信息和格式化价格
+func (uc *TokenUseCase) DescribeCandles(candles []domain.PriceCandle) []domain.PriceCandleView {
+	addresses := make([]string, len(candles))
+	for i, candle := range candles {
+		addresses[i] = candle.TokenAddress
+	}
+	tokens := uc.lookupTokens(addresses)
+
+	views := make([]domain.PriceCandleView, len(candles))
+	for i, candle := range candles {
+		views[i] = domain.PriceCandleView{
+			PriceCandle:      candle,
+			OpenFormatted:    candle.Open,
+			HighFormatted:    candle.High,
+			LowFormatted:     candle.Low,
+			CloseFormatted:   candle.Close,
+			VolumeFormatted:  candle.Volume,
+			AverageFormatted: candle.Average,
+		}
+		if token, exists := tokens[strings.ToLower(candle.TokenAddress)]; exists {
+			views[i].PaymentToken = token
+			views[i].OpenFormatted = FormatTokenAmount(candle.Open, token.Decimals)
+			views[i].HighFormatted = FormatTokenAmount(candle.High, token.Decimals)
+			views[i].LowFormatted = FormatTokenAmount(candle.Low, token.Decimals)
+			views[i].CloseFormatted = FormatTokenAmount(candle.Close, token.Decimals)
+			views[i].VolumeFormatted = FormatTokenAmount(candle.Volume, token.Decimals)
+			views[i].AverageFormatted = FormatTokenAmount(candle.Average, token.Decimals)
+		}
+	}
+	return views
+}
+
+// 按精度将整数金额格式化为十进制字符串，去掉末尾的0
+func FormatTokenAmount(raw string, decimals uint8) string {
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return raw
+	}
+	if decimals == 0 {
+		return amount.String()
+	}
+
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	integer := digits[:len(digits)-int(decimals)]
+	fraction := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+
+	result := integer
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
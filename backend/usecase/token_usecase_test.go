@@ -0,0 +1,33 @@
+package usecase
+
+import "testing"
+
+func TestFormatTokenAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		decimals uint8
+		want     string
+	}{
+		{"整数金额", "1000000000000000000", 18, "1"},
+		{"带小数", "1500000000000000000", 18, "1.5"},
+		{"去掉末尾的0", "1230000", 6, "1.23"},
+		{"小于1", "5", 18, "0.000000000000000005"},
+		{"位数等于精度", "123456", 6, "0.123456"},
+		{"零", "0", 18, "0"},
+		{"精度为0", "42", 0, "42"},
+		{"负数", "-2500000", 6, "-2.5"},
+		{"负数小于1", "-1", 2, "-0.01"},
+		{"超过 uint64 范围", "123456789012345678901234567890", 18, "123456789012.34567890123456789"},
+		{"无法解析时原样返回", "abc", 18, "abc"},
+		{"空字符串原样返回", "", 18, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTokenAmount(tt.raw, tt.decimals); got != tt.want {
+				t.Fatalf("FormatTokenAmount(%q, %d) = %q，应为 %q", tt.raw, tt.decimals, got, tt.want)
+			}
+		})
+	}
+}
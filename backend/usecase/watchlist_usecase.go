@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// 市场动态类型
+const (
+	ActivityTypeListing   = "listing"
+	ActivityTypePriceDrop = "price_drop"
+	ActivityTypeSale      = "sale"
+)
+
+const (
+	defaultFeedLimit = 50
+	maxFeedLimit     = 200
+)
+
+var ErrInvalidActivityType = domain.NewError(domain.ErrKindInvalid, "invalid_activity_type", "无效的动态类型")
+
+type WatchlistUseCase struct {
+	watchlistRepo *repository.WatchlistRepository
+	nftRepo       *repository.NFTRepository
+	tokenUC       *TokenUseCase
+	profileUC     *ProfileUseCase
+}
+
+// 创建后挂到 marketUC 上，由市场事件生成动态
+func NewWatchlistUseCase(watchlistRepo *repository.WatchlistRepository, nftRepo *repository.NFTRepository, tokenUC *TokenUseCase, profileUC *ProfileUseCase, marketUC *MarketUseCase) *WatchlistUseCase {
+	uc := &WatchlistUseCase{
+		watchlistRepo: watchlistRepo,
+		nftRepo:       nftRepo,
+		tokenUC:       tokenUC,
+		profileUC:     profileUC,
+	}
+	marketUC.watchlistUC = uc
+	return uc
+}
+
+// 收藏NFT，NFT必须已被索引
+func (uc *WatchlistUseCase) AddFavorite(address, nftAddress string, tokenID uint) error {
+	nftAddress = common.HexToAddress(nftAddress).Hex()
+	if _, err := uc.nftRepo.GetByTokenID(nftAddress, tokenID); err != nil {
+		return notFoundAs(err, domain.ErrNFTNotFound)
+	}
+	return uc.watchlistRepo.AddFavorite(&domain.Favorite{Address: address, NFTContractAddress: nftAddress, TokenID: tokenID})
+}
+
+func (uc *WatchlistUseCase) RemoveFavorite(address, nftAddress string, tokenID uint) error {
+	rows, err := uc.watchlistRepo.RemoveFavorite(address, common.HexToAddress(nftAddress).Hex(), tokenID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrFavoriteNotFound
+	}
+	return nil
+}
+
+// 获取收藏的NFT，按收藏时间倒序
+func (uc *WatchlistUseCase) GetFavorites(address string) ([]domain.NFTView, error) {
+	favorites, err := uc.watchlistRepo.GetFavoritesByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	nfts := make([]domain.NFT, 0, len(favorites))
+	for _, favorite := range favorites {
+		nft, err := uc.nftRepo.GetByTokenID(favorite.NFTContractAddress, favorite.TokenID)
+		if err != nil {
+			continue
+		}
+		nfts = append(nfts, *nft)
+	}
+	return uc.profileUC.DescribeNFTs(nfts), nil
+}
+
+// 获取NFT的收藏数，address 不为空时同时返回该地址是否已收藏
+func (uc *WatchlistUseCase) GetFavoriteStatus(address, nftAddress string, tokenID uint) (int64, bool, error) {
+	nftAddress = common.HexToAddress(nftAddress).Hex()
+	count, err := uc.watchlistRepo.CountFavorites(nftAddress, tokenID)
+	if err != nil || address == "" {
+		return count, false, err
+	}
+	favorited, err := uc.watchlistRepo.IsFavorited(address, nftAddress, tokenID)
+	return count, favorited, err
+}
+
+// 关注NFT系列，系列必须已被索引
+func (uc *WatchlistUseCase) AddWatch(address, contractAddress string) error {
+	contractAddress = common.HexToAddress(contractAddress).Hex()
+	if _, err := uc.nftRepo.GetCollectionByAddress(contractAddress); err != nil {
+		return notFoundAs(err, domain.ErrCollectionNotFound)
+	}
+	return uc.watchlistRepo.AddWatch(&domain.CollectionWatch{Address: address, ContractAddress: contractAddress})
+}
+
+func (uc *WatchlistUseCase) RemoveWatch(address, contractAddress string) error {
+	rows, err := uc.watchlistRepo.RemoveWatch(address, common.HexToAddress(contractAddress).Hex())
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrWatchNotFound
+	}
+	return nil
+}
+
+// 获取关注的NFT系列
+func (uc *WatchlistUseCase) GetWatchedCollections(address string) ([]domain.NFTCollection, error) {
+	watches, err := uc.watchlistRepo.GetWatchesByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	collections := make([]domain.NFTCollection, 0, len(watches))
+	for _, watch := range watches {
+		collection, err := uc.nftRepo.GetCollectionByAddress(watch.ContractAddress)
+		if err != nil {
+			continue
+		}
+		collections = append(collections, *collection)
+	}
+	return collections, nil
+}
+
+// 获取关注系列中的新挂单、降价和成交，beforeID 用于向前翻页
+func (uc *WatchlistUseCase) GetFeed(address string, types []string, beforeID uint, limit int) ([]domain.MarketActivityView, error) {
+	for _, activityType := range types {
+		switch activityType {
+		case ActivityTypeListing, ActivityTypePriceDrop, ActivityTypeSale:
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidActivityType, activityType)
+		}
+	}
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+	if limit > maxFeedLimit {
+		limit = maxFeedLimit
+	}
+
+	watches, err := uc.watchlistRepo.GetWatchesByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(watches) == 0 {
+		return []domain.MarketActivityView{}, nil
+	}
+	collections := make([]string, len(watches))
+	for i, watch := range watches {
+		collections[i] = watch.ContractAddress
+	}
+
+	activities, err := uc.watchlistRepo.GetActivities(collections, types, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return uc.describeActivities(activities), nil
+}
+
+// 为市场动态附加NFT、支付代币、格式化价格和买卖双方资料
+func (uc *WatchlistUseCase) describeActivities(activities []domain.MarketActivity) []domain.MarketActivityView {
+	tokenAddresses := make([]string, len(activities))
+	parties := make([]string, 0, len(activities)*2)
+	for i, activity := range activities {
+		tokenAddresses[i] = activity.TokenAddress
+		parties = append(parties, activity.Seller, activity.Buyer)
+	}
+	tokens := uc.tokenUC.lookupTokens(tokenAddresses)
+	quotes := uc.tokenUC.oracle.USDPrices(tokenAddresses)
+	profiles := uc.profileUC.Summaries(parties...)
+
+	nfts := make(map[string]*domain.NFT)
+	views := make([]domain.MarketActivityView, len(activities))
+	for i, activity := range activities {
+		key := fmt.Sprintf("%s:%d", strings.ToLower(activity.NFTContractAddress), activity.TokenID)
+		nft, exists := nfts[key]
+		if !exists {
+			if found, err := uc.nftRepo.GetByTokenID(activity.NFTContractAddress, activity.TokenID); err == nil {
+				nft = found
+			}
+			nfts[key] = nft
+		}
+
+		views[i] = domain.MarketActivityView{
+			MarketActivity:         activity,
+			NFT:                    nft,
+			PriceFormatted:         activity.Price,
+			PreviousPriceFormatted: activity.PreviousPrice,
+			SellerProfile:          profiles[strings.ToLower(activity.Seller)],
+		}
+		if activity.Buyer != "" {
+			views[i].BuyerProfile = profiles[strings.ToLower(activity.Buyer)]
+		}
+		if token, exists := tokens[strings.ToLower(activity.TokenAddress)]; exists {
+			views[i].PaymentToken = token
+			views[i].PriceFormatted = FormatTokenAmount(activity.Price, token.Decimals)
+			if activity.PreviousPrice != "" {
+				views[i].PreviousPriceFormatted = FormatTokenAmount(activity.PreviousPrice, token.Decimals)
+			}
+			views[i].PriceUSD = quoteToUSD(quotes[strings.ToLower(activity.TokenAddress)], activity.Price, token.Decimals)
+		}
+	}
+	return views
+}
+
+// 记录新挂单，卖家以更低价格重新挂单同一NFT时记为降价
+func (uc *WatchlistUseCase) recordListing(reference, nftAddress string, tokenID uint, tokenAddress, price, seller string) {
+	activity := &domain.MarketActivity{
+		Type:               ActivityTypeListing,
+		Reference:          reference,
+		NFTContractAddress: nftAddress,
+		TokenID:            tokenID,
+		TokenAddress:       tokenAddress,
+		Price:              price,
+		Seller:             seller,
+		CreatedAt:          time.Now(),
+	}
+
+	previous, err := uc.watchlistRepo.GetLatestListingActivity(nftAddress, tokenID, tokenAddress, seller)
+	if err == nil {
+		previousPrice, ok1 := new(big.Int).SetString(previous.Price, 10)
+		currentPrice, ok2 := new(big.Int).SetString(price, 10)
+		if ok1 && ok2 && currentPrice.Cmp(previousPrice) < 0 {
+			activity.Type = ActivityTypePriceDrop
+			activity.PreviousPrice = previous.Price
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("获取此前挂单动态失败: %v", err)
+	}
+
+	if err := uc.watchlistRepo.SaveActivity(activity); err != nil {
+		log.Printf("保存挂单动态失败: %v", err)
+	}
+}
+
+// 记录成交
+func (uc *WatchlistUseCase) recordSale(reference, nftAddress string, tokenID uint, tokenAddress, price, seller, buyer, transactionHash string, soldAt time.Time) {
+	activity := &domain.MarketActivity{
+		Type:               ActivityTypeSale,
+		Reference:          reference,
+		NFTContractAddress: nftAddress,
+		TokenID:            tokenID,
+		TokenAddress:       tokenAddress,
+		Price:              price,
+		Seller:             seller,
+		Buyer:              buyer,
+		TransactionHash:    transactionHash,
+		CreatedAt:          soldAt,
+	}
+	if err := uc.watchlistRepo.SaveActivity(activity); err != nil {
+		log.Printf("保存成交动态失败: %v", err)
+	}
+}
+
+// 链上订单动态的引用，使用链上订单索引
+func orderActivityReference(orderID uint) string {
+	return fmt.Sprintf("order:%d", orderID-1)
+}
+
+func listingActivityReference(hash string) string {
+	return "listing:" + hash
+}
+
+// 解析以逗号分隔的动态类型
+func ParseActivityTypes(raw string) []string {
+	types := make([]string, 0)
+	for _, activityType := range strings.Split(raw, ",") {
+		if activityType = strings.TrimSpace(activityType); activityType != "" {
+			types = append(types, activityType)
+		}
+	}
+	return types
+}
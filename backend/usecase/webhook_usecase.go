@@ -0,0 +1,565 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend/domain"
+	"backend/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// 推送的事件类型
+const (
+	WebhookEventOrderCreated    = "order.created"
+	WebhookEventOrderCancelled  = "order.cancelled"
+	WebhookEventOrderFulfilled  = "order.fulfilled"
+	WebhookEventNFTMint         = "nft.mint"
+	WebhookEventNFTTransfer     = "nft.transfer"
+	WebhookEventMetadataUpdated = "nft.metadata_updated"
+	WebhookEventAlertTriggered  = "alert.triggered"
+)
+
+var webhookEventTypes = []string{
+	WebhookEventOrderCreated,
+	WebhookEventOrderCancelled,
+	WebhookEventOrderFulfilled,
+	WebhookEventNFTMint,
+	WebhookEventNFTTransfer,
+	WebhookEventMetadataUpdated,
+	WebhookEventAlertTriggered,
+}
+
+// 投递状态
+const (
+	WebhookDeliveryPending   = 0
+	WebhookDeliverySucceeded = 1
+	WebhookDeliveryDead      = 2
+)
+
+var webhookDeliveryStatuses = map[string]uint{
+	"pending":   WebhookDeliveryPending,
+	"succeeded": WebhookDeliverySucceeded,
+	"dead":      WebhookDeliveryDead,
+}
+
+const (
+	webhookPollInterval   = 5 * time.Second
+	webhookBatchSize      = 20
+	webhookRequestTimeout = 10 * time.Second
+	webhookResolveTimeout = 5 * time.Second
+	webhookDeliveryLimit  = 100
+	// 重放时立即投递期间投递协程不会取到该记录；超过该时间仍未更新（如进程退出）时由投递协程接手
+	webhookReplayLease = webhookResolveTimeout + webhookRequestTimeout
+)
+
+var ErrInvalidWebhook = domain.NewError(domain.ErrKindInvalid, "invalid_webhook", "无效的Webhook")
+
+var errWebhookInternalAddress = errors.New("推送地址解析到了内部地址")
+
+// 运营商级 NAT 和 0.0.0.0/8 不在 netip 的判断方法中，单独列出
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// 推送地址只能是公网地址，不能指向本机、内网、链路本地（如云服务器元数据 169.254.169.254）或未指定地址，
+// 防止借推送请求访问内部服务
+func isPublicWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// 在建立连接前检查实际连接的IP，防止注册后通过 DNS 重新绑定指向内部地址
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isPublicWebhookAddr(addr) {
+		return fmt.Errorf("%w: %s", errWebhookInternalAddress, host)
+	}
+	return nil
+}
+
+// 投递使用的客户端：不走代理、连接时检查IP、不跟随重定向（3xx 按失败处理）
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: webhookRequestTimeout, Control: webhookDialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookRequestTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// 解析推送地址的主机名，任一解析结果不是公网地址时拒绝
+func checkWebhookHost(host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicWebhookAddr(addr) {
+			return fmt.Errorf("%w: URL不能指向本机或内网地址", ErrInvalidWebhook)
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: 无法解析主机名 %s", ErrInvalidWebhook, host)
+	}
+	for _, addr := range addrs {
+		if !isPublicWebhookAddr(addr) {
+			return fmt.Errorf("%w: URL不能指向本机或内网地址", ErrInvalidWebhook)
+		}
+	}
+	return nil
+}
+
+// WebhookRequest 表示创建或更新推送地址的参数
+type WebhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	Events     []string `json:"events"`
+	Collection string   `json:"collection"`
+	Address    string   `json:"address"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookPayload 是推送请求体
+type WebhookPayload struct {
+	ID        string
+	Type      string
+	CreatedAt time.Time
+	Data      interface{}
+}
+
+// OrderFulfilledData 是 order.fulfilled 事件的数据
+type OrderFulfilledData struct {
+	Order           *domain.OrderView
+	Buyer           string
+	TransactionHash string
+}
+
+// webhookEvent 是待分发的事件，Collection 和 Addresses 用于匹配推送地址的过滤条件
+type webhookEvent struct {
+	Type       string
+	Owner      string // 非空时只推送给该用户的推送地址
+	Collection string
+	Addresses  []string
+	Data       interface{}
+}
+
+type WebhookUseCase struct {
+	webhookRepo *repository.WebhookRepository
+	client      *http.Client
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewWebhookUseCase(webhookRepo *repository.WebhookRepository, nftUC *NFTUseCase, marketUC *MarketUseCase) *WebhookUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	uc := &WebhookUseCase{
+		webhookRepo: webhookRepo,
+		client:      newWebhookClient(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	nftUC.webhookUC = uc
+	marketUC.webhookUC = uc
+
+	return uc
+}
+
+// Start 启动投递协程，需在所有用例构造完成后调用
+func (uc *WebhookUseCase) Start() {
+	go uc.startWorker()
+}
+
+func (uc *WebhookUseCase) Close() {
+	uc.cancel()
+}
+
+// 校验并规范化推送地址参数
+func (uc *WebhookUseCase) applyRequest(webhook *domain.Webhook, req *WebhookRequest) error {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: URL必须是http或https地址", ErrInvalidWebhook)
+	}
+	if err := checkWebhookHost(target.Hostname()); err != nil {
+		return err
+	}
+	for _, event := range req.Events {
+		if !containsFold(webhookEventTypes, event) {
+			return fmt.Errorf("%w: 未知的事件类型 %s", ErrInvalidWebhook, event)
+		}
+	}
+	if req.Collection != "" && !common.IsHexAddress(req.Collection) {
+		return fmt.Errorf("%w: 无效的合约地址", ErrInvalidWebhook)
+	}
+	if req.Address != "" && !common.IsHexAddress(req.Address) {
+		return fmt.Errorf("%w: 无效的地址", ErrInvalidWebhook)
+	}
+
+	webhook.URL = target.String()
+	webhook.Events = strings.ToLower(strings.Join(req.Events, ","))
+	webhook.Collection = ""
+	if req.Collection != "" {
+		webhook.Collection = common.HexToAddress(req.Collection).Hex()
+	}
+	webhook.Address = ""
+	if req.Address != "" {
+		webhook.Address = common.HexToAddress(req.Address).Hex()
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	return nil
+}
+
+// 注册推送地址，返回的签名密钥只在创建和轮换时可见
+func (uc *WebhookUseCase) CreateWebhook(owner string, req *WebhookRequest) (*domain.Webhook, string, error) {
+	webhook := &domain.Webhook{
+		Owner:  common.HexToAddress(owner).Hex(),
+		Active: true,
+	}
+	if err := uc.applyRequest(webhook, req); err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("生成签名密钥失败: %w", err)
+	}
+	webhook.Secret = secret
+
+	if err := uc.webhookRepo.CreateWebhook(webhook); err != nil {
+		return nil, "", err
+	}
+	return webhook, secret, nil
+}
+
+func (uc *WebhookUseCase) GetWebhooks(owner string) ([]domain.Webhook, error) {
+	return uc.webhookRepo.GetWebhooksByOwner(common.HexToAddress(owner).Hex())
+}
+
+// 获取属于 owner 的推送地址，不属于时按不存在处理
+func (uc *WebhookUseCase) GetWebhook(owner string, id uint) (*domain.Webhook, error) {
+	webhook, err := uc.webhookRepo.GetWebhookByID(id)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrWebhookNotFound)
+	}
+	if !strings.EqualFold(webhook.Owner, owner) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (uc *WebhookUseCase) UpdateWebhook(owner string, id uint, req *WebhookRequest) (*domain.Webhook, error) {
+	webhook, err := uc.GetWebhook(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.applyRequest(webhook, req); err != nil {
+		return nil, err
+	}
+	if err := uc.webhookRepo.SaveWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (uc *WebhookUseCase) DeleteWebhook(owner string, id uint) error {
+	if _, err := uc.GetWebhook(owner, id); err != nil {
+		return err
+	}
+	return uc.webhookRepo.DeleteWebhook(id)
+}
+
+// 轮换签名密钥，旧密钥立即失效
+func (uc *WebhookUseCase) RotateSecret(owner string, id uint) (*domain.Webhook, string, error) {
+	webhook, err := uc.GetWebhook(owner, id)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("生成签名密钥失败: %w", err)
+	}
+	webhook.Secret = secret
+	if err := uc.webhookRepo.SaveWebhook(webhook); err != nil {
+		return nil, "", err
+	}
+	return webhook, secret, nil
+}
+
+// 获取投递记录，status 为逗号分隔的 pending/succeeded/dead，为空时返回全部
+func (uc *WebhookUseCase) GetDeliveries(owner string, id uint, status string) ([]domain.WebhookDelivery, error) {
+	if _, err := uc.GetWebhook(owner, id); err != nil {
+		return nil, err
+	}
+	statuses := make([]uint, 0)
+	for _, name := range strings.Split(status, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		value, exists := webhookDeliveryStatuses[strings.ToLower(name)]
+		if !exists {
+			return nil, fmt.Errorf("%w: 未知的投递状态 %s", ErrInvalidWebhook, name)
+		}
+		statuses = append(statuses, value)
+	}
+	return uc.webhookRepo.GetDeliveries(id, statuses, webhookDeliveryLimit)
+}
+
+// 重放一次投递：以相同的事件内容创建新的投递并立即执行。
+// 新投递的下次投递时间设在立即投递结束之后，投递协程不会重复发送
+func (uc *WebhookUseCase) ReplayDelivery(owner string, id, deliveryID uint) (*domain.WebhookDelivery, error) {
+	webhook, err := uc.GetWebhook(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	original, err := uc.webhookRepo.GetDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, notFoundAs(err, domain.ErrDeliveryNotFound)
+	}
+	if original.WebhookID != webhook.ID {
+		return nil, domain.ErrDeliveryNotFound
+	}
+
+	replay := domain.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: time.Now().Add(webhookReplayLease),
+		ReplayOf:      original.ID,
+	}
+	deliveries := []domain.WebhookDelivery{replay}
+	if err := uc.webhookRepo.CreateDeliveries(deliveries); err != nil {
+		return nil, err
+	}
+	uc.deliver(webhook, &deliveries[0])
+	return uc.webhookRepo.GetDeliveryByID(deliveries[0].ID)
+}
+
+// 按过滤条件为匹配的推送地址创建投递，uc 为 nil 时不做任何事
+func (uc *WebhookUseCase) dispatch(event webhookEvent) {
+	if uc == nil {
+		return
+	}
+	webhooks, err := uc.webhookRepo.GetActiveWebhooks()
+	if err != nil {
+		log.Printf("获取Webhook失败: %v", err)
+		return
+	}
+
+	matched := make([]domain.Webhook, 0)
+	for _, webhook := range webhooks {
+		if webhookMatches(&webhook, &event) {
+			matched = append(matched, webhook)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	eventID, err := randomHex(16)
+	if err != nil {
+		log.Printf("生成事件ID失败: %v", err)
+		return
+	}
+	payload, err := json.Marshal(WebhookPayload{
+		ID:        eventID,
+		Type:      event.Type,
+		CreatedAt: time.Now().UTC(),
+		Data:      event.Data,
+	})
+	if err != nil {
+		log.Printf("序列化Webhook事件失败: %v", err)
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]domain.WebhookDelivery, 0, len(matched))
+	for _, webhook := range matched {
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       eventID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	if err := uc.webhookRepo.CreateDeliveries(deliveries); err != nil {
+		log.Printf("保存Webhook投递失败: %v", err)
+	}
+}
+
+func webhookMatches(webhook *domain.Webhook, event *webhookEvent) bool {
+	if event.Owner != "" && !strings.EqualFold(webhook.Owner, event.Owner) {
+		return false
+	}
+	if webhook.Events != "" && !containsFold(strings.Split(webhook.Events, ","), event.Type) {
+		return false
+	}
+	if webhook.Collection != "" && !strings.EqualFold(webhook.Collection, event.Collection) {
+		return false
+	}
+	if webhook.Address != "" && !containsFold(event.Addresses, webhook.Address) {
+		return false
+	}
+	return true
+}
+
+func (uc *WebhookUseCase) startWorker() {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			uc.drainQueue()
+		case <-uc.ctx.Done():
+			return
+		}
+	}
+}
+
+// 投递所有已到期的事件
+func (uc *WebhookUseCase) drainQueue() {
+	webhooks := make(map[uint]*domain.Webhook)
+	for {
+		deliveries, err := uc.webhookRepo.GetDueDeliveries(time.Now(), webhookBatchSize)
+		if err != nil {
+			log.Printf("获取待投递事件失败: %v", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		for i := range deliveries {
+			if uc.ctx.Err() != nil {
+				return
+			}
+			delivery := &deliveries[i]
+			webhook, exists := webhooks[delivery.WebhookID]
+			if !exists {
+				if webhook, err = uc.webhookRepo.GetWebhookByID(delivery.WebhookID); err != nil {
+					if !errors.Is(err, gorm.ErrRecordNotFound) {
+						log.Printf("获取Webhook失败 (ID: %d): %v", delivery.WebhookID, err)
+						return
+					}
+					webhook = nil
+				}
+				webhooks[delivery.WebhookID] = webhook
+			}
+			if webhook == nil || !webhook.Active {
+				uc.markFailed(delivery, 0, "Webhook已停用或已删除", true)
+				continue
+			}
+			uc.deliver(webhook, delivery)
+		}
+	}
+}
+
+// 发送一次投递并记录结果，失败时按指数退避安排下一次
+func (uc *WebhookUseCase) deliver(webhook *domain.Webhook, delivery *domain.WebhookDelivery) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(uc.ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		uc.markFailed(delivery, 0, err.Error(), true)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", strconv.FormatUint(uint64(webhook.ID), 10))
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.EventID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := uc.client.Do(req)
+	if err != nil {
+		uc.markFailed(delivery, 0, err.Error(), false)
+		return
+	}
+	// 只记录状态码，响应内容不保存也不返回给用户
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		uc.markFailed(delivery, resp.StatusCode, fmt.Sprintf("响应状态码 %d", resp.StatusCode), false)
+		return
+	}
+
+	now := time.Now()
+	if err := uc.webhookRepo.UpdateDelivery(delivery.ID, map[string]interface{}{
+		"status":          WebhookDeliverySucceeded,
+		"attempts":        delivery.Attempts + 1,
+		"response_status": resp.StatusCode,
+		"last_error":      "",
+		"delivered_at":    &now,
+	}); err != nil {
+		log.Printf("更新Webhook投递状态失败 (ID: %d): %v", delivery.ID, err)
+	}
+}
+
+func (uc *WebhookUseCase) markFailed(delivery *domain.WebhookDelivery, responseStatus int, lastError string, dead bool) {
+	attempts := delivery.Attempts + 1
+	if attempts >= retryMaxAttempts {
+		dead = true
+	}
+	status := WebhookDeliveryPending
+	if dead {
+		status = WebhookDeliveryDead
+		log.Printf("Webhook投递已放弃 (ID: %d, Webhook: %d): %s", delivery.ID, delivery.WebhookID, lastError)
+	}
+	if err := uc.webhookRepo.UpdateDelivery(delivery.ID, map[string]interface{}{
+		"status":          status,
+		"attempts":        attempts,
+		"next_attempt_at": time.Now().Add(retryDelay(attempts)),
+		"response_status": responseStatus,
+		"last_error":      lastError,
+	}); err != nil {
+		log.Printf("更新Webhook投递状态失败 (ID: %d): %v", delivery.ID, err)
+	}
+}
+
+// 计算推送签名：HMAC-SHA256(secret, timestamp + "." + body) 的十六进制
+func SignWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
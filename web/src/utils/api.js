@@ -0,0 +1,6 @@
+// 后端接口地址，可通过 VUE_APP_API_BASE_URL 配置，如 https://example.com/api
+// 未配置时使用当前页面所在主机的 8081 端口
+const configuredBaseURL = (process.env.VUE_APP_API_BASE_URL || '').replace(/^['"](.*)['"]$/, '$1');
+
+export const API_BASE_URL = (configuredBaseURL ||
+  `${window.location.protocol}//${window.location.hostname}:8081/api`).replace(/\/+$/, '');